@@ -2,80 +2,327 @@
 package shared
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math/rand/v2"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // AbuseIPDBClient handles interactions with the AbuseIPDB API
 type AbuseIPDBClient struct {
 	apiKey     string
-	httpClient *http.Client
+	httpClient HTTPDoer
 	baseURL    string
+
+	// ownsClient and ownsTransport record whether httpClient and its
+	// transport were created for this client, so options may modify them
+	// without affecting other users; see client and transport.
+	ownsClient    bool
+	ownsTransport bool
+
+	optionErr error // first error reported by an option
+
+	maxAttempts    int
+	retryBaseDelay time.Duration
+
+	signingSecret []byte // see WithRequestSigning
+
+	checkPrivateIPs bool // see WithPrivateIPBypass
+
+	userAgent string // see WithUserAgent
+}
+
+// AbuseIPDBOption configures an AbuseIPDBClient
+type AbuseIPDBOption func(*AbuseIPDBClient)
+
+// WithHTTPDoer sends API requests through the given HTTP client
+func WithHTTPDoer(d HTTPDoer) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		c.httpClient = d
+		c.ownsClient, c.ownsTransport = false, false
+	}
+}
+
+// defaultUserAgent identifies the package to AbuseIPDB unless WithUserAgent is given
+const defaultUserAgent = "azlo-validator-shared/" + Version + " (+https://github.com/nibbabob/azlo-validator-shared)"
+
+// WithUserAgent sets the User-Agent header sent with API requests, so
+// AbuseIPDB can identify and contact the deployment behind them. Empty
+// values are ignored.
+func WithUserAgent(ua string) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// Bounds and default for the AbuseIPDB request timeout
+const (
+	defaultHTTPTimeout = 10 * time.Second
+	minHTTPTimeout     = time.Second
+	maxHTTPTimeout     = 120 * time.Second
+)
+
+// WithHTTPTimeout sets the timeout of each API request attempt. d must be
+// between 1s and 120s; otherwise the option is rejected (see NewAbuseIPDBClientE).
+func WithHTTPTimeout(d time.Duration) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		if d < minHTTPTimeout || d > maxHTTPTimeout {
+			c.fail(fmt.Errorf("WithHTTPTimeout: timeout %v is outside %v to %v", d, minHTTPTimeout, maxHTTPTimeout))
+			return
+		}
+		client, err := c.client("WithHTTPTimeout")
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		client.Timeout = d
+	}
+}
+
+// WithHTTPTransport sends API requests through t, e.g. a transport configured
+// for a corporate proxy. The request timeout still applies; see WithHTTPTimeout.
+func WithHTTPTransport(t http.RoundTripper) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		client, err := c.client("WithHTTPTransport")
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		client.Transport = t
+		c.ownsTransport = false
+	}
+}
+
+// WithPrivateIPBypass controls whether private, loopback and other reserved
+// IPs skip the API call. AbuseIPDB has no data on them, so by default they are
+// reported as whitelisted with a score of 0 without a request.
+func WithPrivateIPBypass(enabled bool) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		c.checkPrivateIPs = !enabled
+	}
+}
+
+// maxRetryDelay caps the backoff between retry attempts
+const maxRetryDelay = 30 * time.Second
+
+// WithRetry retries transient failures (network errors and 429/5xx responses)
+// up to maxAttempts times in total, using full-jitter exponential backoff
+// starting from baseDelay. Requests cut short by their context aren't retried,
+// and the wait between attempts ends when the context is done.
+func WithRetry(maxAttempts int, baseDelay time.Duration) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		c.maxAttempts = maxAttempts
+		c.retryBaseDelay = baseDelay
+	}
 }
 
 // AbuseIPDBResponse represents the response from AbuseIPDB API
 type AbuseIPDBResponse struct {
 	Data struct {
-		IPAddress            string    `json:"ipAddress"`
-		IsPublic             bool      `json:"isPublic"`
-		IPVersion            int       `json:"ipVersion"`
-		IsWhitelisted        bool      `json:"isWhitelisted"`
-		AbuseConfidenceScore int       `json:"abuseConfidenceScore"`
-		CountryCode          string    `json:"countryCode"`
-		CountryName          string    `json:"countryName"`
-		UsageType            string    `json:"usageType"`
-		ISP                  string    `json:"isp"`
-		Domain               string    `json:"domain"`
-		TotalReports         int       `json:"totalReports"`
-		NumDistinctUsers     int       `json:"numDistinctUsers"`
-		LastReportedAt       time.Time `json:"lastReportedAt"`
+		IPAddress            string        `json:"ipAddress"`
+		IsPublic             bool          `json:"isPublic"`
+		IPVersion            int           `json:"ipVersion"`
+		IsWhitelisted        bool          `json:"isWhitelisted"`
+		AbuseConfidenceScore int           `json:"abuseConfidenceScore"`
+		CountryCode          string        `json:"countryCode"`
+		CountryName          string        `json:"countryName"`
+		UsageType            string        `json:"usageType"`
+		ISP                  string        `json:"isp"`
+		Domain               string        `json:"domain"`
+		TotalReports         int           `json:"totalReports"`
+		NumDistinctUsers     int           `json:"numDistinctUsers"`
+		LastReportedAt       time.Time     `json:"lastReportedAt"`
+		Reports              []AbuseReport `json:"reports"`
 	} `json:"data"`
 }
 
+// AbuseReport is a single abuse report returned by AbuseIPDB in verbose mode
+type AbuseReport struct {
+	ReportedAt          time.Time `json:"reportedAt"`
+	Comment             string    `json:"comment"`
+	Categories          []int     `json:"categories"`
+	ReporterCountryCode string    `json:"reporterCountryCode"`
+}
+
+// AbuseIPDBErrorResponse is the body AbuseIPDB sends with non-200 responses
+type AbuseIPDBErrorResponse struct {
+	Errors []AbuseIPDBError `json:"errors"`
+}
+
+// AbuseIPDBError is a single error reported by the AbuseIPDB API
+type AbuseIPDBError struct {
+	Status string            `json:"status"`
+	Detail string            `json:"detail"`
+	Source map[string]string `json:"source,omitempty"` // e.g. {"parameter": "ipAddress"}
+}
+
+// UnmarshalJSON accepts the status as either a JSON string or number, since
+// the API has sent both.
+func (e *AbuseIPDBError) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Status json.RawMessage   `json:"status"`
+		Detail string            `json:"detail"`
+		Source map[string]string `json:"source"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = AbuseIPDBError{
+		Status: strings.Trim(string(raw.Status), `"`),
+		Detail: raw.Detail,
+		Source: raw.Source,
+	}
+	return nil
+}
+
+// parseAbuseIPDBErrors extracts the structured errors from an error response
+// body, returning nil if it isn't in the documented format.
+func parseAbuseIPDBErrors(body []byte) []AbuseIPDBError {
+	var resp AbuseIPDBErrorResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil
+	}
+	return resp.Errors
+}
+
 // IPReputationResult contains the result of IP reputation check
 type IPReputationResult struct {
-	IPAddress            string    `json:"ip_address"`
-	IsWhitelisted        bool      `json:"is_whitelisted"`
-	AbuseConfidenceScore int       `json:"abuse_confidence_score"`
-	TotalReports         int       `json:"total_reports"`
-	CountryCode          string    `json:"country_code"`
-	ISP                  string    `json:"isp"`
-	Domain               string    `json:"domain"`
-	LastReportedAt       time.Time `json:"last_reported_at,omitempty"`
-	CheckedAt            time.Time `json:"checked_at"`
-	Error                string    `json:"error,omitempty"`
-}
-
-// NewAbuseIPDBClient creates a new AbuseIPDB client
-func NewAbuseIPDBClient(apiKey string) *AbuseIPDBClient {
-	return &AbuseIPDBClient{
+	IPAddress            string        `json:"ip_address"`
+	IsWhitelisted        bool          `json:"is_whitelisted"`
+	AbuseConfidenceScore int           `json:"abuse_confidence_score"`
+	TotalReports         int           `json:"total_reports"`
+	NumDistinctUsers     int           `json:"num_distinct_users"`
+	CountryCode          string        `json:"country_code"`
+	ISP                  string        `json:"isp"`
+	Domain               string        `json:"domain"`
+	LastReportedAt       time.Time     `json:"last_reported_at,omitempty"`
+	CheckedAt            time.Time     `json:"checked_at"`
+	Error                string        `json:"error,omitempty"`
+	RetryAttempts        int           `json:"retry_attempts,omitempty"`
+	Reports              []AbuseReport `json:"reports,omitempty"`
+}
+
+// GetRecentReports returns the abuse reports filed at or after since
+func (r *IPReputationResult) GetRecentReports(since time.Time) []AbuseReport {
+	var recent []AbuseReport
+	for _, report := range r.Reports {
+		if !report.ReportedAt.Before(since) {
+			recent = append(recent, report)
+		}
+	}
+	return recent
+}
+
+// NewAbuseIPDBClient creates a new AbuseIPDB client. Options that can't be
+// applied, e.g. WithProxy combined with a WithHTTPDoer that isn't an
+// *http.Client, are logged and skipped; use NewAbuseIPDBClientE to get the error.
+func NewAbuseIPDBClient(apiKey string, opts ...AbuseIPDBOption) *AbuseIPDBClient {
+	c := newAbuseIPDBClient(apiKey, opts)
+	if c.optionErr != nil {
+		log.Printf("Ignoring AbuseIPDB client option: %v", c.optionErr)
+	}
+	return c
+}
+
+// NewAbuseIPDBClientE is NewAbuseIPDBClient, but fails if an option can't be applied.
+func NewAbuseIPDBClientE(apiKey string, opts ...AbuseIPDBOption) (*AbuseIPDBClient, error) {
+	c := newAbuseIPDBClient(apiKey, opts)
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+	return c, nil
+}
+
+// newAbuseIPDBClient creates a client and applies opts, recording the first
+// option error in optionErr.
+func newAbuseIPDBClient(apiKey string, opts []AbuseIPDBOption) *AbuseIPDBClient {
+	c := &AbuseIPDBClient{
 		apiKey:  apiKey,
 		baseURL: "https://api.abuseipdb.com/api/v2",
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: defaultHTTPTimeout,
 		},
+		ownsClient:  true,
+		maxAttempts: 1,
+		userAgent:   defaultUserAgent,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// fail records an option error for NewAbuseIPDBClientE to return.
+func (c *AbuseIPDBClient) fail(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
 	}
 }
 
-// CheckIP checks the reputation of an IP address using AbuseIPDB
+// CheckIP checks the reputation of an IP address using AbuseIPDB.
+//
+// Failures are returned as typed errors: invalid addresses match
+// ErrInvalidIPAddress and non-200 responses are *ErrAbuseIPDBAPIError. For
+// compatibility with callers written before the typed errors, the result is
+// never nil: on failure it carries the error message in Error, as it used to.
 func (c *AbuseIPDBClient) CheckIP(ipAddress string) (*IPReputationResult, error) {
-	// Validate IP address
-	if net.ParseIP(ipAddress) == nil {
+	return c.CheckIPContext(context.Background(), ipAddress)
+}
+
+// CheckIPContext is like CheckIP but cancels the request when ctx is done.
+// Errors include the request ID from ctx, if any.
+func (c *AbuseIPDBClient) CheckIPContext(ctx context.Context, ipAddress string) (*IPReputationResult, error) {
+	result, err := c.checkIP(ctx, ipAddress)
+	if err != nil {
+		err = withRequestID(ctx, err)
 		return &IPReputationResult{
 			IPAddress: ipAddress,
-			Error:     "invalid IP address format",
+			Error:     err.Error(),
 			CheckedAt: time.Now(),
+		}, err
+	}
+	return result, nil
+}
+
+// checkIP performs the AbuseIPDB lookup for CheckIPContext.
+func (c *AbuseIPDBClient) checkIP(ctx context.Context, ipAddress string) (*IPReputationResult, error) {
+	// Validate IP address
+	parsed := net.ParseIP(ipAddress)
+	if parsed == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIPAddress, ipAddress)
+	}
+
+	// Reserved addresses aren't in AbuseIPDB's database
+	if !c.checkPrivateIPs && IsPrivateOrReservedIP(parsed) {
+		return &IPReputationResult{
+			IPAddress:            ipAddress,
+			IsWhitelisted:        true,
+			AbuseConfidenceScore: 0,
+			CheckedAt:            time.Now(),
 		}, nil
 	}
 
 	// Create the request
 	url := fmt.Sprintf("%s/check", c.baseURL)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -83,6 +330,7 @@ func (c *AbuseIPDBClient) CheckIP(ipAddress string) (*IPReputationResult, error)
 	// Set headers
 	req.Header.Set("Key", c.apiKey)
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
 
 	// Set query parameters
 	q := req.URL.Query()
@@ -91,44 +339,39 @@ func (c *AbuseIPDBClient) CheckIP(ipAddress string) (*IPReputationResult, error)
 	q.Add("verbose", "")
 	req.URL.RawQuery = q.Encode()
 
-	// Make the request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return &IPReputationResult{
-			IPAddress: ipAddress,
-			Error:     fmt.Sprintf("HTTP request failed: %v", err),
-			CheckedAt: time.Now(),
-		}, nil
-	}
-	defer resp.Body.Close()
+	// Make the request, retrying transient failures
+	var body []byte
+	attempts := 0
+	for attempts < c.maxAttempts {
+		if attempts > 0 {
+			timer := time.NewTimer(c.retryDelay(attempts))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		attempts++
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return &IPReputationResult{
-			IPAddress: ipAddress,
-			Error:     fmt.Sprintf("failed to read response: %v", err),
-			CheckedAt: time.Now(),
-		}, nil
+		var retryable bool
+		body, retryable, err = c.doRequest(req)
+		if err == nil || !retryable {
+			break
+		}
 	}
 
-	// Handle HTTP errors
-	if resp.StatusCode != http.StatusOK {
-		return &IPReputationResult{
-			IPAddress: ipAddress,
-			Error:     fmt.Sprintf("API error: %d - %s", resp.StatusCode, string(body)),
-			CheckedAt: time.Now(),
-		}, nil
+	if err != nil {
+		if attempts > 1 {
+			return nil, fmt.Errorf("after %d attempts: %w", attempts, err)
+		}
+		return nil, err
 	}
 
 	// Parse JSON response
 	var abuseResp AbuseIPDBResponse
 	if err := json.Unmarshal(body, &abuseResp); err != nil {
-		return &IPReputationResult{
-			IPAddress: ipAddress,
-			Error:     fmt.Sprintf("failed to parse response: %v", err),
-			CheckedAt: time.Now(),
-		}, nil
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Convert to our result format
@@ -137,45 +380,253 @@ func (c *AbuseIPDBClient) CheckIP(ipAddress string) (*IPReputationResult, error)
 		IsWhitelisted:        abuseResp.Data.IsWhitelisted,
 		AbuseConfidenceScore: abuseResp.Data.AbuseConfidenceScore,
 		TotalReports:         abuseResp.Data.TotalReports,
+		NumDistinctUsers:     abuseResp.Data.NumDistinctUsers,
 		CountryCode:          abuseResp.Data.CountryCode,
 		ISP:                  abuseResp.Data.ISP,
 		Domain:               abuseResp.Data.Domain,
 		LastReportedAt:       abuseResp.Data.LastReportedAt,
 		CheckedAt:            time.Now(),
+		RetryAttempts:        attempts - 1,
+		Reports:              abuseResp.Data.Reports,
 	}
 
 	return result, nil
 }
 
-// GetMailServerIPs extracts IP addresses for mail servers of a domain
-func GetMailServerIPs(domain string) ([]string, error) {
+// doRequest performs a single HTTP request and reports whether a failure is worth retrying
+func (c *AbuseIPDBClient) doRequest(req *http.Request) ([]byte, bool, error) {
+	// Sign each attempt separately so retries carry a fresh timestamp
+	if c.signingSecret != nil {
+		if err := signRequest(req, c.signingSecret); err != nil {
+			return nil, false, err
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The caller gave up, so another attempt would fail the same way
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, false, fmt.Errorf("HTTP request failed: %w", err)
+		}
+		var proxyErr *ErrProxyUnreachable
+		if errors.As(err, &proxyErr) {
+			return nil, true, proxyErr
+		}
+		return nil, true, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Handle HTTP errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, isRetryableStatus(resp.StatusCode), &ErrAbuseIPDBAPIError{
+			StatusCode: resp.StatusCode,
+			Errors:     parseAbuseIPDBErrors(body),
+			Body:       string(body),
+		}
+	}
+
+	return body, false, nil
+}
+
+// retryDelay returns a full-jitter exponential backoff delay for the given retry attempt
+func (c *AbuseIPDBClient) retryDelay(attempt int) time.Duration {
+	backoff := c.retryBaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > maxRetryDelay {
+		backoff = maxRetryDelay
+	}
+	return time.Duration(rand.Int64N(int64(backoff) + 1))
+}
+
+// isRetryableStatus reports whether an HTTP status code indicates a transient failure
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// MailServerIP is an address of one of a domain's mail servers
+type MailServerIP struct {
+	IP      net.IP `json:"ip"`
+	Version int    `json:"version"` // 4 or 6
+	MXHost  string `json:"mx_host"` // MX host the address belongs to, without trailing dot
+}
+
+// GetMailServerIPConfig selects which mail server addresses are returned
+type GetMailServerIPConfig struct {
+	// IncludeIPv6 keeps AAAA addresses. Off by default since AbuseIPDB
+	// reputation data is sparse for IPv6.
+	IncludeIPv6 bool
+	// PreferIPv6 lists IPv6 addresses before IPv4 ones; otherwise IPv4 comes first.
+	PreferIPv6 bool
+}
+
+// GetMailServerIPs returns the IPv4 addresses of a domain's mail servers
+func GetMailServerIPs(domain string) ([]MailServerIP, error) {
+	return GetMailServerIPsWithConfig(domain, GetMailServerIPConfig{})
+}
+
+// GetMailServerIPsWithConfig returns the addresses of a domain's mail servers selected by cfg
+func GetMailServerIPsWithConfig(domain string, cfg GetMailServerIPConfig) ([]MailServerIP, error) {
+	return getMailServerIPs(context.Background(), net.DefaultResolver, domain, cfg)
+}
+
+// getMailServerIPs returns the addresses of a domain's mail servers using the
+// given resolver. Each address is listed once, under the first MX host that
+// resolved to it.
+func getMailServerIPs(ctx context.Context, resolver DNSResolver, domain string, cfg GetMailServerIPConfig) ([]MailServerIP, error) {
 	// Get MX records
-	mxRecords, err := net.LookupMX(domain)
+	mxRecords, err := resolver.LookupMX(ctx, domain)
 	if err != nil {
-		return nil, fmt.Errorf("failed to lookup MX records: %w", err)
+		return nil, classifyDNSError(err, "MX")
 	}
 
-	var ips []string
+	var ips []MailServerIP
 	seenIPs := make(map[string]bool)
 
 	for _, mx := range mxRecords {
 		// Remove trailing dot from MX hostname
 		hostname := strings.TrimSuffix(mx.Host, ".")
 
-		// Lookup A records for the MX hostname
-		addrs, err := net.LookupHost(hostname)
+		// Lookup A and AAAA records for the MX hostname
+		addrs, err := resolver.LookupHost(ctx, hostname)
 		if err != nil {
 			continue // Skip this MX if we can't resolve it
 		}
 
 		for _, addr := range addrs {
-			// Only add unique IPs
-			if !seenIPs[addr] {
-				ips = append(ips, addr)
-				seenIPs[addr] = true
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				continue
+			}
+			version := 4
+			if ip.To4() == nil {
+				version = 6
+			}
+			if version == 6 && !cfg.IncludeIPv6 {
+				continue
+			}
+
+			// Only add unique IPs, comparing parsed addresses so notations don't matter
+			key := ip.String()
+			if !seenIPs[key] {
+				ips = append(ips, MailServerIP{IP: ip, Version: version, MXHost: hostname})
+				seenIPs[key] = true
 			}
 		}
 	}
 
+	preferred := 4
+	if cfg.PreferIPv6 {
+		preferred = 6
+	}
+	sort.SliceStable(ips, func(i, j int) bool {
+		return ips[i].Version == preferred && ips[j].Version != preferred
+	})
+
 	return ips, nil
 }
+
+// mailServerIPStrings returns the textual form of each address in ips
+func mailServerIPStrings(ips []MailServerIP) []string {
+	strs := make([]string, len(ips))
+	for i, ip := range ips {
+		strs[i] = ip.IP.String()
+	}
+	return strs
+}
+
+// CheckIPsBatch checks the reputation of multiple IP addresses sequentially,
+// issuing at most rps requests per second; rates above one per nanosecond are
+// treated as one per nanosecond. It stops early and returns the results
+// gathered so far along with ctx.Err() if the context is cancelled.
+func (c *AbuseIPDBClient) CheckIPsBatch(ctx context.Context, ips []string, rps float64) (map[string]*IPReputationResult, error) {
+	if !(rps > 0) { // also rejects NaN
+		return nil, fmt.Errorf("rps must be positive, got %v", rps)
+	}
+
+	// Above 1e9 rps the interval truncates to zero, which NewTicker rejects
+	interval := max(time.Duration(float64(time.Second)/rps), time.Nanosecond)
+
+	results := make(map[string]*IPReputationResult, len(ips))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for _, ip := range ips {
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		case <-ticker.C:
+		}
+
+		result, err := c.CheckIPContext(ctx, ip)
+		if err != nil {
+			result = &IPReputationResult{
+				IPAddress: ip,
+				Error:     fmt.Sprintf("API error: %v", err),
+				CheckedAt: time.Now(),
+			}
+		}
+		results[ip] = result
+	}
+
+	return results, nil
+}
+
+// CheckIPsBulk checks the reputation of multiple IP addresses concurrently,
+// with at most maxConcurrent requests in flight. The returned map has an entry
+// for every input IP; failed lookups (including ones cut short by ctx) have
+// the failure in IPReputationResult.Error.
+func (c *AbuseIPDBClient) CheckIPsBulk(ctx context.Context, ips []string, maxConcurrent int) map[string]*IPReputationResult {
+	results := make(map[string]*IPReputationResult, len(ips))
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(maxConcurrent, 1))
+
+	for _, ip := range ips {
+		mu.Lock()
+		_, seen := results[ip]
+		results[ip] = nil // reserve the entry so duplicates are checked once
+		mu.Unlock()
+		if seen {
+			continue
+		}
+
+		g.Go(func() error {
+			result, err := c.CheckIPContext(gctx, ip)
+			if err != nil {
+				result = &IPReputationResult{
+					IPAddress: ip,
+					Error:     fmt.Sprintf("API error: %v", err),
+					CheckedAt: time.Now(),
+				}
+			}
+
+			mu.Lock()
+			results[ip] = result
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	return results
+}
+
+// IsPrivateOrReservedIP reports whether ip is a private (RFC 1918, fc00::/7),
+// loopback, link-local, multicast or unspecified address, none of which can
+// appear in public abuse databases.
+func IsPrivateOrReservedIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
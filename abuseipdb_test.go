@@ -0,0 +1,208 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestAbuseIPDBClient returns a client sending its requests to srv.
+func newTestAbuseIPDBClient(t *testing.T, srv *httptest.Server, opts ...AbuseIPDBOption) *AbuseIPDBClient {
+	t.Helper()
+	c, err := NewAbuseIPDBClientE("test-key", append([]AbuseIPDBOption{WithHTTPDoer(srv.Client())}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewAbuseIPDBClientE: %v", err)
+	}
+	c.baseURL = srv.URL
+	return c
+}
+
+func TestCheckIPRetryStopsOnCancel(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newTestAbuseIPDBClient(t, srv, WithRetry(5, 10*time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.CheckIPContext(ctx, "8.8.8.8")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CheckIPContext error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CheckIPContext returned after %v, want it to stop waiting when ctx is done", elapsed)
+	}
+	if n := requests.Load(); n > 2 {
+		t.Errorf("server got %d requests after cancellation, want at most 2", n)
+	}
+}
+
+func TestCheckIPDoesNotRetryCancelledRequest(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := newTestAbuseIPDBClient(t, srv, WithRetry(5, time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := c.CheckIPContext(ctx, "8.8.8.8")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CheckIPContext error = %v, want context.Canceled", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestWithProxyCopiesClientAndTransport(t *testing.T) {
+	transport := &http.Transport{}
+	shared := &http.Client{Transport: transport, Timeout: time.Minute}
+
+	c, err := NewAbuseIPDBClientE("key", WithHTTPDoer(shared), WithProxy(ProxyConfig{URL: "socks5://127.0.0.1:1080"}), WithHTTPTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("NewAbuseIPDBClientE: %v", err)
+	}
+
+	if shared.Timeout != time.Minute || shared.Transport != transport {
+		t.Errorf("caller's client was modified: %+v", shared)
+	}
+	if transport.DialContext != nil {
+		t.Error("caller's transport was modified")
+	}
+	client := c.httpClient.(*http.Client)
+	if client == shared || client.Transport == transport {
+		t.Error("client and transport were not copied")
+	}
+	if client.Timeout != 5*time.Second || client.Transport.(*http.Transport).DialContext == nil {
+		t.Errorf("options not applied to the copy: %+v", client)
+	}
+}
+
+func TestWithProxyLeavesDefaultTransport(t *testing.T) {
+	if _, err := NewAbuseIPDBClientE("key", WithProxy(ProxyConfig{URL: "socks5://127.0.0.1:1080"})); err != nil {
+		t.Fatalf("NewAbuseIPDBClientE: %v", err)
+	}
+	if http.DefaultTransport.(*http.Transport).Proxy == nil {
+		t.Error("http.DefaultTransport was modified")
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestOptionsRequireHTTPClient(t *testing.T) {
+	doer := doerFunc(func(*http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+
+	for name, opt := range map[string]AbuseIPDBOption{
+		"WithProxy":         WithProxy(ProxyConfig{URL: "socks5://127.0.0.1:1080"}),
+		"WithHTTPTimeout":   WithHTTPTimeout(5 * time.Second),
+		"WithHTTPTransport": WithHTTPTransport(&http.Transport{}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAbuseIPDBClientE("key", WithHTTPDoer(doer), opt); err == nil {
+				t.Error("NewAbuseIPDBClientE succeeded with a non-*http.Client HTTPDoer")
+			}
+		})
+	}
+}
+
+func TestWithHTTPTimeoutBounds(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		wantErr bool
+	}{
+		{0, true},
+		{-time.Second, true},
+		{999 * time.Millisecond, true},
+		{time.Second, false},
+		{30 * time.Second, false},
+		{120 * time.Second, false},
+		{121 * time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timeout.String(), func(t *testing.T) {
+			c, err := NewAbuseIPDBClientE("key", WithHTTPTimeout(tt.timeout))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAbuseIPDBClientE error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && c.httpClient.(*http.Client).Timeout != tt.timeout {
+				t.Errorf("Timeout = %v, want %v", c.httpClient.(*http.Client).Timeout, tt.timeout)
+			}
+		})
+	}
+}
+
+func TestNewAbuseIPDBClientSkipsFailedOption(t *testing.T) {
+	c := NewAbuseIPDBClient("key", WithHTTPTimeout(time.Hour), WithRetry(3, time.Second))
+	if c == nil {
+		t.Fatal("NewAbuseIPDBClient returned nil")
+	}
+	if got := c.httpClient.(*http.Client).Timeout; got != defaultHTTPTimeout {
+		t.Errorf("Timeout = %v, want the default %v", got, defaultHTTPTimeout)
+	}
+	if c.maxAttempts != 3 {
+		t.Errorf("maxAttempts = %d, want the options after the failed one applied", c.maxAttempts)
+	}
+}
+
+func TestWithHTTPTimeoutSlowServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestAbuseIPDBClient(t, srv, WithHTTPTimeout(time.Second))
+	start := time.Now()
+	_, err := c.CheckIP("8.8.8.8")
+	elapsed := time.Since(start)
+
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("CheckIP error = %v, want a timeout", err)
+	}
+	if elapsed < time.Second || elapsed > 3*time.Second {
+		t.Errorf("CheckIP returned after %v, want about 1s", elapsed)
+	}
+}
+
+func TestCheckIPsBatchRate(t *testing.T) {
+	c := NewAbuseIPDBClient("test-key", WithHTTPDoer(cleanAbuseIPDBClient))
+	ips := []string{"192.0.2.1", "192.0.2.2"}
+
+	// Rates whose interval truncates to zero must not panic in NewTicker
+	for _, rps := range []float64{2e9, math.Inf(1)} {
+		results, err := c.CheckIPsBatch(context.Background(), ips, rps)
+		if err != nil || len(results) != len(ips) {
+			t.Errorf("CheckIPsBatch(rps=%v) = %d results, %v; want %d results", rps, len(results), err, len(ips))
+		}
+	}
+
+	for _, rps := range []float64{0, -1, math.NaN()} {
+		if _, err := c.CheckIPsBatch(context.Background(), ips, rps); err == nil {
+			t.Errorf("CheckIPsBatch(rps=%v) succeeded, want an error", rps)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// File: shared/anonymize.go
+package shared
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// AnonymizeMode selects how email addresses are redacted in log output.
+type AnonymizeMode string
+
+const (
+	// AnonymizeMask keeps the first character of the local part and domain name, e.g. "j***@e***.com".
+	AnonymizeMask AnonymizeMode = "mask"
+	// AnonymizeHash replaces the address with the hex-encoded SHA-256 hash of the normalized address.
+	AnonymizeHash AnonymizeMode = "hash"
+	// AnonymizeDomainOnly redacts the local part and keeps the domain, e.g. "***@example.com".
+	AnonymizeDomainOnly AnonymizeMode = "domain_only"
+)
+
+// AnonymizeEmail redacts email for logging. Unknown modes fall back to AnonymizeMask.
+func AnonymizeEmail(email string, mode AnonymizeMode) string {
+	switch mode {
+	case AnonymizeHash:
+		return hashEmail(email)
+	case AnonymizeDomainOnly:
+		at := strings.LastIndex(email, "@")
+		if at < 0 {
+			return "***"
+		}
+		return "***" + email[at:]
+	default:
+		at := strings.LastIndex(email, "@")
+		if at < 0 {
+			return maskLabel(email)
+		}
+		return maskLabel(email[:at]) + "@" + maskDomain(email[at+1:])
+	}
+}
+
+// maskDomain masks everything but the first character and the top-level domain.
+func maskDomain(domain string) string {
+	dot := strings.LastIndex(domain, ".")
+	if dot <= 0 {
+		return maskLabel(domain)
+	}
+	return maskLabel(domain[:dot]) + domain[dot:]
+}
+
+// maskLabel keeps the first character of s and replaces the rest with "***".
+func maskLabel(s string) string {
+	if s == "" {
+		return "***"
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return s[:size] + "***"
+}
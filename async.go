@@ -0,0 +1,103 @@
+// File: shared/async.go
+package shared
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// asyncJob is a validation submitted through ValidateEmailAsync.
+type asyncJob struct {
+	ctx      context.Context
+	email    string
+	callback func(*Result)
+}
+
+// asyncPool runs asynchronous validations on a fixed number of workers. Jobs
+// are queued without bound so submitting never blocks.
+type asyncPool struct {
+	once   sync.Once
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []asyncJob
+	closed bool
+}
+
+// ValidateEmailAsync queues email for validation with reputation checks and
+// returns immediately. callback is called with the result from a worker
+// goroutine; it is called synchronously with an error result if the
+// validator has been shut down. The number of workers is set by
+// ValidatorConfig.AsyncWorkers.
+func (v *EnhancedValidator) ValidateEmailAsync(ctx context.Context, email string, callback func(*Result)) {
+	pool := &v.async
+
+	pool.mu.Lock()
+	if pool.closed {
+		pool.mu.Unlock()
+		callback(&Result{
+			Email:    email,
+			Status:   StatusError.String(),
+			Reason:   "validator is shut down",
+			Metadata: make(map[string]interface{}),
+		})
+		return
+	}
+	pool.once.Do(v.startAsyncWorkers) // workers block on mu until the job is queued
+	pool.queue = append(pool.queue, asyncJob{ctx: ctx, email: email, callback: callback})
+	pool.mu.Unlock()
+	pool.cond.Signal()
+}
+
+// PendingAsyncJobs returns the number of ValidateEmailAsync jobs waiting for a worker.
+func (v *EnhancedValidator) PendingAsyncJobs() int {
+	v.async.mu.Lock()
+	defer v.async.mu.Unlock()
+
+	return len(v.async.queue)
+}
+
+// startAsyncWorkers launches the async worker goroutines.
+func (v *EnhancedValidator) startAsyncWorkers() {
+	workers := v.basicValidator.config.AsyncWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	for range workers {
+		v.wg.Add(1)
+		go func() {
+			defer v.wg.Done()
+			v.runAsyncWorker()
+		}()
+	}
+}
+
+// runAsyncWorker validates queued jobs until the pool is closed and drained.
+func (v *EnhancedValidator) runAsyncWorker() {
+	pool := &v.async
+	for {
+		pool.mu.Lock()
+		for len(pool.queue) == 0 && !pool.closed {
+			pool.cond.Wait()
+		}
+		if len(pool.queue) == 0 {
+			pool.mu.Unlock()
+			return
+		}
+		job := pool.queue[0]
+		pool.queue = pool.queue[1:]
+		pool.mu.Unlock()
+
+		job.callback(v.ValidateEmailWithReputationContext(job.ctx, job.email))
+	}
+}
+
+// stopAsyncWorkers stops accepting jobs; workers exit once the queue is drained.
+func (v *EnhancedValidator) stopAsyncWorkers() {
+	pool := &v.async
+	pool.mu.Lock()
+	pool.closed = true
+	pool.mu.Unlock()
+	pool.cond.Broadcast()
+}
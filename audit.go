@@ -0,0 +1,148 @@
+// File: shared/audit.go
+package shared
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry records a single validation decision.
+type AuditEntry struct {
+	Timestamp  time.Time     `json:"timestamp"`
+	Email      string        `json:"email"` // the address, or its SHA-256 hash when hashing is enabled
+	Status     Status        `json:"status"`
+	SubStatus  string        `json:"sub_status,omitempty"`
+	RequestID  string        `json:"request_id,omitempty"`
+	ActorID    string        `json:"actor_id,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	IPsChecked []string      `json:"ips_checked,omitempty"`
+}
+
+// AuditLogger stores validation decisions in an append-only audit trail.
+type AuditLogger interface {
+	Log(entry AuditEntry) error
+}
+
+// FileAuditLogger writes audit entries as newline-delimited JSON.
+type FileAuditLogger struct {
+	// HashEmails replaces each email with its hex-encoded SHA-256 hash before writing.
+	HashEmails bool
+
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File // set when opened with OpenAuditLog
+	path string
+}
+
+// NewFileAuditLogger creates an audit logger writing to w.
+func NewFileAuditLogger(w io.Writer) *FileAuditLogger {
+	return &FileAuditLogger{w: w}
+}
+
+// OpenAuditLog opens (or creates) the audit log at path in append-only mode.
+func OpenAuditLog(path string) (*FileAuditLogger, error) {
+	file, err := openAuditFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileAuditLogger{w: file, file: file, path: path}, nil
+}
+
+// Log appends entry to the audit log.
+func (l *FileAuditLogger) Log(entry AuditEntry) error {
+	if l.HashEmails {
+		entry.Email = hashEmail(entry.Email)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Reopen reopens the log file at its original path, e.g. after RotateAuditLog.
+// It has no effect on loggers created with NewFileAuditLogger.
+func (l *FileAuditLogger) Reopen() error {
+	if l.path == "" {
+		return nil
+	}
+
+	file, err := openAuditFile(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	old := l.file
+	l.w, l.file = file, file
+	return old.Close()
+}
+
+// Close closes the log file. It has no effect on loggers created with NewFileAuditLogger.
+func (l *FileAuditLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// TruncateAuditLog empties the audit log at path.
+func TruncateAuditLog(path string) error {
+	if err := os.Truncate(path, 0); err != nil {
+		return fmt.Errorf("failed to truncate audit log: %w", err)
+	}
+	return nil
+}
+
+// RotateAuditLog moves the audit log at path aside, suffixed with the current
+// UTC time, and creates an empty log in its place. It returns the rotated file's
+// path. Loggers opened on path keep writing to the rotated file until Reopen is called.
+func RotateAuditLog(path string) (string, error) {
+	rotated := fmt.Sprintf("%s.%s", path, time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.Rename(path, rotated); err != nil {
+		return "", fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	file, err := openAuditFile(path)
+	if err != nil {
+		return rotated, err
+	}
+	return rotated, file.Close()
+}
+
+// openAuditFile opens path for appending, creating it if needed.
+func openAuditFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return file, nil
+}
+
+// hashEmail returns the hex-encoded SHA-256 hash of the normalized email.
+func hashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,161 @@
+// File: shared/batch_anomaly.go
+package shared
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BatchAnomalies describes patterns in a batch that are typical of credential
+// stuffing or account enumeration rather than organic sign-ups.
+type BatchAnomalies struct {
+	// SameDomainConcentration is the share (0-1) of addresses on the most common domain.
+	SameDomainConcentration float64 `json:"same_domain_concentration"`
+	TopDomain               string  `json:"top_domain,omitempty"`
+
+	// SequentialPatterns counts runs of enumerated local parts such as user001, user002, user003.
+	SequentialPatterns int `json:"sequential_patterns"`
+
+	// SharedLocalParts counts local parts used on more than one domain, e.g. alice@gmail.com and alice@yahoo.com.
+	SharedLocalParts int `json:"shared_local_parts"`
+}
+
+// BatchAnomalyDetector finds credential stuffing patterns in a completed batch.
+type BatchAnomalyDetector struct {
+	// ConcentrationThreshold is the share of the batch on one domain above which a warning is raised.
+	ConcentrationThreshold float64
+
+	// MinBatchSize is the smallest batch checked for domain concentration; tiny batches are concentrated by nature.
+	MinBatchSize int
+
+	// MinSequenceLength is how many consecutive numbers make an enumeration pattern.
+	MinSequenceLength int
+}
+
+// NewBatchAnomalyDetector creates a detector with the default thresholds:
+// more than 50% of a batch of at least 10 on one domain, and runs of 3 or
+// more consecutive numbers.
+func NewBatchAnomalyDetector() *BatchAnomalyDetector {
+	return &BatchAnomalyDetector{
+		ConcentrationThreshold: 0.5,
+		MinBatchSize:           10,
+		MinSequenceLength:      3,
+	}
+}
+
+// Detect analyzes the addresses of results.
+func (d *BatchAnomalyDetector) Detect(results []*Result) BatchAnomalies {
+	var anomalies BatchAnomalies
+
+	total := 0
+	domainCounts := make(map[string]int)
+	localDomains := make(map[string]map[string]bool)
+	sequences := make(map[string][]int) // prefix@domain -> numeric suffixes
+
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		at := strings.LastIndex(r.Email, "@")
+		if at < 0 {
+			continue
+		}
+		local := strings.ToLower(r.Email[:at])
+		domain := strings.ToLower(r.Email[at+1:])
+		total++
+
+		domainCounts[domain]++
+		if localDomains[local] == nil {
+			localDomains[local] = make(map[string]bool)
+		}
+		localDomains[local][domain] = true
+
+		if prefix, n, ok := splitNumericSuffix(local); ok {
+			key := prefix + "@" + domain
+			sequences[key] = append(sequences[key], n)
+		}
+	}
+
+	for domain, count := range domainCounts {
+		share := float64(count) / float64(total)
+		if share > anomalies.SameDomainConcentration ||
+			(share == anomalies.SameDomainConcentration && domain < anomalies.TopDomain) {
+			anomalies.SameDomainConcentration = share
+			anomalies.TopDomain = domain
+		}
+	}
+
+	for _, domains := range localDomains {
+		if len(domains) > 1 {
+			anomalies.SharedLocalParts++
+		}
+	}
+
+	for _, numbers := range sequences {
+		anomalies.SequentialPatterns += countSequentialRuns(numbers, d.MinSequenceLength)
+	}
+
+	return anomalies
+}
+
+// Warnings describes the anomalies that exceed the detector's thresholds.
+func (d *BatchAnomalyDetector) Warnings(a BatchAnomalies, batchSize int) []string {
+	var warnings []string
+	if batchSize >= d.MinBatchSize && a.SameDomainConcentration > d.ConcentrationThreshold {
+		warnings = append(warnings, fmt.Sprintf("%.0f%% of addresses are on %s", a.SameDomainConcentration*100, a.TopDomain))
+	}
+	if a.SequentialPatterns > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d sequential address pattern(s) suggest enumeration", a.SequentialPatterns))
+	}
+	if a.SharedLocalParts > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d local part(s) appear on several domains", a.SharedLocalParts))
+	}
+	return warnings
+}
+
+// splitNumericSuffix splits a local part such as "user001" into "user" and 1.
+// ok is false if there is no trailing number or nothing precedes it.
+func splitNumericSuffix(local string) (prefix string, n int, ok bool) {
+	i := len(local)
+	for i > 0 && local[i-1] >= '0' && local[i-1] <= '9' {
+		i--
+	}
+	if i == len(local) || i == 0 || len(local)-i > 9 {
+		return "", 0, false
+	}
+
+	n, err := strconv.Atoi(local[i:])
+	if err != nil {
+		return "", 0, false
+	}
+	return local[:i], n, true
+}
+
+// countSequentialRuns counts runs of at least minLength consecutive integers in numbers.
+func countSequentialRuns(numbers []int, minLength int) int {
+	if minLength < 2 {
+		minLength = 2
+	}
+	if len(numbers) < minLength {
+		return 0
+	}
+
+	sort.Ints(numbers)
+	runs, length := 0, 1
+	for i := 1; i <= len(numbers); i++ {
+		if i < len(numbers) && numbers[i] == numbers[i-1] {
+			continue // duplicates neither extend nor break a run
+		}
+		if i < len(numbers) && numbers[i] == numbers[i-1]+1 {
+			length++
+			continue
+		}
+		if length >= minLength {
+			runs++
+		}
+		length = 1
+	}
+	return runs
+}
@@ -0,0 +1,147 @@
+// File: shared/batch_stats.go
+package shared
+
+import (
+	"sort"
+	"time"
+)
+
+// BatchStats summarises the results of a batch validation.
+type BatchStats struct {
+	Total           int           `json:"total"`
+	Valid           int           `json:"valid"`
+	Invalid         int           `json:"invalid"`
+	Risky           int           `json:"risky"`
+	Error           int           `json:"error"`
+	DisposableCount int           `json:"disposable_count"`
+	RoleBasedCount  int           `json:"role_based_count"`
+	CatchAllCount   int           `json:"catch_all_count"`
+	AverageScore    float64       `json:"average_score"`
+	MinScore        int           `json:"min_score"`
+	MaxScore        int           `json:"max_score"`
+	P50Latency      time.Duration `json:"p50_latency"`
+	P95Latency      time.Duration `json:"p95_latency"`
+	P99Latency      time.Duration `json:"p99_latency"`
+
+	// Anomalies and Warnings flag credential stuffing patterns, see BatchAnomalyDetector.
+	Anomalies BatchAnomalies `json:"anomalies"`
+	Warnings  []string       `json:"warnings,omitempty"`
+
+	reasonCounts map[string]int
+}
+
+// ReasonCount pairs a failure reason with the number of results that reported it.
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// ComputeBatchStats computes breakdown statistics for a batch of results in a single pass.
+func ComputeBatchStats(results []*Result) BatchStats {
+	stats := BatchStats{
+		reasonCounts: make(map[string]int),
+	}
+
+	var scoreSum int
+	latencies := make([]time.Duration, 0, len(results))
+
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		stats.Total++
+
+		switch Status(r.Status) {
+		case StatusValid:
+			stats.Valid++
+		case StatusInvalid:
+			stats.Invalid++
+		case StatusRisky, StatusCatchAll:
+			stats.Risky++
+		case StatusError:
+			stats.Error++
+		}
+
+		if Status(r.Status) != StatusValid && r.Reason != "" {
+			stats.reasonCounts[r.Reason]++
+		}
+
+		if metadataFlag(r, "is_disposable") {
+			stats.DisposableCount++
+		}
+		if metadataFlag(r, "is_role_based") {
+			stats.RoleBasedCount++
+		}
+		if metadataFlag(r, "is_catch_all") {
+			stats.CatchAllCount++
+		}
+
+		scoreSum += r.Score
+		if stats.Total == 1 || r.Score < stats.MinScore {
+			stats.MinScore = r.Score
+		}
+		if r.Score > stats.MaxScore {
+			stats.MaxScore = r.Score
+		}
+
+		latencies = append(latencies, r.Duration)
+	}
+
+	if stats.Total > 0 {
+		stats.AverageScore = float64(scoreSum) / float64(stats.Total)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	stats.P50Latency = percentile(latencies, 50)
+	stats.P95Latency = percentile(latencies, 95)
+	stats.P99Latency = percentile(latencies, 99)
+
+	detector := NewBatchAnomalyDetector()
+	stats.Anomalies = detector.Detect(results)
+	stats.Warnings = detector.Warnings(stats.Anomalies, stats.Total)
+
+	return stats
+}
+
+// TopFailureReasons returns the five most common failure reasons, most frequent first.
+func (s BatchStats) TopFailureReasons() []ReasonCount {
+	reasons := make([]ReasonCount, 0, len(s.reasonCounts))
+	for reason, count := range s.reasonCounts {
+		reasons = append(reasons, ReasonCount{Reason: reason, Count: count})
+	}
+
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+		return reasons[i].Reason < reasons[j].Reason
+	})
+
+	if len(reasons) > 5 {
+		reasons = reasons[:5]
+	}
+
+	return reasons
+}
+
+// metadataFlag reports whether the result's metadata has the given key set to true.
+func metadataFlag(r *Result, key string) bool {
+	flag, ok := r.Metadata[key].(bool)
+	return ok && flag
+}
+
+// percentile returns the p-th percentile of sorted durations using the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
@@ -0,0 +1,185 @@
+// File: shared/batch_upload.go
+package shared
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// utf8BOM is the byte order mark some editors prepend to UTF-8 files.
+const utf8BOM = "\ufeff"
+
+// LineError describes a single malformed line in an uploaded email list.
+type LineError struct {
+	Line   int // 1-based line number in the uploaded file
+	Reason string
+}
+
+// ParseError is returned when an uploaded email list can't be turned into a batch request.
+// Lines holds every malformed line; it's empty for errors that affect the whole upload.
+type ParseError struct {
+	Reason string
+	Lines  []LineError
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	if len(e.Lines) == 0 {
+		return fmt.Sprintf("batch parse error: %s", e.Reason)
+	}
+
+	first := e.Lines[0]
+	return fmt.Sprintf("batch parse error: %s (%d malformed lines, first at line %d: %s)",
+		e.Reason, len(e.Lines), first.Line, first.Reason)
+}
+
+// ParseBatchRequestFromMultipart reads an uploaded email list from the multipart
+// form field fieldName. The file is parsed as CSV if its first line contains a
+// comma, otherwise as one email per line. Blank lines and lines starting with #
+// are skipped. The upload is rejected if it contains more than maxEmails
+// addresses; a maxEmails of 0 or less means no limit.
+//
+// For CSV files the "email" column is used if the first row is a header,
+// otherwise the first field containing an @.
+func ParseBatchRequestFromMultipart(r *http.Request, fieldName string, maxEmails int) (*BatchRequest, error) {
+	file, _, err := r.FormFile(fieldName)
+	if err != nil {
+		return nil, &ParseError{Reason: fmt.Sprintf("failed to read form file %q: %v", fieldName, err)}
+	}
+	defer file.Close()
+
+	emails, err := parseEmailList(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxEmails > 0 && len(emails) > maxEmails {
+		return nil, &ParseError{Reason: fmt.Sprintf("too many emails: %d exceeds limit of %d", len(emails), maxEmails)}
+	}
+
+	return BatchRequestFromStrings(emails), nil
+}
+
+// parseEmailList extracts email addresses from a plain or CSV email list.
+func parseEmailList(r io.Reader) ([]string, error) {
+	var (
+		emails   []string
+		lineErrs []LineError
+		lineNum  int
+		isCSV    bool
+		detected bool
+		emailCol = -1
+		scanner  = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		lineNum++
+		// ScanLines already drops the \r of CRLF endings
+		line := scanner.Text()
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, utf8BOM)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !detected {
+			detected = true
+			isCSV = strings.Contains(line, ",")
+
+			// A CSV header row names the columns rather than holding an email
+			if isCSV && !strings.Contains(line, "@") {
+				fields, err := parseCSVLine(line)
+				if err != nil {
+					lineErrs = append(lineErrs, LineError{Line: lineNum, Reason: err.Error()})
+					continue
+				}
+				emailCol = csvEmailColumn(fields)
+				continue
+			}
+		}
+
+		email := line
+		if isCSV {
+			fields, err := parseCSVLine(line)
+			if err != nil {
+				lineErrs = append(lineErrs, LineError{Line: lineNum, Reason: err.Error()})
+				continue
+			}
+
+			var ok bool
+			email, ok = csvEmailField(fields, emailCol)
+			if !ok {
+				lineErrs = append(lineErrs, LineError{Line: lineNum, Reason: "no email field found"})
+				continue
+			}
+		}
+
+		if !strings.Contains(email, "@") {
+			lineErrs = append(lineErrs, LineError{Line: lineNum, Reason: fmt.Sprintf("not an email address: %q", email)})
+			continue
+		}
+
+		emails = append(emails, email)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, &ParseError{Reason: fmt.Sprintf("failed to read email list: %v", err)}
+	}
+	if len(lineErrs) > 0 {
+		return nil, &ParseError{Reason: "malformed lines in email list", Lines: lineErrs}
+	}
+	if len(emails) == 0 {
+		return nil, &ParseError{Reason: "email list is empty"}
+	}
+
+	return emails, nil
+}
+
+// parseCSVLine splits a single CSV line, honoring quoted fields.
+func parseCSVLine(line string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(line))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	fields, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	return fields, nil
+}
+
+// csvEmailColumn returns the index of the email column in a header row, or -1.
+func csvEmailColumn(header []string) int {
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "email", "e-mail", "email_address", "email address":
+			return i
+		}
+	}
+	return -1
+}
+
+// csvEmailField returns the email from a CSV row, using col if known.
+func csvEmailField(fields []string, col int) (string, bool) {
+	if col >= 0 {
+		if col >= len(fields) {
+			return "", false
+		}
+		return strings.TrimSpace(fields[col]), true
+	}
+
+	for _, field := range fields {
+		if strings.Contains(field, "@") {
+			return strings.TrimSpace(field), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,50 @@
+// File: shared/bot_detection.go
+package shared
+
+import (
+	"math"
+	"strings"
+)
+
+// defaultEntropyBotThreshold is the local part entropy, in bits per
+// character, at which EntropyBasedBotScore returns 50. Human-chosen local
+// parts such as "christopher.johnson" stay around 3.0-3.6 bits; generated
+// ones like "k3j9d8f7g6h5a2s1" reach 4.
+const defaultEntropyBotThreshold = 3.8
+
+// entropyBotScoreSpan is the entropy difference, in bits per character,
+// between a bot score of 0 and 100.
+const entropyBotScoreSpan = 1.0
+
+// ComputeLocalPartEntropy returns the Shannon entropy of localPart in bits per
+// character, ignoring case; see ShannonEntropy.
+func ComputeLocalPartEntropy(localPart string) float64 {
+	return ShannonEntropy(strings.ToLower(localPart))
+}
+
+// EntropyBasedBotScore rates from 0 to 100 how likely a local part with the
+// given entropy was generated by a bot rather than chosen by a person, using
+// the default threshold of 3.8 bits per character.
+//
+// Entropy can't exceed log2 of the local part length, so short local parts
+// never reach the threshold however random they are: it takes 14 distinct
+// characters to exceed 3.8 bits.
+func EntropyBasedBotScore(entropy float64) int {
+	return entropyBotScore(entropy, defaultEntropyBotThreshold)
+}
+
+// entropyBotScore scores entropy linearly around threshold: 50 at the
+// threshold, 0 and 100 half an entropyBotScoreSpan below and above it.
+func entropyBotScore(entropy, threshold float64) int {
+	score := 50 + 100*(entropy-threshold)/entropyBotScoreSpan
+	return min(max(int(math.Round(score)), 0), 100)
+}
+
+// botScore returns the bot score of localPart with the configured threshold.
+func (v *Validator) botScore(localPart string) int {
+	threshold := v.config.EntropyBotThreshold
+	if threshold <= 0 {
+		threshold = defaultEntropyBotThreshold
+	}
+	return entropyBotScore(ComputeLocalPartEntropy(localPart), threshold)
+}
@@ -0,0 +1,108 @@
+// File: shared/bulk_senders.go
+package shared
+
+import (
+	"net"
+	"path"
+	"strings"
+)
+
+// BulkSenderProvider identifies a bulk email platform by its MX hosts and IP ranges.
+// Their shared IPs often carry abuse reports from other customers, so their
+// reputation says little about an individual domain.
+type BulkSenderProvider struct {
+	Name       string
+	MXPatterns []string     // path.Match patterns for MX host names, e.g. "*.mailgun.org"
+	IPRanges   []*net.IPNet // published sending and receiving ranges
+}
+
+// Built-in bulk sender providers, using the ranges published in their SPF records.
+var (
+	MailgunProvider = &BulkSenderProvider{
+		Name:       "mailgun",
+		MXPatterns: []string{"*.mailgun.org"},
+		IPRanges: mustParseCIDRs(
+			"69.72.32.0/20", "159.135.224.0/20", "166.78.68.0/22", "198.61.254.0/23",
+			"209.61.151.0/24", "146.20.112.0/21", "161.38.192.0/20",
+		),
+	}
+	SendGridProvider = &BulkSenderProvider{
+		Name:       "sendgrid",
+		MXPatterns: []string{"mx.sendgrid.net", "*.sendgrid.net"},
+		IPRanges: mustParseCIDRs(
+			"167.89.0.0/17", "149.72.0.0/16", "168.245.0.0/17", "198.37.144.0/20",
+			"208.117.48.0/20", "50.31.32.0/19", "159.183.0.0/16",
+		),
+	}
+	AmazonSESProvider = &BulkSenderProvider{
+		Name:       "amazon_ses",
+		MXPatterns: []string{"inbound-smtp.*.amazonaws.com", "feedback-smtp.*.amazonses.com"},
+		IPRanges: mustParseCIDRs(
+			"54.240.0.0/18", "23.249.208.0/20", "23.251.224.0/19", "76.223.176.0/20",
+			"199.255.192.0/22", "199.127.232.0/22", "54.240.64.0/19",
+		),
+	}
+)
+
+// DefaultBulkSenderProviders lists the built-in providers.
+var DefaultBulkSenderProviders = []*BulkSenderProvider{MailgunProvider, SendGridProvider, AmazonSESProvider}
+
+// MatchesMX reports whether host matches one of the provider's MX patterns.
+func (p *BulkSenderProvider) MatchesMX(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range p.MXPatterns {
+		if ok, _ := path.Match(pattern, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsIP reports whether ip falls in one of the provider's IP ranges.
+func (p *BulkSenderProvider) ContainsIP(ip net.IP) bool {
+	for _, ipNet := range p.IPRanges {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithBulkSenderProviders replaces the bulk sender providers whose IPs are exempt
+// from the reputation penalty. Passing no providers disables the exemption.
+func WithBulkSenderProviders(providers ...*BulkSenderProvider) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.bulkSenders = providers
+	}
+}
+
+// findBulkSender returns the provider hosting the domain, judged by its MX
+// hosts or mail server IPs, or nil.
+func findBulkSender(providers []*BulkSenderProvider, mxRecords []*net.MX, ips []string) *BulkSenderProvider {
+	for _, provider := range providers {
+		for _, mx := range mxRecords {
+			if provider.MatchesMX(mx.Host) {
+				return provider
+			}
+		}
+		for _, ip := range ips {
+			if parsed := net.ParseIP(ip); parsed != nil && provider.ContainsIP(parsed) {
+				return provider
+			}
+		}
+	}
+	return nil
+}
+
+// mustParseCIDRs parses CIDR ranges, panicking on invalid input.
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic("invalid CIDR " + cidr + ": " + err.Error())
+		}
+		nets[i] = ipNet
+	}
+	return nets
+}
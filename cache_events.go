@@ -0,0 +1,52 @@
+// File: shared/cache_events.go
+package shared
+
+import (
+	"time"
+)
+
+// CacheEventType identifies what happened to an IP reputation cache entry.
+type CacheEventType string
+
+const (
+	CacheEventHit      CacheEventType = "hit"
+	CacheEventMiss     CacheEventType = "miss"
+	CacheEventEviction CacheEventType = "eviction"
+	CacheEventExpiry   CacheEventType = "expiry"
+)
+
+// CacheEvent describes a single IP reputation cache lookup or removal.
+type CacheEvent struct {
+	Type            CacheEventType
+	IP              string
+	TimeSinceInsert time.Duration // zero for misses
+}
+
+// CacheEventHook receives cache events, e.g. to feed a metrics system.
+type CacheEventHook func(event CacheEvent)
+
+// WithCacheEventHook calls hook for every IP reputation cache hit, miss, eviction and expiry.
+// The hook is called synchronously and should return quickly.
+func WithCacheEventHook(hook CacheEventHook) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.cacheEventHook = hook
+	}
+}
+
+// emitCacheEvent calls the cache event hook, if one is set, and reports lookups to the metrics emitter.
+func (v *EnhancedValidator) emitCacheEvent(eventType CacheEventType, ip string, insertedAt time.Time) {
+	if v.metrics != nil && eventType != CacheEventEviction {
+		v.metrics.RecordIPCheck(eventType == CacheEventHit)
+	}
+
+	if v.cacheEventHook == nil {
+		return
+	}
+
+	event := CacheEvent{Type: eventType, IP: ip}
+	if !insertedAt.IsZero() {
+		event.TimeSinceInsert = time.Since(insertedAt)
+	}
+
+	v.cacheEventHook(event)
+}
@@ -0,0 +1,117 @@
+// File: shared/cache_warmup.go
+package shared
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// WarmCache pre-populates the IP reputation cache so that the first batch of
+// validations doesn't trigger a burst of AbuseIPDB calls. IPs that are already
+// cached are skipped. Requests are limited to rps per second and the warm-up
+// stops when ctx is cancelled. Progress is logged every 10%. The cache of the
+// tenant in ctx (or the configured TenantID) is warmed.
+func (v *EnhancedValidator) WarmCache(ctx context.Context, ips []string, rps float64) error {
+	tenant := v.tenantID(ctx)
+
+	// Only look up IPs that aren't cached yet
+	var pending []string
+	v.cacheMutex.RLock()
+	for _, ip := range ips {
+		if cached, exists := v.ipCache[tenant][ip]; !exists || cached.expired(time.Now()) {
+			pending = append(pending, ip)
+		}
+	}
+	v.cacheMutex.RUnlock()
+
+	total := len(pending)
+	if total == 0 {
+		return nil
+	}
+
+	slog.Info("warming IP reputation cache", slog.Int("ips", total), slog.Float64("rps", rps))
+
+	// Process in chunks of ~10% so progress can be reported between them
+	chunkSize := (total + 9) / 10
+	done := 0
+	for start := 0; start < total; start += chunkSize {
+		end := start + chunkSize
+		if end > total {
+			end = total
+		}
+
+		results, err := v.abuseIPDB.CheckIPsBatch(ctx, pending[start:end], rps)
+
+		v.cacheMutex.Lock()
+		cache := v.tenantCache(tenant)
+		for ip, result := range results {
+			if result.Error == "" {
+				cache[ip] = v.newCacheEntry(result)
+			}
+		}
+		v.cacheMutex.Unlock()
+
+		done += len(results)
+		if err != nil {
+			slog.Warn("IP reputation cache warm-up stopped",
+				slog.Int("done", done), slog.Int("total", total), slog.Any("error", err))
+			return err
+		}
+
+		slog.Info("IP reputation cache warm-up progress",
+			slog.Int("done", done), slog.Int("total", total), slog.Int("percent", done*100/total))
+	}
+
+	return nil
+}
+
+// WarmCacheFromConfig loads the IP list from ValidatorConfig.WarmupIPListPath and warms the cache.
+// It is a no-op when no path is configured.
+func (v *EnhancedValidator) WarmCacheFromConfig(ctx context.Context, rps float64) error {
+	path := v.basicValidator.config.WarmupIPListPath
+	if path == "" {
+		return nil
+	}
+
+	ips, err := LoadWarmupIPList(path)
+	if err != nil {
+		return err
+	}
+
+	return v.WarmCache(ctx, ips, rps)
+}
+
+// LoadWarmupIPList reads a list of IP addresses, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadWarmupIPList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open warm-up IP list: %w", err)
+	}
+	defer file.Close()
+
+	var ips []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ips = append(ips, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read warm-up IP list: %w", err)
+	}
+	if len(ips) == 0 {
+		return nil, errors.New("warm-up IP list is empty")
+	}
+
+	return ips, nil
+}
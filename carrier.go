@@ -0,0 +1,52 @@
+// File: shared/carrier.go
+package shared
+
+import (
+	"strings"
+)
+
+// TagSMSGateway is added to Result.Tags for mobile carrier SMS gateway addresses.
+const TagSMSGateway = "sms-gateway"
+
+// DefaultCarrierDomains maps mobile carrier email-to-SMS gateway domains to the carrier name.
+var DefaultCarrierDomains = map[string]string{
+	// Verizon
+	"vtext.com": "Verizon", "vzwpix.com": "Verizon",
+	// AT&T
+	"txt.att.net": "AT&T", "mms.att.net": "AT&T",
+	// T-Mobile
+	"tmomail.net": "T-Mobile",
+	// Sprint
+	"messaging.sprintpcs.com": "Sprint", "pm.sprint.com": "Sprint",
+	// US Cellular
+	"email.uscc.net": "US Cellular", "mms.uscc.net": "US Cellular",
+	// Prepaid and regional carriers
+	"sms.myboostmobile.com":   "Boost Mobile",
+	"sms.cricketwireless.net": "Cricket", "mms.cricketwireless.net": "Cricket",
+	"mymetropcs.com":            "Metro by T-Mobile",
+	"msg.fi.google.com":         "Google Fi",
+	"vmobl.com":                 "Virgin Mobile",
+	"text.republicwireless.com": "Republic Wireless",
+	"mailmymobile.net":          "Consumer Cellular",
+	// Canada
+	"txt.bell.ca": "Bell", "pcs.rogers.com": "Rogers", "msg.telus.com": "Telus",
+	"fido.ca": "Fido",
+}
+
+// IsCarrierEmail reports whether email is addressed to a known mobile carrier
+// SMS gateway, e.g. 5551234567@vtext.com. Such addresses accept mail but
+// forward it as text messages rather than to a personal inbox.
+func IsCarrierEmail(email string) bool {
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return false
+	}
+	_, found := carrierForDomain(domain, DefaultCarrierDomains)
+	return found
+}
+
+// carrierForDomain returns the carrier operating domain as an SMS gateway.
+func carrierForDomain(domain string, carriers map[string]string) (string, bool) {
+	carrier, ok := carriers[strings.ToLower(domain)]
+	return carrier, ok
+}
@@ -0,0 +1,219 @@
+// File: shared/cloudwatch.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// cloudWatchMaxDatumsPerCall is the most metric data points PutMetricData accepts per request
+const cloudWatchMaxDatumsPerCall = 20
+
+// CloudWatch metric names published by CloudWatchEmitter
+const (
+	MetricValidationsTotal   = "ValidationsTotal"
+	MetricValidationDuration = "ValidationDuration"
+	MetricSMTPSuccess        = "SMTPSuccess"
+	MetricIPCheckCacheHit    = "IPCheckCacheHit"
+	MetricDisposableDetected = "DisposableDetected"
+	MetricErrorRate          = "ErrorRate"
+)
+
+// CloudWatch units used by CloudWatchEmitter
+const (
+	UnitCount        = "Count"
+	UnitMilliseconds = "Milliseconds"
+	UnitPercent      = "Percent"
+)
+
+// MetricDatum is a single CloudWatch data point.
+type MetricDatum struct {
+	Name       string
+	Value      float64
+	Unit       string
+	Dimensions map[string]string
+	Timestamp  time.Time
+}
+
+// CloudWatchAPI publishes metric data to CloudWatch. Adapt the AWS SDK's
+// cloudwatch.Client.PutMetricData to it, passing region as a per-call
+// cloudwatch.Options.Region override unless it is empty; the client supplies
+// the credentials. At most 20 data points are passed per call.
+type CloudWatchAPI interface {
+	PutMetricData(ctx context.Context, region, namespace string, data []MetricDatum) error
+}
+
+// CloudWatchConfig holds the settings of a CloudWatchEmitter.
+type CloudWatchConfig struct {
+	// Region is the AWS region metrics are published to, e.g. "eu-west-1".
+	// Empty uses the region the CloudWatchAPI client was configured with.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// Namespace is the CloudWatch namespace metrics are published under.
+	Namespace string `json:"namespace" yaml:"namespace"`
+
+	// Interval is how often collected metrics are published. Zero disables
+	// periodic publishing; metrics are then only sent by Flush.
+	Interval time.Duration `json:"interval" yaml:"interval"`
+
+	// Dimensions are attached to every data point, e.g. {"Environment": "prod"}.
+	Dimensions map[string]string `json:"dimensions,omitempty" yaml:"dimensions,omitempty"`
+}
+
+// DefaultCloudWatchConfig returns the settings used when none are customized.
+func DefaultCloudWatchConfig() CloudWatchConfig {
+	return CloudWatchConfig{
+		Namespace: "AzloValidator",
+		Interval:  time.Minute,
+	}
+}
+
+// cloudWatchCounters accumulates statistics between two flushes.
+type cloudWatchCounters struct {
+	validations   int
+	durationTotal time.Duration
+	smtpSuccess   int
+	ipCacheHits   int
+	disposable    int
+	errors        int
+}
+
+// CloudWatchEmitter is a MetricsEmitter that aggregates validation statistics
+// and publishes them to CloudWatch once per interval.
+type CloudWatchEmitter struct {
+	client CloudWatchAPI
+	config CloudWatchConfig
+
+	mu       sync.Mutex
+	counters cloudWatchCounters
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCloudWatchEmitter creates an emitter publishing through client and starts
+// its publishing loop. An empty namespace uses the default. Call Close to stop it.
+func NewCloudWatchEmitter(client CloudWatchAPI, cfg CloudWatchConfig) *CloudWatchEmitter {
+	if cfg.Namespace == "" {
+		cfg.Namespace = DefaultCloudWatchConfig().Namespace
+	}
+
+	e := &CloudWatchEmitter{
+		client: client,
+		config: cfg,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// RecordValidation counts a finished validation.
+func (e *CloudWatchEmitter) RecordValidation(result *Result) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.counters.validations++
+	e.counters.durationTotal += result.Duration
+	if code, ok := result.Metadata["smtp_code"].(int); ok && code >= 200 && code < 300 {
+		e.counters.smtpSuccess++
+	}
+	if disposable, _ := result.Metadata["is_disposable"].(bool); disposable {
+		e.counters.disposable++
+	}
+	if Status(result.Status) == StatusError {
+		e.counters.errors++
+	}
+}
+
+// RecordIPCheck counts an IP reputation lookup served from the cache.
+func (e *CloudWatchEmitter) RecordIPCheck(cacheHit bool) {
+	if !cacheHit {
+		return
+	}
+	e.mu.Lock()
+	e.counters.ipCacheHits++
+	e.mu.Unlock()
+}
+
+// Flush publishes the statistics collected since the last flush, batching
+// data points into as few PutMetricData calls as possible.
+func (e *CloudWatchEmitter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	counters := e.counters
+	e.counters = cloudWatchCounters{}
+	e.mu.Unlock()
+
+	data := e.datums(counters, time.Now())
+	var errs []error
+	for start := 0; start < len(data); start += cloudWatchMaxDatumsPerCall {
+		end := min(start+cloudWatchMaxDatumsPerCall, len(data))
+		if err := e.client.PutMetricData(ctx, e.config.Region, e.config.Namespace, data[start:end]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops the publishing loop and flushes the remaining statistics.
+func (e *CloudWatchEmitter) Close(ctx context.Context) error {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+	<-e.done
+	return e.Flush(ctx)
+}
+
+// run publishes the collected statistics every interval until Close is called.
+func (e *CloudWatchEmitter) run() {
+	defer close(e.done)
+	if e.config.Interval <= 0 {
+		<-e.stop
+		return
+	}
+
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.Flush(context.Background()); err != nil {
+				log.Printf("Failed to publish CloudWatch metrics: %v", err)
+			}
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// datums converts counters into CloudWatch data points. Nothing is sent for
+// an interval without activity.
+func (e *CloudWatchEmitter) datums(c cloudWatchCounters, now time.Time) []MetricDatum {
+	if c.validations == 0 && c.ipCacheHits == 0 {
+		return nil
+	}
+
+	datum := func(name string, value float64, unit string) MetricDatum {
+		return MetricDatum{Name: name, Value: value, Unit: unit, Dimensions: e.config.Dimensions, Timestamp: now}
+	}
+
+	data := []MetricDatum{
+		datum(MetricValidationsTotal, float64(c.validations), UnitCount),
+		datum(MetricSMTPSuccess, float64(c.smtpSuccess), UnitCount),
+		datum(MetricIPCheckCacheHit, float64(c.ipCacheHits), UnitCount),
+		datum(MetricDisposableDetected, float64(c.disposable), UnitCount),
+	}
+	if c.validations > 0 {
+		avg := c.durationTotal / time.Duration(c.validations)
+		data = append(data,
+			datum(MetricValidationDuration, float64(avg.Milliseconds()), UnitMilliseconds),
+			datum(MetricErrorRate, 100*float64(c.errors)/float64(c.validations), UnitPercent),
+		)
+	}
+	return data
+}
@@ -0,0 +1,171 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// putMetricDataCall is one call received by mockCloudWatch.
+type putMetricDataCall struct {
+	region    string
+	namespace string
+	data      []MetricDatum
+}
+
+// mockCloudWatch records PutMetricData calls and fails them with err.
+type mockCloudWatch struct {
+	mu    sync.Mutex
+	calls []putMetricDataCall
+	err   error
+	sent  chan struct{} // receives after each call, if not nil
+}
+
+func (m *mockCloudWatch) PutMetricData(ctx context.Context, region, namespace string, data []MetricDatum) error {
+	m.mu.Lock()
+	m.calls = append(m.calls, putMetricDataCall{region, namespace, append([]MetricDatum(nil), data...)})
+	m.mu.Unlock()
+	if m.sent != nil {
+		m.sent <- struct{}{}
+	}
+	return m.err
+}
+
+func (m *mockCloudWatch) Calls() []putMetricDataCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]putMetricDataCall(nil), m.calls...)
+}
+
+// metricValues maps the data points of a call by metric name.
+func metricValues(data []MetricDatum) map[string]float64 {
+	values := make(map[string]float64, len(data))
+	for _, d := range data {
+		values[d.Name] = d.Value
+	}
+	return values
+}
+
+func TestCloudWatchEmitterFlush(t *testing.T) {
+	client := &mockCloudWatch{}
+	cfg := CloudWatchConfig{Region: "eu-west-1", Dimensions: map[string]string{"Environment": "test"}}
+	e := NewCloudWatchEmitter(client, cfg)
+
+	e.RecordValidation(&Result{Status: StatusValid.String(), Duration: 100 * time.Millisecond, Metadata: map[string]interface{}{"smtp_code": 250}})
+	e.RecordValidation(&Result{Status: StatusInvalid.String(), Duration: 300 * time.Millisecond, Metadata: map[string]interface{}{"is_disposable": true}})
+	e.RecordValidation(&Result{Status: StatusError.String(), Duration: 200 * time.Millisecond})
+	e.RecordValidation(&Result{Status: StatusValid.String(), Duration: 200 * time.Millisecond, Metadata: map[string]interface{}{"smtp_code": 550}})
+	e.RecordIPCheck(true)
+	e.RecordIPCheck(false)
+	e.RecordIPCheck(true)
+
+	if err := e.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	calls := client.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("PutMetricData called %d times, want 1 batched call", len(calls))
+	}
+	call := calls[0]
+	if call.region != "eu-west-1" || call.namespace != "AzloValidator" {
+		t.Errorf("published to region %q namespace %q, want eu-west-1 and AzloValidator", call.region, call.namespace)
+	}
+
+	want := map[string]float64{
+		MetricValidationsTotal:   4,
+		MetricValidationDuration: 200,
+		MetricSMTPSuccess:        1,
+		MetricIPCheckCacheHit:    2,
+		MetricDisposableDetected: 1,
+		MetricErrorRate:          25,
+	}
+	if got := metricValues(call.data); !reflect.DeepEqual(got, want) {
+		t.Errorf("metrics = %v, want %v", got, want)
+	}
+	for _, d := range call.data {
+		if d.Dimensions["Environment"] != "test" {
+			t.Errorf("%s dimensions = %v, want Environment=test", d.Name, d.Dimensions)
+		}
+	}
+}
+
+func TestCloudWatchEmitterSkipsIdleIntervals(t *testing.T) {
+	client := &mockCloudWatch{}
+	e := NewCloudWatchEmitter(client, CloudWatchConfig{})
+	if err := e.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if calls := client.Calls(); len(calls) != 0 {
+		t.Errorf("PutMetricData called %d times without activity, want 0", len(calls))
+	}
+}
+
+func TestCloudWatchEmitterResetsAfterFlush(t *testing.T) {
+	client := &mockCloudWatch{}
+	e := NewCloudWatchEmitter(client, CloudWatchConfig{})
+	defer e.Close(context.Background())
+
+	e.RecordValidation(&Result{Status: StatusValid.String()})
+	e.RecordValidation(&Result{Status: StatusValid.String()})
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	e.RecordValidation(&Result{Status: StatusValid.String()})
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := client.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("PutMetricData called %d times, want 2", len(calls))
+	}
+	if got := metricValues(calls[1].data)[MetricValidationsTotal]; got != 1 {
+		t.Errorf("second flush ValidationsTotal = %v, want 1", got)
+	}
+}
+
+func TestCloudWatchEmitterPublishesEveryInterval(t *testing.T) {
+	client := &mockCloudWatch{sent: make(chan struct{}, 1)}
+	e := NewCloudWatchEmitter(client, CloudWatchConfig{Interval: 10 * time.Millisecond})
+	defer e.Close(context.Background())
+
+	e.RecordValidation(&Result{Status: StatusValid.String()})
+	select {
+	case <-client.sent:
+	case <-time.After(time.Second):
+		t.Fatal("metrics were not published within the interval")
+	}
+}
+
+func TestCloudWatchEmitterReturnsClientError(t *testing.T) {
+	errThrottled := errors.New("Throttling: rate exceeded")
+	e := NewCloudWatchEmitter(&mockCloudWatch{err: errThrottled}, CloudWatchConfig{})
+
+	e.RecordValidation(&Result{Status: StatusValid.String()})
+	if err := e.Close(context.Background()); !errors.Is(err, errThrottled) {
+		t.Errorf("Close error = %v, want %v", err, errThrottled)
+	}
+}
+
+func TestCloudWatchEmitterFlushedOnShutdown(t *testing.T) {
+	client := &mockCloudWatch{}
+	emitter := NewCloudWatchEmitter(client, CloudWatchConfig{})
+	defer emitter.Close(context.Background())
+
+	basic := (&ValidatorFactory{DNSResolver: fakeResolver{}}).Build(DefaultValidatorConfig())
+	v := NewEnhancedValidatorWithOptions(WithBasicValidator(basic), WithMetricsEmitter(emitter))
+	v.ValidateEmailWithReputation("not-an-address")
+	v.Shutdown()
+
+	calls := client.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("PutMetricData called %d times by Shutdown, want 1", len(calls))
+	}
+	if got := metricValues(calls[0].data)[MetricValidationsTotal]; got != 1 {
+		t.Errorf("ValidationsTotal = %v, want 1", got)
+	}
+}
@@ -0,0 +1,128 @@
+// File: shared/coalesce.go
+package shared
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"strings"
+)
+
+// DomainCoalescer validates a batch with one representative SMTP check per
+// domain, so a batch of 500 gmail.com addresses doesn't open 500 connections
+// to the same mail servers. Catch-all domains accept every recipient, so a
+// shared answer would be meaningless; their addresses are checked one by one.
+type DomainCoalescer struct {
+	validator *Validator
+}
+
+// NewDomainCoalescer creates a coalescer that uses v's configuration and dependencies.
+func NewDomainCoalescer(v *Validator) *DomainCoalescer {
+	return &DomainCoalescer{validator: v}
+}
+
+// ValidateBatch validates emails, sharing SMTP results between addresses on the same domain.
+// Results using a shared SMTP result have Metadata["smtp_coalesced"] set to true.
+func (c *DomainCoalescer) ValidateBatch(ctx context.Context, emails []string) []*Result {
+	v := c.validator
+	results := make([]*Result, len(emails))
+
+	// Run the non-SMTP checks first and group the survivors by domain
+	groups := make(map[string][]int)
+	var domains []string
+	for i, email := range emails {
+		results[i] = v.validateEmail(ctx, email, false)
+		if Status(results[i].Status) != StatusValid {
+			continue
+		}
+
+		addr := resultAddress(results[i])
+		domain := strings.ToLower(addr[strings.LastIndex(addr, "@")+1:])
+		if _, exists := groups[domain]; !exists {
+			domains = append(domains, domain)
+		}
+		groups[domain] = append(groups[domain], i)
+	}
+
+	if v.config.SMTPTimeout <= 0 {
+		return results
+	}
+
+	for _, domain := range domains {
+		c.checkDomain(ctx, domain, groups[domain], results)
+	}
+
+	return results
+}
+
+// checkDomain runs the SMTP checks for the results at indexes, which all share domain.
+func (c *DomainCoalescer) checkDomain(ctx context.Context, domain string, indexes []int, results []*Result) {
+	v := c.validator
+
+	// Domain info is cached by the non-SMTP checks, so this doesn't hit DNS again
+	info, err := v.LookupDomainInfo(ctx, domain)
+	if err != nil || len(info.MXRecords) == 0 {
+		return
+	}
+	mxRecords := info.MXRecords
+	platform := v.platforms.Detect(mxRecords)
+
+	// Addresses on a known catch-all domain are reported without probing
+	if status, ok := v.smtpCache.GetDomainStatus(domain); ok && status == StatusCatchAll {
+		for _, i := range indexes {
+			v.applyCachedCatchAll(results[i], domain)
+		}
+		return
+	}
+
+	if len(indexes) == 1 {
+		c.checkEach(ctx, mxRecords, platform, indexes, results, 0)
+		return
+	}
+
+	probe := c.probeCatchAll(ctx, domain, mxRecords[0])
+	if probe.Status == StatusValid {
+		v.markCatchAll(domain)
+		for _, i := range indexes {
+			results[i].Metadata["is_catch_all"] = true
+		}
+		c.checkEach(ctx, mxRecords, platform, indexes, results, confidenceCatchAll)
+		return
+	}
+
+	representative := results[indexes[0]]
+	smtpResult := checkSMTPServer(ctx, v.smtpDialer, resultAddress(representative), mxRecords[0].Host, v.config.SMTPTimeout, v.config.SMTP)
+
+	// A server that definitively rejects a made-up address is discriminating
+	if probe.Status == StatusInvalid {
+		smtpResult.Confidence = adjustConfidence(smtpResult.Confidence, confidenceProbeRejected)
+	}
+	applySMTPResult(representative, smtpResult, platform)
+
+	for _, i := range indexes[1:] {
+		results[i].Metadata["smtp_coalesced"] = true
+		applySMTPResult(results[i], smtpResult, platform)
+	}
+}
+
+// checkEach runs a separate SMTP check for every result at indexes,
+// adding confidenceDelta to each result's confidence.
+func (c *DomainCoalescer) checkEach(ctx context.Context, mxRecords []*net.MX, platform Platform, indexes []int, results []*Result, confidenceDelta int) {
+	v := c.validator
+
+	for _, i := range indexes {
+		smtpResult := checkSMTP(ctx, v.smtpDialer, resultAddress(results[i]), mxRecords, v.config.SMTPTimeout, v.config.SMTP)
+		smtpResult.Confidence = adjustConfidence(smtpResult.Confidence, confidenceDelta)
+		applySMTPResult(results[i], smtpResult, platform)
+	}
+}
+
+// probeCatchAll probes the mail server with an address that can't exist. A
+// server that accepts it (StatusValid) accepts every recipient.
+func (c *DomainCoalescer) probeCatchAll(ctx context.Context, domain string, mx *net.MX) SMTPResult {
+	v := c.validator
+
+	probe := fmt.Sprintf("catchall-probe-%016x@%s", rand.Uint64(), domain)
+	return checkSMTPServer(ctx, v.smtpDialer, probe, mx.Host, v.config.SMTPTimeout, v.config.SMTP)
+}
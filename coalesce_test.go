@@ -0,0 +1,85 @@
+package shared
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateBatchCoalescesSMTPPerDomain(t *testing.T) {
+	dialer := &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+		conn := NewMockSMTPConn("220 mock ESMTP ready", map[string]string{"RCPT": "550 5.1.1 no such user"})
+		if strings.HasPrefix(addr, "mx.catchall.io.:") {
+			conn.Replies = nil // accepts every recipient
+		} else {
+			conn.RcptReplies = map[string]string{"alice@acme.io": "250 OK", "bob@acme.io": "250 OK"}
+		}
+		return conn, nil
+	}}
+
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = time.Second
+	cfg.CoalesceSMTPPerDomain = true
+	cfg.SyntaxMode = SyntaxModeRFC5322 // accepts display names
+	v := (&ValidatorFactory{DNSResolver: mxPerDomainResolver{}, SMTPDialer: dialer}).Build(cfg)
+
+	results := v.ValidateBatch([]string{
+		"Alice <alice@acme.io>",
+		"bob@acme.io",
+		"carol@catchall.io",
+		"dave@catchall.io",
+	})
+
+	for i, r := range results {
+		if Status(r.Status) != StatusValid {
+			t.Errorf("results[%d] status = %s (%s), want valid", i, r.Status, r.Reason)
+		}
+	}
+
+	// acme.io: a catch-all probe plus one representative check shared by both addresses
+	if coalesced, _ := results[0].Metadata["smtp_coalesced"].(bool); coalesced {
+		t.Error("representative result is marked smtp_coalesced")
+	}
+	if coalesced, _ := results[1].Metadata["smtp_coalesced"].(bool); !coalesced {
+		t.Error("second acme.io result is not marked smtp_coalesced")
+	}
+
+	// catchall.io: the probe is accepted, so each address is checked on its own
+	for _, r := range results[2:] {
+		if catchAll, _ := r.Metadata["is_catch_all"].(bool); !catchAll {
+			t.Errorf("%s is not marked is_catch_all", r.Email)
+		}
+		if coalesced, _ := r.Metadata["smtp_coalesced"].(bool); coalesced {
+			t.Errorf("%s on a catch-all domain is marked smtp_coalesced", r.Email)
+		}
+	}
+
+	dials := make(map[string]int)
+	for _, addr := range dialer.Dialed() {
+		dials[addr]++
+	}
+	if got := dials["mx.acme.io.:25"]; got != 2 {
+		t.Errorf("mx.acme.io dialed %d times, want 2 (probe and representative)", got)
+	}
+	if got := dials["mx.catchall.io.:25"]; got != 3 {
+		t.Errorf("mx.catchall.io dialed %d times, want 3 (probe and one per address)", got)
+	}
+}
+
+func TestValidateBatchWithoutCoalescing(t *testing.T) {
+	dialer := &MockSMTPDialer{}
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = time.Second
+	v := (&ValidatorFactory{DNSResolver: mxPerDomainResolver{}, SMTPDialer: dialer}).Build(cfg)
+
+	results := v.ValidateBatch([]string{"alice@acme.io", "bob@acme.io"})
+	for _, r := range results {
+		if _, ok := r.Metadata["smtp_coalesced"]; ok {
+			t.Errorf("%s has smtp_coalesced without CoalesceSMTPPerDomain", r.Email)
+		}
+	}
+	if got := len(dialer.Dialed()); got != 2 {
+		t.Errorf("dialed %d times, want one SMTP check per address", got)
+	}
+}
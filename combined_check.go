@@ -0,0 +1,140 @@
+// File: shared/combined_check.go
+package shared
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Weights used to combine the SMTP and IP reputation scores.
+const (
+	combinedSMTPWeight       = 0.6
+	combinedReputationWeight = 0.4
+)
+
+// CombinedCheckResult merges the SMTP mailbox check and the IP reputation
+// checks for an address into a single object.
+type CombinedCheckResult struct {
+	SMTPResult
+	IPReputation []IPReputationResult `json:"ip_reputation"`
+
+	SMTPScore       int `json:"smtp_score"`       // 0-100
+	ReputationScore int `json:"reputation_score"` // 0-100, 100 is a clean reputation
+	Score           int `json:"score"`            // weighted average of the two
+
+	// Result is the lean summary; its Metadata["combined_check"] points back to this struct.
+	Result *Result `json:"-"`
+}
+
+// CombinedCheck runs the basic checks and then the SMTP and IP reputation checks
+// concurrently, merging them into one result. If the basic checks fail, only
+// Result is populated. An error is returned if ctx is done or the mail server
+// IPs can't be resolved.
+func (v *EnhancedValidator) CombinedCheck(ctx context.Context, email string) (*CombinedCheckResult, error) {
+	ctx, _ = ensureRequestID(ctx)
+
+	result := v.basicValidator.validateEmail(ctx, email, false)
+	combined := &CombinedCheckResult{Result: result}
+	if Status(result.Status) != StatusValid {
+		return combined, nil
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	info, err := v.basicValidator.LookupDomainInfo(ctx, domain)
+	if err != nil {
+		return combined, err
+	}
+
+	var (
+		smtpResult SMTPResult
+		ips        []string
+		reputation []IPReputationResult
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		cfg := v.basicValidator.config
+		if cfg.SMTPTimeout <= 0 {
+			smtpResult = SMTPResult{Status: StatusUnknown, Reason: "SMTP probing disabled"}
+			return nil
+		}
+		smtpResult = checkSMTP(gctx, v.basicValidator.smtpDialer, email, info.MXRecords, cfg.SMTPTimeout, cfg.SMTP)
+		return gctx.Err()
+	})
+	g.Go(func() error {
+		serverIPs, err := getMailServerIPs(gctx, v.basicValidator.resolver, domain, v.mailServerIPs)
+		if err != nil {
+			return err
+		}
+		ips = mailServerIPStrings(serverIPs)
+		for _, ip := range ips {
+			reputation = append(reputation, *v.checkIPReputationWithCache(gctx, ip))
+		}
+		return gctx.Err()
+	})
+	if err := g.Wait(); err != nil {
+		return combined, err
+	}
+
+	bulkSender := findBulkSender(v.bulkSenders, info.MXRecords, ips)
+
+	combined.SMTPResult = smtpResult
+	combined.IPReputation = reputation
+	combined.SMTPScore = smtpScore(smtpResult.Status)
+	combined.ReputationScore = reputationScore(reputation, bulkSender != nil)
+	combined.Score = int(math.Round(combinedSMTPWeight*float64(combined.SMTPScore) +
+		combinedReputationWeight*float64(combined.ReputationScore)))
+
+	// Merge into the lean result
+	if smtpResult.Status != StatusUnknown {
+		applySMTPResult(result, smtpResult, v.basicValidator.platforms.Detect(info.MXRecords))
+	}
+	if Status(result.Status) == StatusValid && combined.ReputationScore < 25 { // abuse score above 75
+		result.Status = StatusSuspicious.String()
+		result.Reason = "mail server IP has poor reputation"
+	}
+	if bulkSender != nil {
+		result.Metadata["bulk_sender_provider"] = bulkSender.Name
+	}
+	result.Score = combined.Score
+	result.Metadata["ip_reputation"] = reputation
+	result.Metadata["mail_server_ips"] = ips
+	result.Metadata["combined_check"] = combined
+
+	return combined, nil
+}
+
+// smtpScore converts an SMTP status to a 0-100 score.
+func smtpScore(status Status) int {
+	switch status {
+	case StatusValid:
+		return 100
+	case StatusCatchAll:
+		return 60
+	case StatusInvalid:
+		return 0
+	default:
+		return 50
+	}
+}
+
+// reputationScore converts IP reputation checks to a 0-100 score based on the
+// worst IP. Bulk sender IPs are exempt, like in ValidateEmailWithReputation.
+func reputationScore(reputation []IPReputationResult, bulkSender bool) int {
+	if bulkSender {
+		return 100
+	}
+
+	worst := 0
+	for _, r := range reputation {
+		score := r.AbuseConfidenceScore
+		if r.TotalReports > 50 && score < 76 {
+			score = 76 // many reports count as high risk, matching the reputation check
+		}
+		worst = max(worst, score)
+	}
+	return 100 - worst
+}
@@ -0,0 +1,49 @@
+// File: shared/concurrency.go
+package shared
+
+import (
+	"context"
+	"sync/atomic"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// defaultMaxConcurrentValidations is used when ValidatorConfig.MaxConcurrentValidations is zero
+const defaultMaxConcurrentValidations = 100
+
+// concurrencyLimiter bounds the number of validations running at once across
+// all callers of a Validator, so large batches can't exhaust sockets or memory.
+type concurrencyLimiter struct {
+	sem     *semaphore.Weighted
+	max     int
+	current atomic.Int64
+}
+
+// newConcurrencyLimiter creates a limiter admitting max validations. Zero or
+// negative values use defaultMaxConcurrentValidations.
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	if max <= 0 {
+		max = defaultMaxConcurrentValidations
+	}
+	return &concurrencyLimiter{sem: semaphore.NewWeighted(int64(max)), max: max}
+}
+
+// acquire waits for a free slot, returning ctx's error if it is done first.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	if err := l.sem.Acquire(ctx, 1); err != nil {
+		return err
+	}
+	l.current.Add(1)
+	return nil
+}
+
+// release frees a slot taken by acquire.
+func (l *concurrencyLimiter) release() {
+	l.current.Add(-1)
+	l.sem.Release(1)
+}
+
+// ConcurrentValidations returns the number of validations currently running.
+func (v *Validator) ConcurrentValidations() int64 {
+	return v.limiter.current.Load()
+}
@@ -0,0 +1,143 @@
+// File: shared/config.go
+package shared
+
+import (
+	"runtime"
+	"time"
+)
+
+// ValidatorConfig holds the tunable settings shared by Validator and EnhancedValidator.
+type ValidatorConfig struct {
+	// TenantID namespaces the EnhancedValidator's caches. Empty uses the shared namespace.
+	// A tenant ID in the validation context (see WithTenantID) takes precedence.
+	TenantID string `json:"tenant_id,omitempty" yaml:"tenant_id,omitempty"`
+
+	// SMTPTimeout bounds each SMTP conversation. Zero disables SMTP mailbox probing.
+	SMTPTimeout time.Duration `json:"smtp_timeout" yaml:"smtp_timeout"`
+
+	// SyntaxMode selects the format check; empty means SyntaxModePragmatic.
+	SyntaxMode SyntaxMode `json:"syntax_mode,omitempty" yaml:"syntax_mode,omitempty"`
+
+	// ValidationTimeout bounds each ValidateEmail call, DNS and SMTP included.
+	// Zero leaves it bounded by the individual DNS and SMTP timeouts only.
+	ValidationTimeout time.Duration `json:"validation_timeout" yaml:"validation_timeout"`
+
+	// DNSTimeoutFraction is the share of a validation deadline given to the
+	// DNS checks by ValidateEmailContext, between 0 and 1. Zero leaves DNS
+	// bounded by the deadline only.
+	DNSTimeoutFraction float64 `json:"dns_timeout_fraction" yaml:"dns_timeout_fraction"`
+
+	// CoalesceSMTPPerDomain makes ValidateBatch run one representative SMTP check
+	// per domain instead of one per address. Catch-all domains are still checked per address.
+	CoalesceSMTPPerDomain bool `json:"coalesce_smtp_per_domain" yaml:"coalesce_smtp_per_domain"`
+
+	// DomainInfoTTL is how long DNS facts about a domain are cached. Zero uses 5 minutes.
+	DomainInfoTTL time.Duration `json:"domain_info_ttl" yaml:"domain_info_ttl"`
+
+	// CatchAllCacheTTL is how long a domain found to accept every recipient
+	// is reported as catch-all without further SMTP probes. Zero uses 24 hours.
+	CatchAllCacheTTL time.Duration `json:"catch_all_cache_ttl" yaml:"catch_all_cache_ttl"`
+
+	// IDNA selects the standard internationalized domains are validated against.
+	IDNA IDNAConfig `json:"idna" yaml:"idna"`
+
+	// SMTP holds the settings used for SMTP mailbox probing.
+	SMTP SMTPConfig `json:"smtp" yaml:"smtp"`
+
+	// SMTPBehaviors describes providers whose SMTP answers need special
+	// handling. Nil uses the database embedded in the package (see
+	// DefaultSMTPBehaviorDB); an empty map disables the overrides.
+	SMTPBehaviors SMTPBehaviorDB `json:"smtp_behaviors" yaml:"smtp_behaviors,omitempty"`
+
+	// GraylistRetryAfter is how long to wait before retrying a greylisted address.
+	GraylistRetryAfter time.Duration `json:"graylist_retry_after" yaml:"graylist_retry_after"`
+
+	// GraylistMaxRetries is the number of retries attempted before a final result is published.
+	GraylistMaxRetries int `json:"graylist_max_retries" yaml:"graylist_max_retries"`
+
+	// DisposableDomains lists disposable email domains. Nil uses the list
+	// embedded in the package (see LoadDisposableDomainsFromEmbed).
+	DisposableDomains map[string]bool `json:"disposable_domains" yaml:"disposable_domains,omitempty"`
+
+	// FreeProviderDomains lists consumer/free-tier provider domains. Nil uses DefaultFreeProviderDomains.
+	FreeProviderDomains map[string]bool `json:"free_provider_domains" yaml:"free_provider_domains,omitempty"`
+
+	// CarrierDomains maps mobile carrier SMS gateway domains to the carrier name. Nil uses DefaultCarrierDomains.
+	CarrierDomains map[string]string `json:"carrier_domains" yaml:"carrier_domains,omitempty"`
+
+	// EduGovTLDs lists additional educational/government suffixes (e.g. "ac.at", "gov.sg").
+	// Each suffix is classified by its leading label ("edu", "ac" => edu; "gov", "gouv" => gov).
+	EduGovTLDs []string `json:"edu_gov_tlds,omitempty" yaml:"edu_gov_tlds,omitempty"`
+
+	// SuspiciousLocalPartPatterns lists the checks flagging random-looking local
+	// parts: the names all_digits, high_entropy and too_long, or regular
+	// expressions. Nil uses DefaultSuspiciousLocalPartPatterns; empty disables the check.
+	SuspiciousLocalPartPatterns []string `json:"suspicious_local_part_patterns" yaml:"suspicious_local_part_patterns,omitempty"`
+
+	// LocalPartEntropyThreshold is the Shannon entropy (bits per character)
+	// above which high_entropy matches. Zero uses 4.0.
+	LocalPartEntropyThreshold float64 `json:"local_part_entropy_threshold,omitempty" yaml:"local_part_entropy_threshold,omitempty"`
+
+	// EnableProfanityCheck rejects addresses whose local part contains a word
+	// of ProfanityWordlist; see ContainsProfanity. No wordlist is shipped with
+	// the package, so callers enabling the check must provide their own.
+	EnableProfanityCheck bool     `json:"enable_profanity_check" yaml:"enable_profanity_check"`
+	ProfanityWordlist    []string `json:"profanity_wordlist,omitempty" yaml:"profanity_wordlist,omitempty"`
+
+	// EnableEntropyCheck scores how likely each local part is bot generated
+	// (see EntropyBasedBotScore), recorded as Metadata["bot_score"] and
+	// ValidationReport.BotScore and lowering report scores. Bot scores above
+	// 50 mean local part entropy above EntropyBotThreshold; zero uses 3.8.
+	EnableEntropyCheck  bool    `json:"enable_entropy_check" yaml:"enable_entropy_check"`
+	EntropyBotThreshold float64 `json:"entropy_bot_threshold,omitempty" yaml:"entropy_bot_threshold,omitempty"`
+
+	// RiskConfig holds the IP risk settings, e.g. RecentReportThreshold.
+	RiskConfig `yaml:",inline"`
+
+	// ReputationAggregation selects how many high risk mail server IPs flag a
+	// domain as suspicious. Empty uses AggModeAny.
+	ReputationAggregation AggregationMode `json:"reputation_aggregation" yaml:"reputation_aggregation"`
+
+	// EnableSubnetAggregation also weighs previously checked IPs in the same
+	// /24 as a domain's mail servers. If all its mail servers share one /24 and
+	// any known IP there is high risk, the domain is marked suspicious.
+	EnableSubnetAggregation bool `json:"enable_subnet_aggregation" yaml:"enable_subnet_aggregation"`
+
+	// ScoringWeights sets how much each check contributes to ValidationReport scores.
+	ScoringWeights ScoringWeights `json:"scoring_weights" yaml:"scoring_weights"`
+
+	// LogAnonymizeMode controls how email addresses are redacted in log messages.
+	LogAnonymizeMode AnonymizeMode `json:"log_anonymize_mode" yaml:"log_anonymize_mode"`
+
+	// MaxConcurrentValidations caps how many validations a Validator runs at
+	// once, across ValidateEmail, ValidateBatch and all other callers. Zero uses 100.
+	MaxConcurrentValidations int `json:"max_concurrent_validations" yaml:"max_concurrent_validations"`
+
+	// AsyncWorkers is the number of goroutines running ValidateEmailAsync jobs. Zero uses runtime.NumCPU().
+	AsyncWorkers int `json:"async_workers" yaml:"async_workers"`
+
+	// WarmupIPListPath points to a file of historically common mail server IPs
+	// (one per line) used to pre-populate the IP reputation cache at startup.
+	WarmupIPListPath string `json:"warmup_ip_list_path,omitempty" yaml:"warmup_ip_list_path,omitempty"`
+}
+
+// DefaultValidatorConfig returns the recommended configuration, the starting
+// point for the one passed to NewValidator.
+func DefaultValidatorConfig() ValidatorConfig {
+	return ValidatorConfig{
+		SMTPTimeout:           0, // SMTP probing is opt-in
+		DNSTimeoutFraction:    0.3,
+		SMTP:                  DefaultSMTPConfig(),
+		IDNA:                  DefaultIDNAConfig(),
+		DomainInfoTTL:         defaultDomainInfoTTL,
+		GraylistRetryAfter:    5 * time.Minute,
+		GraylistMaxRetries:    3,
+		RiskConfig:            DefaultRiskConfig(),
+		ReputationAggregation: AggModeAny,
+		ScoringWeights:        DefaultScoringWeights(),
+		LogAnonymizeMode:      AnonymizeMask,
+		AsyncWorkers:          runtime.NumCPU(),
+
+		MaxConcurrentValidations: defaultMaxConcurrentValidations,
+	}
+}
@@ -0,0 +1,96 @@
+// File: shared/config_json.go
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// validatorConfigJSON has the same fields as ValidatorConfig but none of its
+// methods, so it can be encoded and decoded without recursing into them.
+type validatorConfigJSON ValidatorConfig
+
+// jsonDuration encodes a time.Duration as a human-readable string (e.g. "30s").
+type jsonDuration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON accepts a duration string or integer nanoseconds, the
+// encoding/json default for time.Duration.
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = jsonDuration(parsed)
+		return nil
+	}
+
+	nanos, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = jsonDuration(nanos)
+	return nil
+}
+
+// validatorConfigJSONDurations overrides the duration fields of
+// ValidatorConfig, which encoding/json would write as integer nanoseconds.
+// Fields at this level take precedence over the embedded ones of the same
+// name. New duration fields must be added here and in the two methods below.
+type validatorConfigJSONDurations struct {
+	*validatorConfigJSON
+	SMTPTimeout           jsonDuration `json:"smtp_timeout"`
+	ValidationTimeout     jsonDuration `json:"validation_timeout"`
+	DomainInfoTTL         jsonDuration `json:"domain_info_ttl"`
+	CatchAllCacheTTL      jsonDuration `json:"catch_all_cache_ttl"`
+	GraylistRetryAfter    jsonDuration `json:"graylist_retry_after"`
+	RecentReportThreshold jsonDuration `json:"recent_report_threshold"`
+}
+
+// withJSONDurations wraps cfg for encoding or decoding its durations as strings.
+func withJSONDurations(cfg *ValidatorConfig) *validatorConfigJSONDurations {
+	return &validatorConfigJSONDurations{
+		validatorConfigJSON:   (*validatorConfigJSON)(cfg),
+		SMTPTimeout:           jsonDuration(cfg.SMTPTimeout),
+		ValidationTimeout:     jsonDuration(cfg.ValidationTimeout),
+		DomainInfoTTL:         jsonDuration(cfg.DomainInfoTTL),
+		CatchAllCacheTTL:      jsonDuration(cfg.CatchAllCacheTTL),
+		GraylistRetryAfter:    jsonDuration(cfg.GraylistRetryAfter),
+		RecentReportThreshold: jsonDuration(cfg.RecentReportThreshold),
+	}
+}
+
+// MarshalJSON encodes the configuration with durations as human-readable strings (e.g. "30s").
+func (cfg ValidatorConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(withJSONDurations(&cfg))
+}
+
+// UnmarshalJSON decodes the configuration. Duration fields accept either
+// duration strings (e.g. "30s") or integer nanoseconds. Fields that are not
+// present keep their current values.
+func (cfg *ValidatorConfig) UnmarshalJSON(data []byte) error {
+	wrapped := withJSONDurations(cfg)
+	if err := json.Unmarshal(data, wrapped); err != nil {
+		return err
+	}
+
+	cfg.SMTPTimeout = time.Duration(wrapped.SMTPTimeout)
+	cfg.ValidationTimeout = time.Duration(wrapped.ValidationTimeout)
+	cfg.DomainInfoTTL = time.Duration(wrapped.DomainInfoTTL)
+	cfg.CatchAllCacheTTL = time.Duration(wrapped.CatchAllCacheTTL)
+	cfg.GraylistRetryAfter = time.Duration(wrapped.GraylistRetryAfter)
+	cfg.RecentReportThreshold = time.Duration(wrapped.RecentReportThreshold)
+	return nil
+}
@@ -0,0 +1,59 @@
+// File: shared/config_presets.go
+package shared
+
+import (
+	"time"
+)
+
+// QuickValidationConfig checks syntax and DNS only, within 2 seconds per
+// address. Fastest preset, suited to interactive form validation; mailboxes
+// are not verified, so addresses on valid domains pass even if the mailbox
+// doesn't exist.
+func QuickValidationConfig() ValidatorConfig {
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = 0
+	cfg.ValidationTimeout = 2 * time.Second
+	cfg.DNSTimeoutFraction = 0.9 // no SMTP to leave time for
+	return cfg
+}
+
+// StrictValidationConfig runs every check, including SMTP mailbox probing of
+// up to three MX servers, within 30 seconds per address. It flags random
+// looking local parts more readily and treats an IP's reports as recent for
+// longer. Most accurate, but slow and prone to greylisting; suited to
+// checking high-value sign-ups one at a time.
+func StrictValidationConfig() ValidatorConfig {
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = 10 * time.Second
+	cfg.ValidationTimeout = 30 * time.Second
+	cfg.SMTP.MaxMXAttempts = 3
+	cfg.LocalPartEntropyThreshold = 3.5
+	cfg.RecentReportThreshold = 30 * 24 * time.Hour
+	cfg.ReputationAggregation = AggModeAny
+	return cfg
+}
+
+// BulkValidationConfig checks syntax, DNS and disposable domains only, with
+// 1 second per address and up to 1000 validations in flight. Built for list
+// cleaning, where throughput matters more than catching every bad mailbox;
+// advisory local part checks are turned off.
+func BulkValidationConfig() ValidatorConfig {
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = 0
+	cfg.ValidationTimeout = time.Second
+	cfg.DNSTimeoutFraction = 0.9
+	cfg.MaxConcurrentValidations = 1000
+	cfg.SuspiciousLocalPartPatterns = []string{}
+	return cfg
+}
+
+// ComplianceValidationConfig runs every check like StrictValidationConfig
+// and hashes addresses in log output. DMARC and the other email
+// authentication records are looked up as part of the DNS checks. Audit
+// logging is configured on the EnhancedValidator, so pair this preset with
+// WithAuditLogger. The package has no domain age check.
+func ComplianceValidationConfig() ValidatorConfig {
+	cfg := StrictValidationConfig()
+	cfg.LogAnonymizeMode = AnonymizeHash
+	return cfg
+}
@@ -0,0 +1,178 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fullValidatorConfig returns a config with every field set to a value other
+// than its zero value and its default.
+func fullValidatorConfig() ValidatorConfig {
+	return ValidatorConfig{
+		TenantID:              "tenant-1",
+		SMTPTimeout:           7 * time.Second,
+		SyntaxMode:            SyntaxModeRFC5322,
+		ValidationTimeout:     1500 * time.Millisecond,
+		DNSTimeoutFraction:    0.25,
+		CoalesceSMTPPerDomain: true,
+		DomainInfoTTL:         90 * time.Second,
+		CatchAllCacheTTL:      36 * time.Hour,
+		IDNA:                  IDNAConfig{Standard: IDNA2003},
+		SMTP: SMTPConfig{
+			Port:               2525,
+			HeloDomain:         "probe.acme.io",
+			FromEmail:          "probe@acme.io",
+			EnablePipelining:   true,
+			UseLMTP:            true,
+			LMTPPort:           2424,
+			UseEHLO:            true,
+			UseVRFY:            true,
+			RecordConversation: true,
+			MaxMXAttempts:      4,
+			UseSTARTTLS:        true,
+			VerifyTLSHostname:  true,
+		},
+		SMTPBehaviors: SMTPBehaviorDB{
+			"acme": {MXPatterns: []string{"*.mx.acme.io"}, IsCatchAll: true, ThrottlesDensely: true, RequiresTLS: true, RejectsHELO: true, UseVRFY: true},
+		},
+		GraylistRetryAfter:          10 * time.Minute,
+		GraylistMaxRetries:          5,
+		DisposableDomains:           map[string]bool{"throwaway.io": true},
+		FreeProviderDomains:         map[string]bool{"freemail.io": true},
+		CarrierDomains:              map[string]string{"sms.carrier.io": "Carrier"},
+		EduGovTLDs:                  []string{"ac.at", "gov.sg"},
+		SuspiciousLocalPartPatterns: []string{"all_digits", `^x{5,}$`},
+		LocalPartEntropyThreshold:   4.5,
+		EnableProfanityCheck:        true,
+		ProfanityWordlist:           []string{"darn"},
+		EnableEntropyCheck:          true,
+		EntropyBotThreshold:         3.5,
+		RiskConfig: RiskConfig{
+			RecentReportThreshold: 72 * time.Hour,
+			DistinctUsersWeight:   0.75,
+		},
+		ReputationAggregation:   AggModeMajority,
+		EnableSubnetAggregation: true,
+		ScoringWeights: ScoringWeights{
+			Format: 0.1, DomainDNS: 0.2, MXReachable: 0.15, SMTP: 0.25, IPReputation: 0.1,
+			DMARC: 0.05, SPF: 0.05, DomainAge: 0.1, NumericLocalPenalty: 0.2,
+		},
+		LogAnonymizeMode:         AnonymizeHash,
+		MaxConcurrentValidations: 32,
+		AsyncWorkers:             3,
+		WarmupIPListPath:         "/etc/azlo/warmup_ips.txt",
+	}
+}
+
+// zeroFields returns the names of the fields of v, a struct, that hold their
+// zero value, descending into nested structs.
+func zeroFields(v reflect.Value, prefix string) []string {
+	var zero []string
+	for i := 0; i < v.NumField(); i++ {
+		field, name := v.Field(i), prefix+v.Type().Field(i).Name
+		switch {
+		case field.Kind() == reflect.Struct:
+			zero = append(zero, zeroFields(field, name+".")...)
+		case field.IsZero():
+			zero = append(zero, name)
+		}
+	}
+	return zero
+}
+
+func TestValidatorConfigRoundTrip(t *testing.T) {
+	full := fullValidatorConfig()
+	if zero := zeroFields(reflect.ValueOf(full), ""); len(zero) > 0 {
+		t.Fatalf("fullValidatorConfig leaves %v unset", zero)
+	}
+
+	emptyCollections := DefaultValidatorConfig()
+	emptyCollections.SMTPBehaviors = SMTPBehaviorDB{}
+	emptyCollections.DisposableDomains = map[string]bool{}
+	emptyCollections.SuspiciousLocalPartPatterns = []string{}
+
+	configs := []struct {
+		name string
+		cfg  ValidatorConfig
+	}{
+		{"fully populated", full},
+		{"default", DefaultValidatorConfig()},
+		{"empty collections", emptyCollections},
+	}
+
+	for _, tt := range configs {
+		t.Run(tt.name+"/JSON", func(t *testing.T) {
+			data, err := json.Marshal(tt.cfg)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got ValidatorConfig
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cfg) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.cfg)
+			}
+		})
+
+		t.Run(tt.name+"/YAML", func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteValidatorConfigToYAML(&buf, tt.cfg); err != nil {
+				t.Fatal(err)
+			}
+			var got ValidatorConfig
+			if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cfg) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.cfg)
+			}
+		})
+	}
+}
+
+func TestValidatorConfigDurationEncoding(t *testing.T) {
+	cfg := fullValidatorConfig()
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if got := fields["smtp_timeout"]; got != "7s" {
+		t.Errorf("JSON smtp_timeout = %v, want \"7s\"", got)
+	}
+	if got := fields["recent_report_threshold"]; got != "72h0m0s" {
+		t.Errorf("JSON recent_report_threshold = %v, want \"72h0m0s\"", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteValidatorConfigToYAML(&buf, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "smtp_timeout: 7s\n") {
+		t.Errorf("YAML does not write smtp_timeout as 7s:\n%s", buf.String())
+	}
+
+	// Integer nanoseconds, as encoding/json writes a time.Duration, are accepted too
+	var fromJSON ValidatorConfig
+	if err := json.Unmarshal([]byte(`{"smtp_timeout": 7000000000}`), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	var fromYAML ValidatorConfig
+	if err := yaml.Unmarshal([]byte("smtp_timeout: 7000000000\n"), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON.SMTPTimeout != 7*time.Second || fromYAML.SMTPTimeout != 7*time.Second {
+		t.Errorf("SMTPTimeout from nanoseconds = %v (JSON), %v (YAML), want 7s", fromJSON.SMTPTimeout, fromYAML.SMTPTimeout)
+	}
+}
@@ -0,0 +1,128 @@
+// File: shared/config_validate.go
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// maxGraylistRetries caps GraylistMaxRetries so a misconfiguration can't keep retrying for hours
+const maxGraylistRetries = 10
+
+// ErrInvalidConfig is returned by ValidatorConfig.Validate. Use errors.Is to check for it.
+var ErrInvalidConfig = errors.New("invalid validator config")
+
+// Validate checks the configuration for values that would make validation
+// misbehave, e.g. negative timeouts or malformed domain list entries. It
+// reports every problem found, each wrapping ErrInvalidConfig.
+func (cfg ValidatorConfig) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidConfig, fmt.Sprintf(format, args...)))
+	}
+
+	if cfg.SMTPTimeout < 0 {
+		invalid("smtp_timeout must not be negative (zero disables SMTP probing)")
+	}
+	if cfg.SMTPTimeout > 0 {
+		if port := cfg.SMTP.port(); port <= 0 || port > 65535 {
+			invalid("smtp port %d is out of range", port)
+		}
+	}
+	if cfg.ValidationTimeout < 0 {
+		invalid("validation_timeout must not be negative")
+	}
+	if cfg.DNSTimeoutFraction < 0 || cfg.DNSTimeoutFraction >= 1 {
+		invalid("dns_timeout_fraction must be at least 0 and below 1, got %v", cfg.DNSTimeoutFraction)
+	}
+	if cfg.DomainInfoTTL < 0 {
+		invalid("domain_info_ttl must not be negative")
+	}
+	if cfg.CatchAllCacheTTL < 0 {
+		invalid("catch_all_cache_ttl must not be negative")
+	}
+	if cfg.GraylistRetryAfter < 0 {
+		invalid("graylist_retry_after must not be negative")
+	}
+	if cfg.GraylistMaxRetries < 0 || cfg.GraylistMaxRetries > maxGraylistRetries {
+		invalid("graylist_max_retries must be between 0 and %d, got %d", maxGraylistRetries, cfg.GraylistMaxRetries)
+	}
+	if cfg.MaxConcurrentValidations < 0 {
+		invalid("max_concurrent_validations must not be negative")
+	}
+	if cfg.AsyncWorkers < 0 {
+		invalid("async_workers must not be negative")
+	}
+	if cfg.LocalPartEntropyThreshold < 0 {
+		invalid("local_part_entropy_threshold must not be negative")
+	}
+	if cfg.EntropyBotThreshold < 0 {
+		invalid("entropy_bot_threshold must not be negative")
+	}
+	if cfg.RecentReportThreshold < 0 {
+		invalid("recent_report_threshold must not be negative")
+	}
+	if cfg.DistinctUsersWeight < 0 {
+		invalid("distinct_users_weight must not be negative")
+	}
+
+	switch cfg.IDNA.Standard {
+	case "", IDNA2003, IDNA2008:
+	default:
+		invalid("unknown idna standard %q", cfg.IDNA.Standard)
+	}
+	switch cfg.ReputationAggregation {
+	case "", AggModeAny, AggModeAll, AggModeMajority, AggModeAverage:
+	default:
+		invalid("unknown reputation_aggregation %q", cfg.ReputationAggregation)
+	}
+	if cfg.EnableProfanityCheck && len(cfg.ProfanityWordlist) == 0 {
+		invalid("enable_profanity_check requires a profanity_wordlist")
+	}
+	switch cfg.SyntaxMode {
+	case "", SyntaxModePragmatic, SyntaxModeRFC5322:
+	default:
+		invalid("unknown syntax_mode %q", cfg.SyntaxMode)
+	}
+	switch cfg.LogAnonymizeMode {
+	case "", AnonymizeMask, AnonymizeHash, AnonymizeDomainOnly:
+	default:
+		invalid("unknown log_anonymize_mode %q", cfg.LogAnonymizeMode)
+	}
+
+	for domain := range cfg.DisposableDomains {
+		if !domainRegex.MatchString(domain) {
+			invalid("disposable_domains entry %q is not a valid domain", domain)
+		}
+	}
+	for domain := range cfg.FreeProviderDomains {
+		if !domainRegex.MatchString(domain) {
+			invalid("free_provider_domains entry %q is not a valid domain", domain)
+		}
+	}
+	for domain := range cfg.CarrierDomains {
+		if !domainRegex.MatchString(domain) {
+			invalid("carrier_domains entry %q is not a valid domain", domain)
+		}
+	}
+	for name, behavior := range cfg.SMTPBehaviors {
+		for _, pattern := range behavior.MXPatterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				invalid("smtp_behaviors %s: invalid MX pattern %q", name, pattern)
+			}
+		}
+	}
+	for _, pattern := range cfg.SuspiciousLocalPartPatterns {
+		switch pattern {
+		case LocalPartAllDigits, LocalPartHighEntropy, LocalPartTooLong:
+		default:
+			if _, err := regexp.Compile(pattern); err != nil {
+				invalid("suspicious_local_part_patterns entry %q: %v", pattern, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewValidatorValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*ValidatorConfig)
+		wantErr bool
+	}{
+		{"default", func(*ValidatorConfig) {}, false},
+		{"SMTP disabled", func(c *ValidatorConfig) { c.SMTPTimeout = 0 }, false},
+		{"negative SMTP timeout", func(c *ValidatorConfig) { c.SMTPTimeout = -time.Second }, true},
+		{"SMTP port out of range", func(c *ValidatorConfig) { c.SMTPTimeout, c.SMTP.Port = time.Second, 70000 }, true},
+		{"negative greylist retries", func(c *ValidatorConfig) { c.GraylistMaxRetries = -1 }, true},
+		{"too many greylist retries", func(c *ValidatorConfig) { c.GraylistMaxRetries = maxGraylistRetries + 1 }, true},
+		{"DNS timeout fraction of 1", func(c *ValidatorConfig) { c.DNSTimeoutFraction = 1 }, true},
+		{"unknown syntax mode", func(c *ValidatorConfig) { c.SyntaxMode = "strict" }, true},
+		{"malformed disposable domain", func(c *ValidatorConfig) { c.DisposableDomains = map[string]bool{"not a domain": true} }, true},
+		{"profanity check without wordlist", func(c *ValidatorConfig) { c.EnableProfanityCheck = true }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultValidatorConfig()
+			tt.modify(&cfg)
+
+			v, err := NewValidator(cfg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidConfig) {
+					t.Errorf("NewValidator error = %v, want ErrInvalidConfig", err)
+				}
+				if v != nil {
+					t.Error("NewValidator returned a validator with an error")
+				}
+				return
+			}
+			if err != nil || v == nil {
+				t.Fatalf("NewValidator = %v, %v; want a validator", v, err)
+			}
+		})
+	}
+}
+
+func TestNewEnhancedValidatorWithOptionsEValidatesConfig(t *testing.T) {
+	invalid := DefaultValidatorConfig()
+	invalid.SMTPTimeout = -time.Second
+
+	v, err := NewEnhancedValidatorWithOptionsE(WithValidatorConfig(invalid))
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewEnhancedValidatorWithOptionsE error = %v, want ErrInvalidConfig", err)
+	}
+	if v != nil {
+		v.Shutdown()
+		t.Error("NewEnhancedValidatorWithOptionsE returned a validator with an error")
+	}
+
+	valid := DefaultValidatorConfig()
+	valid.SMTPTimeout = 0
+	v, err = NewEnhancedValidatorWithOptionsE(WithValidatorConfig(valid))
+	if err != nil {
+		t.Fatalf("NewEnhancedValidatorWithOptionsE: %v", err)
+	}
+	defer v.Shutdown()
+	if got := v.basicValidator.config.SMTPTimeout; got != 0 {
+		t.Errorf("SMTPTimeout = %v, want the configured 0", got)
+	}
+}
@@ -0,0 +1,137 @@
+// File: shared/config_yaml.go
+package shared
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validatorConfigYAML has the same fields as ValidatorConfig but none of its
+// methods, so it can be encoded and decoded without recursing into them.
+type validatorConfigYAML ValidatorConfig
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// MarshalYAML encodes the configuration with durations as human-readable strings (e.g. "30s").
+func (cfg ValidatorConfig) MarshalYAML() (interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode(validatorConfigYAML(cfg)); err != nil {
+		return nil, err
+	}
+	keepEmptyCollections(&node, reflect.ValueOf(cfg))
+	return &node, nil
+}
+
+// keepEmptyCollections adds the empty, non-nil lists and maps of v that
+// omitempty dropped from node. For fields such as DisposableDomains nil means
+// "use the default" while empty disables the check, so both must survive a
+// round trip.
+func keepEmptyCollections(node *yaml.Node, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if kind := field.Kind(); (kind != reflect.Slice && kind != reflect.Map) || field.IsNil() || field.Len() > 0 {
+			continue
+		}
+		name, opts, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || !strings.Contains(opts, "omitempty") {
+			continue
+		}
+
+		value := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		if field.Kind() == reflect.Map {
+			value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Style: yaml.FlowStyle}
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, value)
+	}
+}
+
+// UnmarshalYAML decodes the configuration. Duration fields accept either
+// duration strings (e.g. "30s") or integer nanoseconds as produced by JSON.
+func (cfg *ValidatorConfig) UnmarshalYAML(value *yaml.Node) error {
+	if err := normalizeYAMLDurations(value, reflect.TypeOf(*cfg)); err != nil {
+		return err
+	}
+	return value.Decode((*validatorConfigYAML)(cfg))
+}
+
+// LoadValidatorConfigFromYAML reads a ValidatorConfig from YAML. Fields that
+// are not present keep their DefaultValidatorConfig values. The result is
+// checked with ValidatorConfig.Validate.
+func LoadValidatorConfigFromYAML(r io.Reader) (ValidatorConfig, error) {
+	cfg := DefaultValidatorConfig()
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && err != io.EOF {
+		return ValidatorConfig{}, fmt.Errorf("failed to decode validator config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return ValidatorConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// WriteValidatorConfigToYAML writes the configuration as YAML.
+func WriteValidatorConfigToYAML(w io.Writer, cfg ValidatorConfig) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode validator config: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+// normalizeYAMLDurations rewrites integer scalars for duration fields of t
+// (including nested structs) into duration strings that yaml.v3 can decode.
+func normalizeYAMLDurations(node *yaml.Node, t reflect.Type) error {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fieldType, ok := fields[key.Value]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case fieldType == durationType && value.Kind == yaml.ScalarNode && value.Tag == "!!int":
+			nanos, err := strconv.ParseInt(value.Value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid duration for %s: %w", key.Value, err)
+			}
+			value.Value = time.Duration(nanos).String()
+			value.Tag = "!!str"
+		case fieldType.Kind() == reflect.Struct:
+			if err := normalizeYAMLDurations(value, fieldType); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
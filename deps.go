@@ -0,0 +1,56 @@
+// File: shared/deps.go
+package shared
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DNSResolver performs the DNS lookups needed for validation. *net.Resolver implements it.
+type DNSResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// SMTPDialer opens TCP connections to mail servers. NetSMTPDialer is the
+// production implementation; tests can supply connections that replay
+// scripted server responses.
+type SMTPDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// HTTPDoer sends HTTP requests. *http.Client implements it.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DisposableProvider reports whether a domain belongs to a disposable email service.
+type DisposableProvider interface {
+	IsDisposable(ctx context.Context, domain string) (bool, error)
+}
+
+// DefaultDisposableDomains lists common disposable email domains.
+var DefaultDisposableDomains = map[string]bool{
+	"10minutemail.com": true, "guerrillamail.com": true, "mailinator.com": true,
+	"tempmail.org": true, "throwaway.email": true, "yopmail.com": true,
+	"temp-mail.org": true, "getairmail.com": true, "sharklasers.com": true,
+}
+
+// StaticDisposableProvider checks domains against a fixed set of disposable domains.
+type StaticDisposableProvider struct {
+	domains map[string]bool
+}
+
+// NewStaticDisposableProvider creates a provider backed by the given domain set.
+func NewStaticDisposableProvider(domains map[string]bool) *StaticDisposableProvider {
+	return &StaticDisposableProvider{domains: domains}
+}
+
+// IsDisposable checks if the domain is in the provider's set.
+func (p *StaticDisposableProvider) IsDisposable(ctx context.Context, domain string) (bool, error) {
+	return IsDisposable(strings.TrimSpace(domain), p.domains), nil
+}
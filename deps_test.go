@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"context"
+	"net"
+)
+
+// fakeResolver answers MX lookups with mx and err and address lookups with
+// ips. Lookups without configured answers fail as not found.
+type fakeResolver struct {
+	mx  []*net.MX
+	err error
+	ips []net.IP
+}
+
+func (r fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return r.mx, r.err
+}
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if len(r.ips) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	addrs := make([]string, len(r.ips))
+	for i, ip := range r.ips {
+		addrs[i] = ip.String()
+	}
+	return addrs, nil
+}
+
+func (r fakeResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	if len(r.ips) == 0 || network == "ip6" {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return r.ips, nil
+}
+
+func (r fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+// mxPerDomainResolver resolves every domain to a TEST-NET address with the
+// single MX host "mx.<domain>".
+type mxPerDomainResolver struct{}
+
+func (mxPerDomainResolver) resolver(name string) fakeResolver {
+	return fakeResolver{
+		mx:  []*net.MX{{Host: "mx." + name + ".", Pref: 10}},
+		ips: []net.IP{net.ParseIP("192.0.2.1")},
+	}
+}
+
+func (r mxPerDomainResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return r.resolver(name).LookupMX(ctx, name)
+}
+
+func (r mxPerDomainResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.resolver(host).LookupHost(ctx, host)
+}
+
+func (r mxPerDomainResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return r.resolver(host).LookupIP(ctx, network, host)
+}
+
+func (r mxPerDomainResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.resolver(name).LookupTXT(ctx, name)
+}
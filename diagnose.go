@@ -0,0 +1,140 @@
+// File: shared/diagnose.go
+package shared
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Format error codes reported by DiagnoseFormatErrors.
+const (
+	FormatErrWhitespace        = "LEADING_TRAILING_WHITESPACE"
+	FormatErrSpaceInLocalPart  = "SPACE_IN_LOCAL_PART"
+	FormatErrSpaceInDomain     = "SPACE_IN_DOMAIN"
+	FormatErrDoubleAt          = "DOUBLE_AT_SIGN"
+	FormatErrConsecutiveDots   = "CONSECUTIVE_DOTS"
+	FormatErrLeadingDotLocal   = "LEADING_DOT_IN_LOCAL_PART"
+	FormatErrLeadingDotDomain  = "LEADING_DOT_IN_DOMAIN"
+	FormatErrTrailingDotDomain = "TRAILING_DOT_IN_DOMAIN"
+)
+
+// FormatError describes a common typing mistake found in an email address.
+type FormatError struct {
+	Code       string `json:"code"`
+	Suggestion string `json:"suggestion"`
+	FixedEmail string `json:"fixed_email,omitempty"` // the address with only this error corrected, if trivially fixable
+}
+
+var (
+	consecutiveDotsRegex = regexp.MustCompile(`\.{2,}`)
+	repeatedAtRegex      = regexp.MustCompile(`@{2,}`)
+)
+
+// DiagnoseFormatErrors reports common user mistakes in an email address, such as
+// stray spaces, doubled at-signs and misplaced dots. Each diagnosis carries a
+// corrected address where the fix is unambiguous.
+func DiagnoseFormatErrors(email string) []FormatError {
+	var diagnoses []FormatError
+
+	trimmed := strings.TrimSpace(email)
+	if trimmed != email {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrWhitespace,
+			Suggestion: "Remove the spaces before or after the email address.",
+			FixedEmail: trimmed,
+		})
+	}
+
+	if countUnquotedAts(trimmed) > 1 {
+		// Only a doubled @ ("john@@example.com") has an obvious fix
+		diagnosis := FormatError{
+			Code:       FormatErrDoubleAt,
+			Suggestion: "Use a single @ between the name and the domain.",
+		}
+		if fixed := repeatedAtRegex.ReplaceAllString(trimmed, "@"); countUnquotedAts(fixed) == 1 {
+			diagnosis.FixedEmail = fixed
+		}
+		diagnoses = append(diagnoses, diagnosis)
+	}
+
+	at := strings.LastIndex(trimmed, "@")
+	if at < 0 {
+		return diagnoses
+	}
+	localPart := strings.TrimRight(trimmed[:at], "@")
+	domain := trimmed[at+1:]
+
+	if strings.ContainsAny(localPart, " \t") {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrSpaceInLocalPart,
+			Suggestion: "Remove the spaces from the part before the @.",
+			FixedEmail: removeSpaces(localPart) + "@" + domain,
+		})
+	}
+
+	if strings.ContainsAny(domain, " \t") {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrSpaceInDomain,
+			Suggestion: "Remove the spaces from the domain.",
+			FixedEmail: localPart + "@" + removeSpaces(domain),
+		})
+	}
+
+	if strings.Contains(localPart, "..") || strings.Contains(domain, "..") {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrConsecutiveDots,
+			Suggestion: "Replace the repeated dots with a single dot.",
+			FixedEmail: consecutiveDotsRegex.ReplaceAllString(localPart, ".") + "@" +
+				consecutiveDotsRegex.ReplaceAllString(domain, "."),
+		})
+	}
+
+	if strings.HasPrefix(localPart, ".") {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrLeadingDotLocal,
+			Suggestion: "Remove the dot at the start of the address.",
+			FixedEmail: strings.TrimLeft(localPart, ".") + "@" + domain,
+		})
+	}
+
+	if strings.HasPrefix(domain, ".") {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrLeadingDotDomain,
+			Suggestion: "Remove the dot right after the @.",
+			FixedEmail: localPart + "@" + strings.TrimLeft(domain, "."),
+		})
+	}
+
+	if strings.HasSuffix(domain, ".") {
+		diagnoses = append(diagnoses, FormatError{
+			Code:       FormatErrTrailingDotDomain,
+			Suggestion: "Remove the dot at the end of the domain.",
+			FixedEmail: localPart + "@" + strings.TrimRight(domain, "."),
+		})
+	}
+
+	return diagnoses
+}
+
+// removeSpaces strips all spaces and tabs from s.
+func removeSpaces(s string) string {
+	return strings.NewReplacer(" ", "", "\t", "").Replace(s)
+}
+
+// countUnquotedAts counts the @ signs in email outside a quoted local part,
+// where an @ is allowed.
+func countUnquotedAts(email string) int {
+	count := 0
+	quoted := false
+	for i := 0; i < len(email); i++ {
+		switch c := email[i]; {
+		case quoted && c == '\\':
+			i++ // skip the escaped character
+		case c == '"':
+			quoted = !quoted
+		case c == '@' && !quoted:
+			count++
+		}
+	}
+	return count
+}
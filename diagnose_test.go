@@ -0,0 +1,34 @@
+package shared
+
+import "testing"
+
+func TestDiagnoseFormatErrorsDoubleAt(t *testing.T) {
+	tests := []struct {
+		email     string
+		wantError bool
+		fixed     string
+	}{
+		{"john@@example.com", true, "john@example.com"},
+		{"john@doe@example.com", true, ""},
+		{`"john@doe"@example.com`, false, ""},
+		{`"john\"@"@example.com`, false, ""},
+		{"john@example.com", false, ""},
+	}
+
+	for _, tt := range tests {
+		var found *FormatError
+		for _, diagnosis := range DiagnoseFormatErrors(tt.email) {
+			if diagnosis.Code == FormatErrDoubleAt {
+				found = &diagnosis
+			}
+		}
+
+		if (found != nil) != tt.wantError {
+			t.Errorf("DiagnoseFormatErrors(%q) reported double @ = %v, want %v", tt.email, found != nil, tt.wantError)
+			continue
+		}
+		if found != nil && found.FixedEmail != tt.fixed {
+			t.Errorf("DiagnoseFormatErrors(%q) FixedEmail = %q, want %q", tt.email, found.FixedEmail, tt.fixed)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// File: shared/diff.go
+package shared
+
+// ResultDiff describes how a result changed between two validator configurations.
+type ResultDiff struct {
+	StatusChanged    bool     `json:"status_changed"`
+	OldStatus        Status   `json:"old_status"`
+	NewStatus        Status   `json:"new_status"`
+	SubStatusChanged bool     `json:"sub_status_changed"`
+	ScoreChange      int      `json:"score_change"` // new score minus old score
+	AddedTags        []string `json:"added_tags,omitempty"`
+	RemovedTags      []string `json:"removed_tags,omitempty"`
+}
+
+// Changed reports whether anything differs between the two results.
+func (d ResultDiff) Changed() bool {
+	return d.StatusChanged || d.SubStatusChanged || d.ScoreChange != 0 ||
+		len(d.AddedTags) > 0 || len(d.RemovedTags) > 0
+}
+
+// BatchDiff summarizes the differences between two runs over the same batch.
+type BatchDiff struct {
+	TotalChanged   int `json:"total_changed"`
+	ValidToInvalid int `json:"valid_to_invalid"`
+	InvalidToValid int `json:"invalid_to_valid"`
+	ScoreIncrease  int `json:"score_increase"` // results whose score went up
+	ScoreDecrease  int `json:"score_decrease"` // results whose score went down
+}
+
+// CompareResults compares the result a of the current configuration with the
+// result b of a candidate configuration. A nil result is treated as empty.
+func CompareResults(a, b *Result) ResultDiff {
+	if a == nil {
+		a = &Result{}
+	}
+	if b == nil {
+		b = &Result{}
+	}
+
+	return ResultDiff{
+		StatusChanged:    a.Status != b.Status,
+		OldStatus:        Status(a.Status),
+		NewStatus:        Status(b.Status),
+		SubStatusChanged: a.SubStatus != b.SubStatus,
+		ScoreChange:      b.Score - a.Score,
+		AddedTags:        missingTags(b.Tags, a.Tags),
+		RemovedTags:      missingTags(a.Tags, b.Tags),
+	}
+}
+
+// DiffBatch compares two runs over the same emails. Results are paired by
+// index, so both slices must be in input order; extra results in the longer
+// slice are compared against nil.
+func DiffBatch(aResults, bResults []*Result) BatchDiff {
+	var diff BatchDiff
+
+	for i := range max(len(aResults), len(bResults)) {
+		var a, b *Result
+		if i < len(aResults) {
+			a = aResults[i]
+		}
+		if i < len(bResults) {
+			b = bResults[i]
+		}
+
+		d := CompareResults(a, b)
+		if !d.Changed() {
+			continue
+		}
+		diff.TotalChanged++
+
+		switch {
+		case d.OldStatus == StatusValid && d.NewStatus == StatusInvalid:
+			diff.ValidToInvalid++
+		case d.OldStatus == StatusInvalid && d.NewStatus == StatusValid:
+			diff.InvalidToValid++
+		}
+
+		switch {
+		case d.ScoreChange > 0:
+			diff.ScoreIncrease++
+		case d.ScoreChange < 0:
+			diff.ScoreDecrease++
+		}
+	}
+
+	return diff
+}
+
+// missingTags returns the tags in tags that are not in other.
+func missingTags(tags, other []string) []string {
+	present := make(map[string]bool, len(other))
+	for _, tag := range other {
+		present[tag] = true
+	}
+
+	var missing []string
+	for _, tag := range tags {
+		if !present[tag] {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,108 @@
+// File: shared/disposable_providers.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URLDisposableProvider checks domains against a list fetched from a URL, e.g.
+// an internal API. The list uses the same format as the embedded list: one
+// domain per line, with # comments. It is re-fetched once refresh has passed;
+// if a refresh fails, the previous list keeps being used.
+type URLDisposableProvider struct {
+	url     string
+	client  HTTPDoer
+	refresh time.Duration
+
+	mu       sync.Mutex
+	domains  map[string]bool
+	loadedAt time.Time
+}
+
+// NewURLDisposableProvider creates a provider that fetches the list at url
+// through client. A nil client uses a 10-second HTTP client.
+func NewURLDisposableProvider(url string, client HTTPDoer, refresh time.Duration) *URLDisposableProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &URLDisposableProvider{url: url, client: client, refresh: refresh}
+}
+
+// IsDisposable checks if the domain is in the fetched list. An error is
+// returned only if no list has been fetched successfully yet.
+func (p *URLDisposableProvider) IsDisposable(ctx context.Context, domain string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.domains == nil || time.Since(p.loadedAt) >= p.refresh {
+		domains, err := p.fetch(ctx)
+		if err != nil && p.domains == nil {
+			return false, err
+		}
+		if err == nil {
+			p.domains = domains
+			p.loadedAt = time.Now()
+		}
+	}
+
+	return IsDisposable(strings.TrimSpace(domain), p.domains), nil
+}
+
+// fetch downloads and parses the domain list.
+func (p *URLDisposableProvider) fetch(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create disposable list request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch disposable list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch disposable list: status %d", resp.StatusCode)
+	}
+
+	domains, err := parseDomainList(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disposable list: %w", err)
+	}
+	return domains, nil
+}
+
+// CompositeDisposableProvider checks several providers in sequence; the first
+// provider reporting a domain as disposable wins.
+type CompositeDisposableProvider struct {
+	providers []DisposableProvider
+}
+
+// NewCompositeDisposableProvider creates a provider that consults providers in order.
+func NewCompositeDisposableProvider(providers ...DisposableProvider) *CompositeDisposableProvider {
+	return &CompositeDisposableProvider{providers: providers}
+}
+
+// IsDisposable reports whether any provider considers the domain disposable.
+// Failing providers are skipped; their errors are returned only if no
+// provider reported a match.
+func (p *CompositeDisposableProvider) IsDisposable(ctx context.Context, domain string) (bool, error) {
+	var errs []error
+	for _, provider := range p.providers {
+		isDisposable, err := provider.IsDisposable(ctx, domain)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if isDisposable {
+			return true, nil
+		}
+	}
+	return false, errors.Join(errs...)
+}
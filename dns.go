@@ -1,32 +1,55 @@
 package shared
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net"
 	"sort"
 	"strings"
+	"sync"
 )
 
+// NewDNSResolverFromAddr creates a resolver that sends every query to the DNS
+// server at addr ("host:port"), e.g. a local Unbound instance, instead of the
+// servers configured in the OS.
+func NewDNSResolverFromAddr(addr string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+}
+
 // CheckMX verifies that a domain has valid MX records.
 func CheckMX(domain string) ([]*net.MX, error) {
+	return checkMX(context.Background(), net.DefaultResolver, domain)
+}
+
+// CheckMX verifies that a domain has valid MX records using the validator's resolver.
+func (v *Validator) CheckMX(domain string) ([]*net.MX, error) {
+	return checkMX(context.Background(), v.resolver, domain)
+}
+
+// checkMX verifies that a domain has valid MX records using the given resolver.
+func checkMX(ctx context.Context, resolver DNSResolver, domain string) ([]*net.MX, error) {
 	domain = strings.ToLower(strings.TrimSpace(domain))
 
-	mxRecords, err := net.LookupMX(domain)
+	mxRecords, err := resolver.LookupMX(ctx, domain)
 	if err != nil {
 		// Differentiate between a non-existent domain and other lookup errors.
-		if dnsErr, ok := err.(*net.DNSError); ok {
-			if dnsErr.IsNotFound {
-				return nil, errors.New("domain does not exist")
-			}
-			if dnsErr.IsTimeout {
-				return nil, errors.New("DNS lookup timeout")
-			}
-		}
-		return nil, errors.New("failed to lookup MX records")
+		return nil, withRequestID(ctx, classifyDNSError(err, "MX"))
 	}
 
 	if len(mxRecords) == 0 {
-		return nil, errors.New("no MX records found for the domain")
+		return nil, withRequestID(ctx, ErrNoMXRecords)
+	}
+
+	// A single "." MX record means the domain accepts no email (RFC 7505)
+	if len(mxRecords) == 1 && (mxRecords[0].Host == "." || mxRecords[0].Host == "") {
+		return nil, withRequestID(ctx, ErrNullMX)
 	}
 
 	// Sort MX records by priority (lower priority number = higher priority)
@@ -37,25 +60,83 @@ func CheckMX(domain string) ([]*net.MX, error) {
 	return mxRecords, nil
 }
 
+// ResolvedMX is an MX record together with the addresses its host resolves to.
+type ResolvedMX struct {
+	*net.MX
+	IPs             []net.IP
+	ResolutionError error // set if the host has no usable address
+}
+
+// CheckMXWithResolution looks up the MX records of domain like CheckMX and
+// resolves each MX host. Records pointing to hosts without an address, common
+// after a botched DNS migration, are returned with ResolutionError set; use
+// UsableMX to keep only the working ones. The error is only set if the MX
+// lookup itself fails, so "MX exists but broken" is an empty UsableMX result
+// rather than ErrNoMXRecords.
+func CheckMXWithResolution(ctx context.Context, domain string) ([]*ResolvedMX, error) {
+	return checkMXWithResolution(ctx, net.DefaultResolver, domain)
+}
+
+// CheckMXWithResolution resolves the MX hosts of domain using the validator's resolver.
+func (v *Validator) CheckMXWithResolution(ctx context.Context, domain string) ([]*ResolvedMX, error) {
+	return checkMXWithResolution(ctx, v.resolver, domain)
+}
+
+// checkMXWithResolution looks up and resolves MX hosts using the given resolver.
+func checkMXWithResolution(ctx context.Context, resolver DNSResolver, domain string) ([]*ResolvedMX, error) {
+	mxRecords, err := checkMX(ctx, resolver, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// checkMX has already sorted the records by preference
+	resolved := make([]*ResolvedMX, len(mxRecords))
+	var wg sync.WaitGroup
+	for i, mx := range mxRecords {
+		resolved[i] = &ResolvedMX{MX: mx}
+		wg.Add(1)
+		go func(r *ResolvedMX) {
+			defer wg.Done()
+			r.IPs, r.ResolutionError = checkA(ctx, resolver, strings.TrimSuffix(r.Host, "."))
+		}(resolved[i])
+	}
+	wg.Wait()
+
+	return resolved, nil
+}
+
+// UsableMX returns the records of resolved whose host resolved to at least one address.
+func UsableMX(resolved []*ResolvedMX) []*ResolvedMX {
+	var usable []*ResolvedMX
+	for _, r := range resolved {
+		if r.ResolutionError == nil {
+			usable = append(usable, r)
+		}
+	}
+	return usable
+}
+
 // CheckA verifies that a domain has valid A records (fallback if no MX).
 func CheckA(domain string) ([]net.IP, error) {
+	return checkA(context.Background(), net.DefaultResolver, domain)
+}
+
+// CheckA verifies that a domain has valid A records using the validator's resolver.
+func (v *Validator) CheckA(domain string) ([]net.IP, error) {
+	return checkA(context.Background(), v.resolver, domain)
+}
+
+// checkA verifies that a domain has valid A records using the given resolver.
+func checkA(ctx context.Context, resolver DNSResolver, domain string) ([]net.IP, error) {
 	domain = strings.ToLower(strings.TrimSpace(domain))
 
-	ips, err := net.LookupIP(domain)
+	ips, err := resolver.LookupIP(ctx, "ip", domain)
 	if err != nil {
-		if dnsErr, ok := err.(*net.DNSError); ok {
-			if dnsErr.IsNotFound {
-				return nil, errors.New("domain does not exist")
-			}
-			if dnsErr.IsTimeout {
-				return nil, errors.New("DNS lookup timeout")
-			}
-		}
-		return nil, errors.New("failed to lookup A records")
+		return nil, classifyDNSError(err, "A")
 	}
 
 	if len(ips) == 0 {
-		return nil, errors.New("no A records found for the domain")
+		return nil, ErrNoARecords
 	}
 
 	return ips, nil
@@ -69,6 +150,11 @@ func ValidateDomain(domain string) error {
 		return nil // MX records found, domain is valid for email
 	}
 
+	// A null MX is an explicit refusal, so don't fall back to A records
+	if errors.Is(err, ErrNullMX) {
+		return err
+	}
+
 	// If no MX records, check for A records as fallback
 	_, aErr := CheckA(domain)
 	if aErr != nil {
@@ -78,3 +164,16 @@ func ValidateDomain(domain string) error {
 	// Domain has A records but no MX, still potentially valid for email
 	return nil
 }
+
+// classifyDNSError maps a resolver error to one of the package's sentinel errors.
+func classifyDNSError(err error, recordType string) error {
+	if dnsErr, ok := err.(*net.DNSError); ok {
+		if dnsErr.IsNotFound {
+			return ErrDomainNotFound
+		}
+		if dnsErr.IsTimeout {
+			return ErrDNSTimeout
+		}
+	}
+	return fmt.Errorf("%w: failed to lookup %s records: %v", ErrDNSLookupFailed, recordType, err)
+}
@@ -0,0 +1,225 @@
+// File: shared/domain_info.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultDomainInfoTTL is how long DomainInfo is cached when no TTL is configured.
+const defaultDomainInfoTTL = 5 * time.Minute
+
+// dkimSelectors are the selectors probed when looking for a DKIM key. DKIM
+// selectors aren't discoverable, so these cover the common providers only.
+var dkimSelectors = []string{"default", "google", "selector1", "selector2", "k1", "s1", "dkim"}
+
+// DKIMResult describes a DKIM public key found under one of the common selectors.
+type DKIMResult struct {
+	Found    bool   `json:"found"`
+	Selector string `json:"selector,omitempty"`
+	Record   string `json:"record,omitempty"`
+}
+
+// DomainInfo holds every DNS fact the validator needs about a domain.
+// SPF, DMARC and DKIM are nil if their lookups failed.
+type DomainInfo struct {
+	Domain      string       `json:"domain"`
+	MXRecords   []*net.MX    `json:"mx_records,omitempty"` // sorted by preference
+	ARecords    []net.IP     `json:"a_records,omitempty"`
+	AAAARecords []net.IP     `json:"aaaa_records,omitempty"`
+	SPF         *SPFResult   `json:"spf,omitempty"`
+	DMARC       *DMARCResult `json:"dmarc,omitempty"`
+	DKIM        *DKIMResult  `json:"dkim,omitempty"`
+	HasNullMX   bool         `json:"has_null_mx"`
+	ResolvedAt  time.Time    `json:"resolved_at"`
+
+	// transient is set when a lookup failed for a reason other than the
+	// record not existing, so the info shouldn't be cached.
+	transient bool
+}
+
+// Resolves reports whether the domain has any A or AAAA records.
+func (d *DomainInfo) Resolves() bool {
+	return len(d.ARecords) > 0 || len(d.AAAARecords) > 0
+}
+
+// LookupDomainInfo returns the DNS facts for domain, performing all lookups in
+// parallel. Results are cached for ValidatorConfig.DomainInfoTTL; infos with
+// transient lookup failures (e.g. timeouts) are not cached. An error is
+// returned only if ctx is done before the lookups complete.
+func (v *Validator) LookupDomainInfo(ctx context.Context, domain string) (*DomainInfo, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	if info, ok := v.domainInfo.get(domain, v.now()); ok {
+		return info, nil
+	}
+
+	info := lookupDomainInfo(ctx, v.resolver, domain)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info.ResolvedAt = v.now()
+
+	if !info.transient {
+		ttl := v.config.DomainInfoTTL
+		if ttl <= 0 {
+			ttl = defaultDomainInfoTTL
+		}
+		v.domainInfo.set(domain, info, info.ResolvedAt.Add(ttl))
+	}
+
+	return info, nil
+}
+
+// lookupDomainInfo runs every DNS lookup for domain concurrently.
+func lookupDomainInfo(ctx context.Context, resolver DNSResolver, domain string) *DomainInfo {
+	info := &DomainInfo{Domain: domain}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex // guards info.transient
+	)
+	lookup := func(fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil && isTransientDNSError(err) {
+				mu.Lock()
+				info.transient = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	lookup(func() error {
+		mxRecords, err := resolver.LookupMX(ctx, domain)
+		if err != nil {
+			return classifyDNSError(err, "MX")
+		}
+		if len(mxRecords) == 1 && (mxRecords[0].Host == "." || mxRecords[0].Host == "") {
+			info.HasNullMX = true
+			return nil
+		}
+		sort.Slice(mxRecords, func(i, j int) bool {
+			return mxRecords[i].Pref < mxRecords[j].Pref
+		})
+		info.MXRecords = mxRecords
+		return nil
+	})
+	lookup(func() error {
+		ips, err := resolver.LookupIP(ctx, "ip4", domain)
+		if err != nil {
+			return classifyDNSError(err, "A")
+		}
+		info.ARecords = ips
+		return nil
+	})
+	lookup(func() error {
+		ips, err := resolver.LookupIP(ctx, "ip6", domain)
+		if err != nil {
+			return classifyDNSError(err, "AAAA")
+		}
+		info.AAAARecords = ips
+		return nil
+	})
+	lookup(func() error {
+		spf, err := lookupSPF(ctx, resolver, domain)
+		info.SPF = spf
+		return err
+	})
+	lookup(func() error {
+		dmarc, err := lookupDMARC(ctx, resolver, domain)
+		info.DMARC = dmarc
+		return err
+	})
+	lookup(func() error {
+		dkim, err := lookupDKIM(ctx, resolver, domain)
+		info.DKIM = dkim
+		return err
+	})
+
+	wg.Wait()
+	return info
+}
+
+// lookupDKIM probes the common DKIM selectors concurrently and returns the
+// first one (in dkimSelectors order) that publishes a key.
+func lookupDKIM(ctx context.Context, resolver DNSResolver, domain string) (*DKIMResult, error) {
+	records := make([][]string, len(dkimSelectors))
+	errs := make([]error, len(dkimSelectors))
+
+	var wg sync.WaitGroup
+	for i, selector := range dkimSelectors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records[i], errs[i] = lookupTXT(ctx, resolver, selector+"._domainkey."+domain)
+		}()
+	}
+	wg.Wait()
+
+	for i, selector := range dkimSelectors {
+		for _, record := range records[i] {
+			if strings.Contains(strings.ToLower(record), "p=") {
+				return &DKIMResult{Found: true, Selector: selector, Record: record}, nil
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &DKIMResult{Found: false}, nil
+}
+
+// isTransientDNSError reports whether a lookup error may succeed on retry.
+func isTransientDNSError(err error) bool {
+	return !errors.Is(err, ErrDomainNotFound)
+}
+
+// domainInfoCache caches DomainInfo keyed by lower-cased domain.
+type domainInfoCache struct {
+	mu      sync.RWMutex
+	entries map[string]domainInfoEntry
+}
+
+// domainInfoEntry is a cached DomainInfo with its expiry time.
+type domainInfoEntry struct {
+	info      *DomainInfo
+	expiresAt time.Time
+}
+
+// newDomainInfoCache creates an empty domain info cache.
+func newDomainInfoCache() *domainInfoCache {
+	return &domainInfoCache{entries: make(map[string]domainInfoEntry)}
+}
+
+// get returns the cached info for domain if it hasn't expired at now.
+func (c *domainInfoCache) get(domain string, now time.Time) (*DomainInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, exists := c.entries[domain]
+	if !exists || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.info, true
+}
+
+// set caches info for domain until expiresAt, dropping expired entries.
+func (c *domainInfoCache) set(domain string, info *DomainInfo, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for d, entry := range c.entries {
+		if info.ResolvedAt.After(entry.expiresAt) {
+			delete(c.entries, d)
+		}
+	}
+	c.entries[domain] = domainInfoEntry{info: info, expiresAt: expiresAt}
+}
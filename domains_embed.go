@@ -0,0 +1,52 @@
+// File: shared/domains_embed.go
+package shared
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:generate go run gen_disposable_domains.go
+
+// embeddedDisposableDomains is the disposable domain list shipped with the
+// package, generated from the disposable-email-domains project's blocklist.
+//
+//go:embed disposable_domains.txt
+var embeddedDisposableDomains string
+
+// builtinDisposableDomains is the parsed embedded list merged with
+// DefaultDisposableDomains, shared by every validator using the built-in list.
+var builtinDisposableDomains = sync.OnceValue(func() map[string]bool {
+	domains := LoadDisposableDomainsFromEmbed()
+	for domain := range DefaultDisposableDomains {
+		domains[domain] = true
+	}
+	return domains
+})
+
+// LoadDisposableDomainsFromEmbed parses the disposable domain list embedded in
+// the package. Blank lines and lines starting with # are ignored. Each call
+// returns a new map that the caller may modify.
+func LoadDisposableDomainsFromEmbed() map[string]bool {
+	domains, _ := parseDomainList(strings.NewReader(embeddedDisposableDomains))
+	return domains
+}
+
+// parseDomainList reads one domain per line, ignoring blank lines and # comments.
+func parseDomainList(r io.Reader) (map[string]bool, error) {
+	domains := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains[strings.ToLower(line)] = true
+	}
+
+	return domains, scanner.Err()
+}
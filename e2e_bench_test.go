@@ -0,0 +1,208 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// corpusEntry is one address of testdata/corpus.json.
+type corpusEntry struct {
+	Email    string `json:"email"`
+	Category string `json:"category"`
+}
+
+// dnsFixtures are the recorded DNS answers in testdata/dns_fixtures.json.
+type dnsFixtures struct {
+	Domains map[string]struct {
+		MX  []net.MX `json:"mx"`
+		IPs []string `json:"ips"`
+	} `json:"domains"`
+	Hosts map[string][]string `json:"hosts"` // MX host to addresses
+}
+
+// smtpFixture is the recorded behaviour of one mail server in
+// testdata/smtp_fixtures.json.
+type smtpFixture struct {
+	Greeting  string   `json:"greeting"`
+	Rcpt      string   `json:"rcpt"`      // reply to recipients not in Mailboxes
+	Mailboxes []string `json:"mailboxes"` // recipients accepted with 250
+}
+
+// loadJSONFixture decodes testdata/name into v.
+func loadJSONFixture(tb testing.TB, name string, v interface{}) {
+	tb.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		tb.Fatalf("decoding %s: %v", name, err)
+	}
+}
+
+func loadCorpus(tb testing.TB) []corpusEntry {
+	var corpus struct {
+		Emails []corpusEntry `json:"emails"`
+	}
+	loadJSONFixture(tb, "corpus.json", &corpus)
+	return corpus.Emails
+}
+
+// fixtureResolver answers DNS lookups from dnsFixtures. Names without an
+// answer are not found.
+type fixtureResolver struct {
+	fixtures dnsFixtures
+}
+
+func fixtureName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fixtureResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	domain, ok := r.fixtures.Domains[fixtureName(name)]
+	if !ok || len(domain.MX) == 0 {
+		return nil, notFound(name)
+	}
+	mx := make([]*net.MX, len(domain.MX))
+	for i := range domain.MX {
+		mx[i] = &domain.MX[i]
+	}
+	return mx, nil
+}
+
+func (r *fixtureResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.fixtures.Hosts[fixtureName(host)+"."]; ok {
+		return addrs, nil
+	}
+	if domain, ok := r.fixtures.Domains[fixtureName(host)]; ok && len(domain.IPs) > 0 {
+		return domain.IPs, nil
+	}
+	return nil, notFound(host)
+}
+
+func (r *fixtureResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	addrs, err := r.LookupHost(ctx, host)
+	if err != nil || network == "ip6" {
+		return nil, notFound(host)
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = net.ParseIP(addr)
+	}
+	return ips, nil
+}
+
+func (r *fixtureResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, notFound(name)
+}
+
+// fixtureSMTPDialer returns a dialer whose connections replay the recorded
+// behaviour of the mail server dialed.
+func fixtureSMTPDialer(servers map[string]smtpFixture) *MockSMTPDialer {
+	return &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		server, ok := servers[fixtureName(host)+"."]
+		if !ok {
+			return nil, fmt.Errorf("dial %s: connection refused", addr)
+		}
+		conn := NewMockSMTPConn(server.Greeting, map[string]string{"RCPT": server.Rcpt})
+		conn.RcptReplies = make(map[string]string, len(server.Mailboxes))
+		for _, mailbox := range server.Mailboxes {
+			conn.RcptReplies[mailbox] = "250 2.1.5 OK"
+		}
+		return conn, nil
+	}}
+}
+
+// newFixtureValidator builds a validator answering DNS and SMTP from the
+// testdata fixtures.
+func newFixtureValidator(tb testing.TB, maxConcurrent int) *Validator {
+	tb.Helper()
+	var dns dnsFixtures
+	loadJSONFixture(tb, "dns_fixtures.json", &dns)
+	var smtp struct {
+		Servers map[string]smtpFixture `json:"servers"`
+	}
+	loadJSONFixture(tb, "smtp_fixtures.json", &smtp)
+
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = time.Second
+	cfg.MaxConcurrentValidations = maxConcurrent
+	return (&ValidatorFactory{
+		DNSResolver: &fixtureResolver{fixtures: dns},
+		SMTPDialer:  fixtureSMTPDialer(smtp.Servers),
+	}).Build(cfg)
+}
+
+// TestEndToEndCorpus checks that the fixtures produce the result each corpus
+// category describes, so the benchmark measures realistic work.
+func TestEndToEndCorpus(t *testing.T) {
+	corpus := loadCorpus(t)
+	if len(corpus) != 1000 {
+		t.Fatalf("corpus has %d addresses, want 1000", len(corpus))
+	}
+
+	emails := make([]string, len(corpus))
+	for i, entry := range corpus {
+		emails[i] = entry.Email
+	}
+	results := newFixtureValidator(t, 16).ValidateBatch(emails)
+
+	wantStatus := map[string]Status{
+		"valid":              StatusValid,
+		"role_based":         StatusValid,
+		"catch_all":          StatusValid, // only probed when coalescing SMTP checks
+		"unknown_mailbox":    StatusInvalid,
+		"no_mx":              StatusInvalid,
+		"nonexistent_domain": StatusInvalid,
+		"disposable":         StatusInvalid,
+		"invalid_syntax":     StatusInvalid,
+	}
+	for i, entry := range corpus {
+		want, ok := wantStatus[entry.Category]
+		if !ok {
+			t.Fatalf("%s: unknown category %q", entry.Email, entry.Category)
+		}
+		if got := Status(results[i].Status); got != want {
+			t.Errorf("%s (%s): status %s (%s), want %s", entry.Email, entry.Category, got, results[i].Reason, want)
+		}
+		if entry.Category == "role_based" {
+			if role, _ := results[i].Metadata["is_role_based"].(bool); !role {
+				t.Errorf("%s: is_role_based not set", entry.Email)
+			}
+		}
+	}
+}
+
+// BenchmarkEndToEndValidation measures ValidateBatch over the recorded corpus
+// at several concurrency limits, reporting throughput in emails/s.
+func BenchmarkEndToEndValidation(b *testing.B) {
+	corpus := loadCorpus(b)
+	emails := make([]string, len(corpus))
+	for i, entry := range corpus {
+		emails[i] = entry.Email
+	}
+
+	for _, concurrency := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			v := newFixtureValidator(b, concurrency)
+			for b.Loop() {
+				v.ValidateBatch(emails)
+			}
+			b.ReportMetric(float64(b.N*len(emails))/b.Elapsed().Seconds(), "emails/s")
+		})
+	}
+}
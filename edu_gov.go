@@ -0,0 +1,105 @@
+// File: shared/edu_gov.go
+package shared
+
+import (
+	"strings"
+)
+
+// Tags added to Result.Tags for educational, government and military domains.
+const (
+	TagEducational = "edu"
+	TagGovernment  = "gov"
+	TagMilitary    = "mil"
+)
+
+// eduSuffixes lists TLDs and second-level domains reserved for educational institutions.
+var eduSuffixes = []string{
+	"edu", "edu.au", "edu.ar", "edu.br", "edu.cn", "edu.co", "edu.eg", "edu.hk", "edu.in",
+	"edu.mx", "edu.my", "edu.pe", "edu.ph", "edu.pk", "edu.pl", "edu.sa", "edu.sg", "edu.tr",
+	"edu.tw", "edu.vn", "ac.uk", "ac.jp", "ac.nz", "ac.za", "ac.in", "ac.kr", "ac.il", "ac.at",
+	"ac.id", "ac.th", "ac.be", "ac.cn", "ac.ir", "ac.ke",
+}
+
+// govSuffixes lists TLDs and second-level domains reserved for government bodies.
+var govSuffixes = []string{
+	"gov", "gov.uk", "gov.au", "gov.br", "gov.cn", "gov.in", "gov.za", "gov.sg", "gov.il",
+	"gov.ie", "gov.pl", "gov.tr", "gov.ph", "gov.my", "gov.hk", "gc.ca", "gouv.fr", "gouv.qc.ca",
+	"govt.nz", "gob.mx", "gob.es", "gob.ar", "go.jp", "go.kr", "go.id", "go.th", "gv.at",
+	"admin.ch", "europa.eu",
+}
+
+// milSuffixes lists military domains, including NATO and allied equivalents.
+var milSuffixes = []string{
+	"mil", "nato.int", "mod.uk", "mil.uk", "mil.be", "mil.ca", "mil.pl", "mil.no", "mil.nz",
+	"mil.ee", "mil.lv", "mil.lt", "mil.hr", "mil.al", "mil.gr", "mil.tr", "mil.ro", "mil.cz",
+	"mil.sk", "mil.si", "mil.bg", "mil.fi", "mil.se", "army.mil", "defence.gov.au",
+}
+
+// eduLabels and govLabels classify additional suffixes from ValidatorConfig.EduGovTLDs by their leading label.
+var (
+	eduLabels = map[string]bool{"edu": true, "ac": true, "sch": true, "school": true, "uni": true}
+	govLabels = map[string]bool{"gov": true, "govt": true, "gob": true, "gouv": true, "go": true, "gv": true, "mil": true}
+)
+
+// IsEduDomain checks if the domain belongs to an educational institution.
+func IsEduDomain(domain string) bool {
+	return hasDomainSuffix(domain, eduSuffixes)
+}
+
+// IsGovDomain checks if the domain belongs to a government or military body.
+func IsGovDomain(domain string) bool {
+	return hasDomainSuffix(domain, govSuffixes) || IsMilDomain(domain)
+}
+
+// IsMilDomain checks if the domain belongs to a military organisation (.mil and NATO equivalents).
+func IsMilDomain(domain string) bool {
+	return hasDomainSuffix(domain, milSuffixes)
+}
+
+// classifyEduGovDomain returns the edu/gov/mil tags for the domain, including
+// additional country-specific suffixes classified by their leading label.
+func classifyEduGovDomain(domain string, extraSuffixes []string) []string {
+	isEdu := IsEduDomain(domain)
+	isGov := IsGovDomain(domain)
+
+	for _, suffix := range extraSuffixes {
+		suffix = strings.Trim(strings.ToLower(suffix), ".")
+		if !hasDomainSuffix(domain, []string{suffix}) {
+			continue
+		}
+
+		label := strings.SplitN(suffix, ".", 2)[0]
+		switch {
+		case eduLabels[label]:
+			isEdu = true
+		case govLabels[label]:
+			isGov = true
+		}
+	}
+
+	var tags []string
+	if isEdu {
+		tags = append(tags, TagEducational)
+	}
+	if isGov {
+		tags = append(tags, TagGovernment)
+	}
+	if IsMilDomain(domain) {
+		tags = append(tags, TagMilitary)
+	}
+
+	return tags
+}
+
+// hasDomainSuffix checks if the domain equals or is a subdomain of any of the suffixes.
+func hasDomainSuffix(domain string, suffixes []string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+
+	for _, suffix := range suffixes {
+		if domain == suffix || strings.HasSuffix(domain, "."+suffix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+// File: shared/email_auth.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// SPFResult describes a domain's SPF policy (RFC 7208).
+type SPFResult struct {
+	Found  bool   `json:"found"`
+	Record string `json:"record,omitempty"`
+	All    string `json:"all,omitempty"` // qualifier of the "all" mechanism: "+", "-", "~" or "?"
+}
+
+// DMARCResult describes a domain's DMARC policy (RFC 7489).
+type DMARCResult struct {
+	Found  bool   `json:"found"`
+	Record string `json:"record,omitempty"`
+	Policy string `json:"policy,omitempty"` // "none", "quarantine" or "reject"
+	Pct    int    `json:"pct,omitempty"`
+}
+
+// lookupSPF fetches and parses the SPF record for domain. A domain without
+// an SPF record returns a result with Found set to false.
+func lookupSPF(ctx context.Context, resolver DNSResolver, domain string) (*SPFResult, error) {
+	records, err := lookupTXT(ctx, resolver, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if !strings.HasPrefix(strings.ToLower(record), "v=spf1") {
+			continue
+		}
+
+		result := &SPFResult{Found: true, Record: record}
+		for _, term := range strings.Fields(record)[1:] {
+			mechanism := strings.ToLower(term)
+			qualifier := "+"
+			if strings.ContainsAny(mechanism[:1], "+-~?") {
+				qualifier, mechanism = mechanism[:1], mechanism[1:]
+			}
+			if mechanism == "all" {
+				result.All = qualifier
+			}
+		}
+		return result, nil
+	}
+
+	return &SPFResult{Found: false}, nil
+}
+
+// lookupDMARC fetches and parses the DMARC record published at _dmarc.<domain>.
+// A domain without a DMARC record returns a result with Found set to false.
+func lookupDMARC(ctx context.Context, resolver DNSResolver, domain string) (*DMARCResult, error) {
+	records, err := lookupTXT(ctx, resolver, "_dmarc."+domain)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if !strings.HasPrefix(strings.ToLower(record), "v=dmarc1") {
+			continue
+		}
+
+		result := &DMARCResult{Found: true, Record: record, Pct: 100}
+		for _, tag := range strings.Split(record, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(tag), "=")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "p":
+				result.Policy = strings.ToLower(strings.TrimSpace(value))
+			case "pct":
+				if pct, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+					result.Pct = pct
+				}
+			}
+		}
+		return result, nil
+	}
+
+	return &DMARCResult{Found: false}, nil
+}
+
+// lookupTXT returns the TXT records for name, treating a missing name as no records.
+func lookupTXT(ctx context.Context, resolver DNSResolver, name string) ([]string, error) {
+	records, err := resolver.LookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, nil
+		}
+		return nil, classifyDNSError(err, "TXT")
+	}
+	return records, nil
+}
@@ -0,0 +1,109 @@
+// File: shared/email_pattern.go
+package shared
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PatternType classifies how an address's local part appears to have been chosen.
+type PatternType string
+
+const (
+	PatternRandom     PatternType = "random"      // high-entropy string, e.g. "k7xq9vz2mw"
+	PatternSequential PatternType = "sequential"  // numbered account, e.g. "user0042"
+	PatternDateBased  PatternType = "date_based"  // embedded date, e.g. "tmpuser_20240115_a7f3"
+	PatternUUID       PatternType = "uuid"        // UUID, with or without hyphens
+	PatternPersonName PatternType = "person_name" // separated name parts, e.g. "jane.doe"
+	PatternGeneric    PatternType = "generic"     // none of the above
+)
+
+// EmailPattern is the result of DetectEmailPattern. Confidence ranges from 0 to 1.
+type EmailPattern struct {
+	Type       PatternType `json:"type"`
+	Confidence float64     `json:"confidence"`
+}
+
+// PatternConfig tunes DetectEmailPatternWithConfig.
+type PatternConfig struct {
+	// EntropyThreshold is the Shannon entropy (bits per character) above
+	// which a local part of at least minRandomLocalPartLength characters is
+	// classified as random.
+	EntropyThreshold float64
+}
+
+// DefaultPatternConfig returns the settings used by DetectEmailPattern.
+func DefaultPatternConfig() PatternConfig {
+	return PatternConfig{EntropyThreshold: 3.0}
+}
+
+// minRandomLocalPartLength keeps short local parts, whose entropy is
+// naturally close to the maximum, from being classified as random
+const minRandomLocalPartLength = 8
+
+// Regexes for the structured local part patterns
+var (
+	uuidLocalPartRegex   = regexp.MustCompile(`(?i)^[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$`)
+	dateLocalPartRegex   = regexp.MustCompile(`(?:^|[^0-9])(?:19|20)\d{2}[-_.]?(?:0[1-9]|1[0-2])[-_.]?(?:0[1-9]|[12]\d|3[01])(?:[^0-9]|$)`)
+	paddedSequenceRegex  = regexp.MustCompile(`^[a-z]+[._-]?0\d+$`)
+	numberedAccountRegex = regexp.MustCompile(`^(?:user|test|tmp|temp|account|member|customer|client|demo|guest)[._-]?\d+$`)
+	personNameRegex      = regexp.MustCompile(`^[a-z]{2,}(?:[._-][a-z]{2,}){1,2}$`)
+)
+
+// DetectEmailPattern classifies the local part of email with the default
+// PatternConfig, to tell addresses people picked from generated ones.
+func DetectEmailPattern(email string) EmailPattern {
+	return DetectEmailPatternWithConfig(email, DefaultPatternConfig())
+}
+
+// DetectEmailPatternWithConfig classifies the local part of email. Structured
+// patterns (UUID, date, sequence) are checked before entropy, so e.g.
+// "user0042" is sequential rather than random. Only local parts with
+// characters other than letters, or short on vowels, count as random, since
+// long names such as "christopher" have high entropy too.
+func DetectEmailPatternWithConfig(email string, cfg PatternConfig) EmailPattern {
+	local := strings.ToLower(email)
+	if at := strings.LastIndex(local, "@"); at >= 0 {
+		local = local[:at]
+	}
+	if local == "" {
+		return EmailPattern{Type: PatternGeneric}
+	}
+
+	switch {
+	case uuidLocalPartRegex.MatchString(local):
+		return EmailPattern{Type: PatternUUID, Confidence: 0.95}
+	case dateLocalPartRegex.MatchString(local):
+		return EmailPattern{Type: PatternDateBased, Confidence: 0.8}
+	case numberedAccountRegex.MatchString(local):
+		return EmailPattern{Type: PatternSequential, Confidence: 0.8}
+	case paddedSequenceRegex.MatchString(local):
+		return EmailPattern{Type: PatternSequential, Confidence: 0.6}
+	case personNameRegex.MatchString(local):
+		return EmailPattern{Type: PatternPersonName, Confidence: 0.7}
+	}
+
+	if len(local) >= minRandomLocalPartLength && !pronounceable(local) {
+		if entropy := ShannonEntropy(local); entropy > cfg.EntropyThreshold {
+			// Scale from 0.5 at the threshold up to 0.95 one bit above it
+			return EmailPattern{Type: PatternRandom, Confidence: min(0.95, 0.5+0.45*(entropy-cfg.EntropyThreshold))}
+		}
+	}
+
+	return EmailPattern{Type: PatternGeneric, Confidence: 0.5}
+}
+
+// pronounceable reports whether local consists of letters only, at least a
+// quarter of them vowels.
+func pronounceable(local string) bool {
+	vowels := 0
+	for _, r := range local {
+		switch {
+		case strings.ContainsRune("aeiouy", r):
+			vowels++
+		case r < 'a' || r > 'z':
+			return false
+		}
+	}
+	return vowels*4 >= len(local)
+}
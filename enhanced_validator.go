@@ -2,8 +2,12 @@
 package shared
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -13,101 +17,490 @@ import (
 type EnhancedValidator struct {
 	basicValidator *Validator
 	abuseIPDB      *AbuseIPDBClient
-	ipCache        map[string]*IPReputationResult
+	abuseIPDBKey   string
+	httpClient     HTTPDoer                           // used by the AbuseIPDB client when set
+	ipCache        map[string]map[string]ipCacheEntry // tenant -> IP -> result
 	cacheMutex     sync.RWMutex
 	cacheExpiry    time.Duration
+	lastEvictionAt time.Time // guarded by cacheMutex
+	evictionsTotal int       // guarded by cacheMutex
+	evictInterval  time.Duration
+	graylistQueue  *GraylistQueue
+	resultCache    ResultCache
+	cacheEventHook CacheEventHook
+	metrics        MetricsEmitter
+	recorder       *EventRecorder
+	auditLogger    AuditLogger
+	auditActorID   string
+	bulkSenders    []*BulkSenderProvider
+	office365      *Office365Checker
+	tranco         *TrancoListChecker
+	mailServerIPs  GetMailServerIPConfig
+	optionErr      error // first error reported by an option
+
+	// ctx is cancelled by Shutdown to stop background goroutines, which are tracked by wg.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	async asyncPool // ValidateEmailAsync workers
+
+	prefetchMu   sync.Mutex
+	prefetchDone chan struct{} // closed when the latest PrefetchIPReputation finishes
 }
 
-// NewEnhancedValidator creates a new enhanced validator with AbuseIPDB integration
-func NewEnhancedValidator(abuseIPDBKey string) *EnhancedValidator {
-	return &EnhancedValidator{
-		basicValidator: NewValidator(),
-		abuseIPDB:      NewAbuseIPDBClient(abuseIPDBKey),
-		ipCache:        make(map[string]*IPReputationResult),
+// EnhancedValidatorOption configures an EnhancedValidator
+type EnhancedValidatorOption func(*EnhancedValidator)
+
+// WithValidatorConfig sets the configuration used by the underlying basic
+// validator. NewEnhancedValidatorWithOptionsE fails on an invalid
+// configuration; NewEnhancedValidatorWithOptions logs it and keeps the default.
+func WithValidatorConfig(cfg ValidatorConfig) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		basic, err := NewValidator(cfg)
+		if err != nil {
+			v.fail(err)
+			return
+		}
+		v.basicValidator = basic
+	}
+}
+
+// WithGraylistQueue enables automatic retries of greylisted addresses through the given queue.
+// Final results are published to the queue once retries succeed or are exhausted.
+func WithGraylistQueue(q *GraylistQueue) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.graylistQueue = q
+	}
+}
+
+// WithResultCache caches validation results. If the cache supports it, cfg
+// sets how long results are kept for each status.
+func WithResultCache(cache ResultCache, cfg ResultCacheConfig) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		if configurable, ok := cache.(interface{ SetCacheConfig(ResultCacheConfig) }); ok {
+			configurable.SetCacheConfig(cfg)
+		}
+		v.resultCache = cache
+	}
+}
+
+// WithAuditLogger records every validation decision with logger.
+func WithAuditLogger(logger AuditLogger) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.auditLogger = logger
+	}
+}
+
+// WithAuditActorID sets the actor ID recorded in audit entries, e.g. the service or user name.
+func WithAuditActorID(id string) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.auditActorID = id
+	}
+}
+
+// WithAbuseIPDBKey sets the API key used for AbuseIPDB reputation checks
+func WithAbuseIPDBKey(key string) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.abuseIPDBKey = key
+	}
+}
+
+// WithCacheExpiry sets how long IP reputation results are cached. Non-positive values are ignored.
+func WithCacheExpiry(d time.Duration) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		if d > 0 {
+			v.cacheExpiry = d
+		}
+	}
+}
+
+// WithBasicValidator sets the basic validator used for the non-reputation checks
+func WithBasicValidator(basic *Validator) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.basicValidator = basic
+	}
+}
+
+// WithMailServerIPConfig selects which mail server addresses are checked for
+// reputation. By default only IPv4 addresses are checked.
+func WithMailServerIPConfig(cfg GetMailServerIPConfig) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.mailServerIPs = cfg
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for AbuseIPDB requests
+func WithHTTPClient(c *http.Client) EnhancedValidatorOption {
+	if c == nil {
+		return withHTTPDoer(nil) // not a non-nil HTTPDoer holding a nil client
+	}
+	return withHTTPDoer(c)
+}
+
+// withHTTPDoer sets what sends AbuseIPDB requests; nil uses the client's default
+func withHTTPDoer(d HTTPDoer) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.httpClient = d
+	}
+}
+
+// NewEnhancedValidatorWithOptions creates a new enhanced validator with AbuseIPDB integration.
+// IP reputation results are cached for 24 hours unless WithCacheExpiry is given.
+// An option that can't be applied, such as an invalid WithValidatorConfig, is
+// logged and skipped; use NewEnhancedValidatorWithOptionsE to handle the error.
+func NewEnhancedValidatorWithOptions(opts ...EnhancedValidatorOption) *EnhancedValidator {
+	v := newEnhancedValidator(opts)
+	if v.optionErr != nil {
+		log.Printf("Ignoring enhanced validator option: %v", v.optionErr)
+	}
+	v.start()
+	return v
+}
+
+// NewEnhancedValidatorWithOptionsE is NewEnhancedValidatorWithOptions, but
+// fails if an option can't be applied. Configuration errors wrap ErrInvalidConfig.
+func NewEnhancedValidatorWithOptionsE(opts ...EnhancedValidatorOption) (*EnhancedValidator, error) {
+	v := newEnhancedValidator(opts)
+	if v.optionErr != nil {
+		v.cancel()
+		return nil, v.optionErr
+	}
+	v.start()
+	return v, nil
+}
+
+// newEnhancedValidator applies opts to a validator with the default settings,
+// recording the first option error in optionErr. It starts nothing.
+func newEnhancedValidator(opts []EnhancedValidatorOption) *EnhancedValidator {
+	v := &EnhancedValidator{
+		basicValidator: newValidator(DefaultValidatorConfig()),
+		ipCache:        make(map[string]map[string]ipCacheEntry),
 		cacheExpiry:    time.Hour * 24, // Cache results for 24 hours
+		bulkSenders:    DefaultBulkSenderProviders,
+	}
+	v.ctx, v.cancel = context.WithCancel(context.Background())
+	v.async.cond = sync.NewCond(&v.async.mu)
+
+	for _, opt := range opts {
+		opt(v)
 	}
+	return v
+}
+
+// fail records err as the option error unless one was already recorded.
+func (v *EnhancedValidator) fail(err error) {
+	if v.optionErr == nil {
+		v.optionErr = err
+	}
+}
+
+// start creates the AbuseIPDB client and launches the background goroutines
+// once the options have been applied.
+func (v *EnhancedValidator) start() {
+	var clientOpts []AbuseIPDBOption
+	if v.httpClient != nil {
+		clientOpts = append(clientOpts, WithHTTPDoer(v.httpClient))
+	}
+	v.abuseIPDB = NewAbuseIPDBClient(v.abuseIPDBKey, clientOpts...)
+	v.attachRecorder()
+	v.evictInterval = v.cacheExpiry / 2
+
+	if v.graylistQueue != nil {
+		jobs, err := v.graylistQueue.ConsumeJobs()
+		if err != nil {
+			log.Printf("Failed to consume greylisting retry jobs: %v", err)
+		} else {
+			v.wg.Add(1)
+			go func() {
+				defer v.wg.Done()
+				v.runGraylistRetries(jobs)
+			}()
+		}
+	}
+}
+
+// NewEnhancedValidator creates a new enhanced validator with AbuseIPDB integration
+//
+// Deprecated: Use NewEnhancedValidatorWithOptions with WithAbuseIPDBKey.
+func NewEnhancedValidator(abuseIPDBKey string, opts ...EnhancedValidatorOption) *EnhancedValidator {
+	return NewEnhancedValidatorWithOptions(append([]EnhancedValidatorOption{WithAbuseIPDBKey(abuseIPDBKey)}, opts...)...)
+}
+
+// StartCacheEvictionWorker calls ClearExpiredCache every interval until ctx is
+// cancelled or the validator is shut down. A non-positive interval uses half
+// the cache expiry.
+func (v *EnhancedValidator) StartCacheEvictionWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = v.evictInterval
+	}
+
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-v.ctx.Done():
+				return
+			case <-ticker.C:
+				v.ClearExpiredCache()
+			}
+		}
+	}()
+}
+
+// Shutdown stops the validator's background goroutines and waits for them to
+// exit. Queued ValidateEmailAsync jobs are drained first; a graylist retry in
+// progress is cancelled. The graylist queue itself is left open and should be
+// closed by its owner.
+func (v *EnhancedValidator) Shutdown() {
+	v.stopAsyncWorkers()
+	v.cancel()
+	v.wg.Wait()
+	v.flushMetrics()
 }
 
 // ValidateEmailWithReputation performs email validation including IP reputation checks
 func (v *EnhancedValidator) ValidateEmailWithReputation(email string) *Result {
-	// Start with basic validation
-	result := v.basicValidator.ValidateEmail(email)
+	return v.ValidateEmailWithReputationContext(context.Background(), email)
+}
 
-	// If basic validation failed, no need to check IP reputation
-	if result.Status != "valid" {
-		return result
+// ValidateEmailWithReputationContext is like ValidateEmailWithReputation but uses the
+// request ID and tenant ID from ctx. Cached results are kept separate per tenant.
+func (v *EnhancedValidator) ValidateEmailWithReputationContext(ctx context.Context, email string) *Result {
+	cacheKey := tenantCacheKey(v.tenantID(ctx), email)
+	if v.resultCache != nil {
+		if cached, ok := v.resultCache.Get(cacheKey); ok {
+			v.audit(cached)
+			v.recordValidation(cached)
+			return cached
+		}
+	}
+
+	result := v.validateWithReputation(ctx, email)
+	v.audit(result)
+	v.recordValidation(result)
+	v.recordValidationEvent(result)
+
+	// Greylisted addresses are retried in the background when a queue is configured
+	if result.WasGreylisted && v.graylistQueue != nil {
+		v.scheduleGraylistRetry(ValidationJob{Email: email, Timestamp: time.Now()}, result)
 	}
 
-	// Extract domain from email
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
+	if v.resultCache != nil {
+		v.resultCache.Set(cacheKey, result)
+	}
+
+	return result
+}
+
+// validateWithReputation runs basic validation followed by IP reputation checks
+func (v *EnhancedValidator) validateWithReputation(ctx context.Context, email string) *Result {
+	ctx, _ = ensureRequestID(ctx)
+
+	// Start with basic validation
+	result := v.basicValidator.validateEmail(ctx, email, true)
+
+	// Take the domain from the address basic validation parsed, e.g. without
+	// a display name, splitting on the last @ since a quoted local part may contain one
+	address := resultAddress(result)
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
 		result.Status = "invalid"
 		result.Reason = "invalid email format"
 		return result
 	}
-	domain := parts[1]
+	domain := address[at+1:]
+
+	// SMTP can't confirm Microsoft 365 mailboxes, so ask the Graph API instead
+	if v.office365 != nil && (result.Status == "valid" || Status(result.Status) == StatusCatchAll) {
+		v.verifyOffice365(ctx, domain, result)
+	}
+
+	// If basic validation failed, no need to check IP reputation
+	if result.Status != "valid" {
+		return result
+	}
+
+	v.applyTrancoRank(domain, result)
 
 	// Get mail server IPs for the domain
-	ips, err := GetMailServerIPs(domain)
+	serverIPs, err := getMailServerIPs(ctx, v.basicValidator.resolver, domain, v.mailServerIPs)
 	if err != nil {
-		log.Printf("Failed to get mail server IPs for domain %s: %v", domain, err)
+		log.Printf("%sFailed to get mail server IPs for domain %s: %v", requestIDPrefix(ctx), domain, err)
 		// Don't fail the validation, just log the error
 		result.Metadata["ip_reputation_error"] = fmt.Sprintf("Failed to lookup mail servers: %v", err)
 		return result
 	}
 
+	ips := mailServerIPStrings(serverIPs)
 	if len(ips) == 0 {
-		result.Status = "suspicious"
+		result.Status = StatusSuspicious.String()
 		result.Reason = "no mail servers found for domain"
 		return result
 	}
 
+	// Shared IPs of bulk sender platforms carry other customers' reports, so
+	// they aren't held against the domain
+	var mxRecords []*net.MX
+	if info, err := v.basicValidator.LookupDomainInfo(ctx, domain); err == nil {
+		mxRecords = info.MXRecords
+	}
+	bulkSender := findBulkSender(v.bulkSenders, mxRecords, ips)
+	if bulkSender != nil {
+		result.Metadata["bulk_sender_provider"] = bulkSender.Name
+	}
+
 	// Check reputation for each IP
 	var reputationResults []IPReputationResult
-	highRiskFound := false
+	var scored []*IPReputationResult
 
 	for _, ip := range ips {
-		ipResult := v.checkIPReputationWithCache(ip)
+		ipResult := v.checkIPReputationWithCache(ctx, ip)
 		reputationResults = append(reputationResults, *ipResult)
 
-		// Consider high risk if abuse confidence > 75% or many reports
-		if ipResult.AbuseConfidenceScore > 75 || ipResult.TotalReports > 50 {
-			highRiskFound = true
+		if bulkSender == nil {
+			scored = append(scored, ipResult)
 		}
 	}
 
+	// Large ESPs have mixed IP pools, so the configured mode decides how many
+	// high risk IPs (abuse confidence > 75%, many or recent reports) flag the domain
+	cfg := v.basicValidator.config
+	reputation := aggregateDomainReputation(scored, cfg.RiskConfig)
+	highRiskFound := reputation.IsHighRisk(cfg.ReputationAggregation)
+
 	// Update result based on IP reputation
 	if highRiskFound {
-		result.Status = "suspicious"
+		result.Status = StatusSuspicious.String()
 		result.Reason = "mail server IP has poor reputation"
 	}
 
+	// Neighbouring IPs in the same /24 often share the mail servers' reputation
+	if cfg.EnableSubnetAggregation && len(scored) > 0 {
+		subnets := v.subnetReputation(ctx, scored)
+		if score, bad := singleSubnetHighRisk(subnets, scored); bad && !highRiskFound {
+			result.Status = StatusSuspicious.String()
+			result.Reason = fmt.Sprintf("mail server subnet %s has poor reputation", score.Subnet)
+		}
+		result.Metadata["subnet_reputation"] = subnets.Scores()
+	}
+
 	// Add reputation data to metadata
 	if result.Metadata == nil {
 		result.Metadata = make(map[string]interface{})
 	}
 	result.Metadata["ip_reputation"] = reputationResults
+	result.Metadata["domain_reputation"] = reputation
 	result.Metadata["mail_server_ips"] = ips
 
 	return result
 }
 
-// checkIPReputationWithCache checks IP reputation with caching
-func (v *EnhancedValidator) checkIPReputationWithCache(ip string) *IPReputationResult {
-	v.cacheMutex.RLock()
-	if cached, exists := v.ipCache[ip]; exists {
-		// Check if cache entry is still valid
-		if time.Since(cached.CheckedAt) < v.cacheExpiry {
-			v.cacheMutex.RUnlock()
-			return cached
+// audit records the validation decision if an audit logger is configured
+func (v *EnhancedValidator) audit(result *Result) {
+	if v.auditLogger == nil {
+		return
+	}
+
+	entry := AuditEntry{
+		Timestamp: time.Now(),
+		Email:     result.Email,
+		Status:    Status(result.Status),
+		SubStatus: result.SubStatus,
+		ActorID:   v.auditActorID,
+		Duration:  result.Duration,
+	}
+	if id, ok := result.Metadata["request_id"].(string); ok {
+		entry.RequestID = id
+	}
+	if ips, ok := result.Metadata["mail_server_ips"].([]string); ok {
+		entry.IPsChecked = ips
+	}
+
+	if err := v.auditLogger.Log(entry); err != nil {
+		log.Printf("Failed to write audit entry for %s: %v", v.logEmail(result.Email), err)
+	}
+}
+
+// logEmail redacts email for log messages according to the configured LogAnonymizeMode
+func (v *EnhancedValidator) logEmail(email string) string {
+	return AnonymizeEmail(email, v.basicValidator.config.LogAnonymizeMode)
+}
+
+// scheduleGraylistRetry enqueues the job for another attempt after GraylistRetryAfter
+func (v *EnhancedValidator) scheduleGraylistRetry(job ValidationJob, result *Result) {
+	job.Attempts++
+	job.NotBefore = time.Now().Add(v.basicValidator.config.GraylistRetryAfter)
+
+	if err := v.graylistQueue.PublishJob(job); err != nil {
+		log.Printf("Failed to schedule greylisting retry for %s: %v", v.logEmail(job.Email), err)
+		return
+	}
+
+	result.Metadata["graylist_retry_scheduled"] = true
+	result.Metadata["graylist_retry_at"] = job.NotBefore
+}
+
+// runGraylistRetries re-validates greylisted jobs as they become ready and
+// publishes a final result once the address is resolved or retries are exhausted.
+// It returns when jobs is closed or the validator is shut down.
+func (v *EnhancedValidator) runGraylistRetries(jobs <-chan ValidationJob) {
+	for {
+		var job ValidationJob
+		select {
+		case <-v.ctx.Done():
+			return
+		case j, ok := <-jobs:
+			if !ok {
+				return
+			}
+			job = j
 		}
+
+		result := v.validateWithReputation(v.ctx, job.Email)
+		if v.ctx.Err() != nil {
+			return
+		}
+		result.JobID = job.JobID
+		result.GraylistRetryCount = job.Attempts
+
+		if result.WasGreylisted && job.Attempts < v.basicValidator.config.GraylistMaxRetries {
+			v.scheduleGraylistRetry(job, result)
+			continue
+		}
+
+		result.WasGreylisted = true
+		if err := v.graylistQueue.PublishResult(*result); err != nil {
+			log.Printf("Failed to publish greylisting result for %s: %v", v.logEmail(job.Email), err)
+		}
+	}
+}
+
+// checkIPReputationWithCache checks IP reputation with caching
+func (v *EnhancedValidator) checkIPReputationWithCache(ctx context.Context, ip string) *IPReputationResult {
+	tenant := v.tenantID(ctx)
+	cached, err := v.getCachedIPReputation(tenant, ip)
+	switch {
+	case err == nil:
+		v.emitCacheEvent(CacheEventHit, ip, cached.CheckedAt)
+		return cached
+	case errors.Is(err, ErrCacheExpired):
+		v.emitCacheEvent(CacheEventExpiry, ip, cached.CheckedAt)
+	default:
+		v.emitCacheEvent(CacheEventMiss, ip, time.Time{})
 	}
-	v.cacheMutex.RUnlock()
 
 	// Cache miss or expired, fetch from API
-	result, err := v.abuseIPDB.CheckIP(ip)
+	result, err := v.abuseIPDB.CheckIPContext(ctx, ip)
 	if err != nil {
-		log.Printf("Error checking IP reputation for %s: %v", ip, err)
+		log.Printf("Error checking IP reputation for %s: %v", ip, err) // err carries the request ID
 		return &IPReputationResult{
 			IPAddress: ip,
 			Error:     fmt.Sprintf("API error: %v", err),
@@ -117,12 +510,70 @@ func (v *EnhancedValidator) checkIPReputationWithCache(ip string) *IPReputationR
 
 	// Update cache
 	v.cacheMutex.Lock()
-	v.ipCache[ip] = result
+	v.tenantCache(tenant)[ip] = v.newCacheEntry(result)
 	v.cacheMutex.Unlock()
 
 	return result
 }
 
+// CheckIPsBulk checks the reputation of multiple IP addresses, serving cached
+// results directly and looking up the rest with at most maxConcurrent
+// concurrent AbuseIPDB requests. Successful lookups are cached. See
+// AbuseIPDBClient.CheckIPsBulk.
+func (v *EnhancedValidator) CheckIPsBulk(ctx context.Context, ips []string, maxConcurrent int) map[string]*IPReputationResult {
+	tenant := v.tenantID(ctx)
+	results := make(map[string]*IPReputationResult, len(ips))
+
+	var misses []string
+	for _, ip := range ips {
+		cached, err := v.getCachedIPReputation(tenant, ip)
+		switch {
+		case err == nil:
+			v.emitCacheEvent(CacheEventHit, ip, cached.CheckedAt)
+			results[ip] = cached
+			continue
+		case errors.Is(err, ErrCacheExpired):
+			v.emitCacheEvent(CacheEventExpiry, ip, cached.CheckedAt)
+		default:
+			v.emitCacheEvent(CacheEventMiss, ip, time.Time{})
+		}
+		misses = append(misses, ip)
+	}
+
+	fetched := v.abuseIPDB.CheckIPsBulk(ctx, misses, maxConcurrent)
+
+	v.cacheMutex.Lock()
+	cache := v.tenantCache(tenant)
+	for ip, result := range fetched {
+		results[ip] = result
+		if result.Error == "" {
+			cache[ip] = v.newCacheEntry(result)
+		}
+	}
+	v.cacheMutex.Unlock()
+
+	return results
+}
+
+// getCachedIPReputation returns the cached result for ip, or ErrCacheMiss / ErrCacheExpired.
+// The stale entry is returned alongside ErrCacheExpired.
+func (v *EnhancedValidator) getCachedIPReputation(tenant, ip string) (*IPReputationResult, error) {
+	v.cacheMutex.RLock()
+	defer v.cacheMutex.RUnlock()
+
+	cached, exists := v.ipCache[tenant][ip]
+	if !exists {
+		return nil, ErrCacheMiss
+	}
+
+	// Check if cache entry is still valid
+	if cached.expired(time.Now()) {
+		return cached.result, ErrCacheExpired
+	}
+
+	return cached.result, nil
+}
+
 // ValidateEmail provides backward compatibility with basic validation
 func (v *EnhancedValidator) ValidateEmail(email string) *Result {
 	return v.ValidateEmailWithReputation(email)
@@ -131,23 +582,141 @@ func (v *EnhancedValidator) ValidateEmail(email string) *Result {
 // ClearExpiredCache removes expired entries from the IP cache
 func (v *EnhancedValidator) ClearExpiredCache() {
 	v.cacheMutex.Lock()
-	defer v.cacheMutex.Unlock()
 
 	now := time.Now()
-	for ip, result := range v.ipCache {
-		if now.Sub(result.CheckedAt) > v.cacheExpiry {
-			delete(v.ipCache, ip)
+	type evictedEntry struct {
+		ip         string
+		insertedAt time.Time
+	}
+	var evicted []evictedEntry
+	for tenant, cache := range v.ipCache {
+		for ip, entry := range cache {
+			if entry.expired(now) {
+				delete(cache, ip)
+				evicted = append(evicted, evictedEntry{ip: ip, insertedAt: entry.result.CheckedAt})
+			}
+		}
+		if len(cache) == 0 {
+			delete(v.ipCache, tenant)
 		}
 	}
+	v.lastEvictionAt = now
+	v.evictionsTotal += len(evicted)
+
+	v.cacheMutex.Unlock()
+
+	// Report evictions outside the lock so slow hooks don't block lookups
+	for _, entry := range evicted {
+		v.emitCacheEvent(CacheEventEviction, entry.ip, entry.insertedAt)
+	}
 }
 
-// GetCacheStats returns statistics about the IP reputation cache
+// GetCacheStats returns statistics about the IP cache across all tenants
 func (v *EnhancedValidator) GetCacheStats() map[string]interface{} {
 	v.cacheMutex.RLock()
 	defer v.cacheMutex.RUnlock()
 
+	entries := 0
+	for _, cache := range v.ipCache {
+		entries += len(cache)
+	}
+
+	stats := map[string]interface{}{
+		"cached_entries":   entries,
+		"tenants":          len(v.ipCache),
+		"cache_expiry":     v.cacheExpiry.String(),
+		"evictions_total":  v.evictionsTotal,
+		"last_eviction_at": nil,
+
+		"concurrent_validations_current": v.basicValidator.ConcurrentValidations(),
+	}
+	if !v.lastEvictionAt.IsZero() {
+		stats["last_eviction_at"] = v.lastEvictionAt
+	}
+	return stats
+}
+
+// GetCacheStatsContext returns statistics about the IP cache of the tenant in
+// ctx, or across all tenants if ctx has no tenant ID
+func (v *EnhancedValidator) GetCacheStatsContext(ctx context.Context) map[string]interface{} {
+	tenant := ExtractTenantID(ctx)
+	if tenant == "" {
+		return v.GetCacheStats()
+	}
+
+	v.cacheMutex.RLock()
+	defer v.cacheMutex.RUnlock()
+
 	return map[string]interface{}{
-		"cached_entries": len(v.ipCache),
+		"tenant_id":      tenant,
+		"cached_entries": len(v.ipCache[tenant]),
 		"cache_expiry":   v.cacheExpiry.String(),
 	}
 }
+
+// ClearTenantCache removes every cached IP reputation result of the tenant,
+// e.g. for a GDPR deletion request. Cached validation results expire by their TTL.
+func (v *EnhancedValidator) ClearTenantCache(tenantID string) {
+	v.cacheMutex.Lock()
+	defer v.cacheMutex.Unlock()
+
+	delete(v.ipCache, tenantID)
+}
+
+// tenantID returns the tenant for ctx, falling back to the configured TenantID.
+// An empty tenant ID selects the shared namespace.
+func (v *EnhancedValidator) tenantID(ctx context.Context) string {
+	if tenant := ExtractTenantID(ctx); tenant != "" {
+		return tenant
+	}
+	return v.basicValidator.config.TenantID
+}
+
+// tenantCache returns the tenant's IP cache, creating it if needed. The caller must hold cacheMutex.
+func (v *EnhancedValidator) tenantCache(tenant string) map[string]ipCacheEntry {
+	cache, exists := v.ipCache[tenant]
+	if !exists {
+		cache = make(map[string]ipCacheEntry)
+		v.ipCache[tenant] = cache
+	}
+	return cache
+}
+
+// ipCacheEntry is a cached IP reputation result with its time to live.
+type ipCacheEntry struct {
+	result *IPReputationResult
+	expiry time.Duration
+}
+
+// expired reports whether the entry's time to live has passed at now.
+func (e ipCacheEntry) expired(now time.Time) bool {
+	return now.After(e.result.CheckedAt.Add(e.expiry))
+}
+
+// newCacheEntry wraps result for the IP cache with a TTL based on its abuse score.
+func (v *EnhancedValidator) newCacheEntry(result *IPReputationResult) ipCacheEntry {
+	return ipCacheEntry{result: result, expiry: DynamicCacheExpiry(result, v.cacheExpiry)}
+}
+
+// DynamicCacheExpiry returns how long to cache result: high-confidence abuse
+// IPs (score above 75) rarely change and are kept for 3×baseExpiry, clean IPs
+// (score below 25) for baseExpiry/3 so they are rechecked sooner, and
+// borderline IPs for baseExpiry.
+func DynamicCacheExpiry(result *IPReputationResult, baseExpiry time.Duration) time.Duration {
+	switch score := result.AbuseConfidenceScore; {
+	case score > 75:
+		return baseExpiry * 3
+	case score < 25:
+		return baseExpiry / 3
+	default:
+		return baseExpiry
+	}
+}
+
+// tenantCacheKey namespaces a result cache key by tenant.
+func tenantCacheKey(tenant, email string) string {
+	if tenant == "" {
+		return email
+	}
+	return tenant + "/" + email
+}
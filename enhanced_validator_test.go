@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// acmeOnlyResolver is mxPerDomainResolver for acme.io alone; every other
+// name is not found.
+type acmeOnlyResolver struct{}
+
+func (acmeOnlyResolver) known(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	return name == "acme.io" || name == "mx.acme.io"
+}
+
+func (r acmeOnlyResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if !r.known(name) {
+		return nil, notFound(name)
+	}
+	return mxPerDomainResolver{}.LookupMX(ctx, name)
+}
+
+func (r acmeOnlyResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if !r.known(host) {
+		return nil, notFound(host)
+	}
+	return mxPerDomainResolver{}.LookupHost(ctx, host)
+}
+
+func (r acmeOnlyResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	if !r.known(host) {
+		return nil, notFound(host)
+	}
+	return mxPerDomainResolver{}.LookupIP(ctx, network, host)
+}
+
+func (acmeOnlyResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, notFound(name)
+}
+
+func TestValidateWithReputationUsesParsedDomain(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		mode  SyntaxMode
+	}{
+		{"plain address", "alice@acme.io", SyntaxModePragmatic},
+		{"display name", "Alice <alice@acme.io>", SyntaxModeRFC5322},
+		{"quoted local part with @", `"alice@home"@acme.io`, SyntaxModeRFC5322},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultValidatorConfig()
+			cfg.SMTPTimeout = time.Second
+			cfg.SyntaxMode = tt.mode
+			basic := (&ValidatorFactory{DNSResolver: acmeOnlyResolver{}, SMTPDialer: &MockSMTPDialer{}}).Build(cfg)
+			v := NewEnhancedValidatorWithOptions(WithBasicValidator(basic), WithHTTPClient(cleanAbuseIPDBClient))
+			defer v.Shutdown()
+
+			result := v.ValidateEmailWithReputation(tt.email)
+			if Status(result.Status) != StatusValid {
+				t.Fatalf("status = %s (%s), want valid", result.Status, result.Reason)
+			}
+			if errMsg, ok := result.Metadata["ip_reputation_error"]; ok {
+				t.Fatalf("ip_reputation_error = %v", errMsg)
+			}
+			if ips := fmt.Sprint(result.Metadata["mail_server_ips"]); !strings.Contains(ips, "192.0.2.1") {
+				t.Errorf("mail_server_ips = %s, want acme.io's mail server 192.0.2.1", ips)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// File: shared/errors.go
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors returned by the package. Use errors.Is to check for them.
+var (
+	ErrInvalidIPAddress     = errors.New("invalid IP address format")
+	ErrDomainNotFound       = errors.New("domain does not exist")
+	ErrDNSTimeout           = errors.New("DNS lookup timeout")
+	ErrDNSLookupFailed      = errors.New("DNS lookup failed")
+	ErrNoMXRecords          = errors.New("no MX records found for the domain")
+	ErrNoARecords           = errors.New("no A records found for the domain")
+	ErrNullMX               = errors.New("domain does not accept email (null MX)")
+	ErrSMTPConnectionFailed = errors.New("could not connect to SMTP server")
+	ErrSMTPCommandFailed    = errors.New("SMTP command failed")
+	ErrSMTPTLSFailed        = errors.New("SMTP TLS negotiation failed")
+	ErrCacheMiss            = errors.New("cache entry not found")
+	ErrCacheExpired         = errors.New("cache entry expired")
+	ErrRateLimited          = errors.New("rate limited")
+	ErrTLSPinMismatch       = errors.New("server certificate does not match any pinned key")
+)
+
+// ErrAbuseIPDBAPIError is returned when the AbuseIPDB API responds with a non-200 status.
+// Use errors.As to inspect the structured Errors the API reported.
+// A 429 response also matches ErrRateLimited via errors.Is.
+type ErrAbuseIPDBAPIError struct {
+	StatusCode int
+	Errors     []AbuseIPDBError // nil if the body wasn't a JSON error response
+	Body       string
+}
+
+// Error implements the error interface.
+func (e *ErrAbuseIPDBAPIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("API error: %d - %s", e.StatusCode, e.Body)
+	}
+
+	details := make([]string, len(e.Errors))
+	for i, apiErr := range e.Errors {
+		details[i] = apiErr.Detail
+	}
+	return fmt.Sprintf("API error: %d - %s", e.StatusCode, strings.Join(details, "; "))
+}
+
+// Is reports whether the API error matches target.
+func (e *ErrAbuseIPDBAPIError) Is(target error) bool {
+	return target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,146 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// abuseIPDBStatusError returns the error CheckIP reports for an HTTP status.
+func abuseIPDBStatusError(t *testing.T, status int, body string) error {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	result, err := newTestAbuseIPDBClient(t, srv).CheckIP("8.8.8.8")
+	if result == nil || result.Error == "" {
+		t.Errorf("CheckIP result = %+v, want a result carrying the error", result)
+	}
+	return err
+}
+
+// smtpGreetingError returns the error of an SMTP check against a server
+// sending greeting, or failing to connect if greeting is empty.
+func smtpGreetingError(greeting string) error {
+	dialer := &MockSMTPDialer{Dial: func(string) (net.Conn, error) {
+		if greeting == "" {
+			return nil, errors.New("connection refused")
+		}
+		return NewMockSMTPConn(greeting, nil), nil
+	}}
+	return checkSMTPServer(context.Background(), dialer, "john@example.com", "mx.example.com", time.Second, DefaultSMTPConfig()).Err
+}
+
+// ipCacheError returns the error of a cache lookup for an entry checked at checkedAt.
+func ipCacheError(checkedAt time.Time) error {
+	v := &EnhancedValidator{ipCache: make(map[string]map[string]ipCacheEntry), cacheExpiry: time.Hour}
+	if !checkedAt.IsZero() {
+		v.tenantCache("")["8.8.8.8"] = v.newCacheEntry(&IPReputationResult{IPAddress: "8.8.8.8", CheckedAt: checkedAt})
+	}
+	_, err := v.getCachedIPReputation("", "8.8.8.8")
+	return err
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    func(t *testing.T) error
+		target error
+	}{
+		{"invalid IP", func(t *testing.T) error {
+			result, err := NewAbuseIPDBClient("key").CheckIP("not-an-ip")
+			if result == nil || result.Error == "" {
+				t.Errorf("CheckIP result = %+v, want a result carrying the error", result)
+			}
+			return err
+		}, ErrInvalidIPAddress},
+		{"domain not found", func(t *testing.T) error {
+			_, err := checkMX(context.Background(), fakeResolver{err: &net.DNSError{Err: "no such host", IsNotFound: true}}, "example.com")
+			return err
+		}, ErrDomainNotFound},
+		{"DNS timeout", func(t *testing.T) error {
+			_, err := checkMX(context.Background(), fakeResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, "example.com")
+			return err
+		}, ErrDNSTimeout},
+		{"DNS failure", func(t *testing.T) error {
+			_, err := checkMX(context.Background(), fakeResolver{err: &net.DNSError{Err: "server misbehaving"}}, "example.com")
+			return err
+		}, ErrDNSLookupFailed},
+		{"no MX records", func(t *testing.T) error {
+			_, err := checkMX(context.Background(), fakeResolver{}, "example.com")
+			return err
+		}, ErrNoMXRecords},
+		{"null MX", func(t *testing.T) error {
+			_, err := checkMX(context.Background(), fakeResolver{mx: []*net.MX{{Host: "."}}}, "example.com")
+			return err
+		}, ErrNullMX},
+		{"SMTP connection failed", func(t *testing.T) error {
+			return smtpGreetingError("")
+		}, ErrSMTPConnectionFailed},
+		{"SMTP command failed", func(t *testing.T) error {
+			return smtpGreetingError("554 no service here")
+		}, ErrSMTPCommandFailed},
+		{"AbuseIPDB rate limit", func(t *testing.T) error {
+			return abuseIPDBStatusError(t, http.StatusTooManyRequests, `{"errors":[{"detail":"Daily rate limit exceeded","status":429}]}`)
+		}, ErrRateLimited},
+		{"cache miss", func(t *testing.T) error {
+			return ipCacheError(time.Time{})
+		}, ErrCacheMiss},
+		{"cache expired", func(t *testing.T) error {
+			return ipCacheError(time.Now().Add(-7 * 24 * time.Hour))
+		}, ErrCacheExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err(t)
+			if !errors.Is(err, tt.target) {
+				t.Errorf("error = %v, want errors.Is(err, %v)", err, tt.target)
+			}
+		})
+	}
+}
+
+func TestAbuseIPDBAPIErrorAs(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		detail      string
+		rateLimited bool
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"errors":[{"detail":"Daily rate limit exceeded","status":429}]}`, "Daily rate limit exceeded", true},
+		{"invalid key", http.StatusUnauthorized, `{"errors":[{"detail":"Authentication failed","status":"401"}]}`, "Authentication failed", false},
+		{"non-JSON body", http.StatusBadRequest, "bad request", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := abuseIPDBStatusError(t, tt.status, tt.body)
+
+			var apiErr *ErrAbuseIPDBAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want an *ErrAbuseIPDBAPIError", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Body != tt.body {
+				t.Errorf("Body = %q, want %q", apiErr.Body, tt.body)
+			}
+			if tt.detail != "" && (len(apiErr.Errors) != 1 || apiErr.Errors[0].Detail != tt.detail) {
+				t.Errorf("Errors = %+v, want one error with detail %q", apiErr.Errors, tt.detail)
+			}
+			if got := errors.Is(err, ErrRateLimited); got != tt.rateLimited {
+				t.Errorf("errors.Is(err, ErrRateLimited) = %v, want %v", got, tt.rateLimited)
+			}
+		})
+	}
+}
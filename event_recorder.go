@@ -0,0 +1,306 @@
+// File: shared/event_recorder.go
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RecordedEventType identifies the operation a RecordedEvent captured.
+type RecordedEventType string
+
+const (
+	EventValidation RecordedEventType = "validation" // Input is the validated email
+	EventDNSHost    RecordedEventType = "dns_host"
+	EventDNSMX      RecordedEventType = "dns_mx"
+	EventDNSIP      RecordedEventType = "dns_ip"
+	EventDNSTXT     RecordedEventType = "dns_txt"
+	EventSMTPDial   RecordedEventType = "smtp_dial"  // Input is the server address
+	EventSMTPWrite  RecordedEventType = "smtp_write" // Input is the data sent
+	EventSMTPRead   RecordedEventType = "smtp_read"  // Output is the data received
+	EventHTTP       RecordedEventType = "http"       // Input is "METHOD URL"
+)
+
+// RecordedEvent is one external interaction of a validation. DNS and HTTP
+// outputs are JSON encoded; SMTP data is kept verbatim.
+type RecordedEvent struct {
+	RequestID string            `json:"request_id"`
+	Type      RecordedEventType `json:"type"`
+	Input     string            `json:"input"`
+	Output    string            `json:"output,omitempty"`
+	Err       string            `json:"error,omitempty"`
+	ConnID    int64             `json:"conn_id,omitempty"` // SMTP connection the event belongs to
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// recordedHTTPResponse is the Output of an EventHTTP event.
+type recordedHTTPResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// EventRecorder captures DNS, SMTP and HTTP traffic of validations in a ring
+// buffer, so an unexpected result can be inspected and replayed with
+// ReplayValidation. Events are grouped by request ID.
+type EventRecorder struct {
+	mu     sync.Mutex
+	events []RecordedEvent
+	next   int
+	full   bool
+
+	connIDs atomic.Int64
+}
+
+// NewEventRecorder creates a recorder keeping the last size events.
+func NewEventRecorder(size int) *EventRecorder {
+	if size < 1 {
+		size = 1
+	}
+	return &EventRecorder{events: make([]RecordedEvent, size)}
+}
+
+// WithEventRecorder records the external interactions of every validation in
+// rec; see DumpReplayLog. The basic validator's resolver, SMTP dialer and
+// HTTP client and the AbuseIPDB client are wrapped in place.
+func WithEventRecorder(rec *EventRecorder) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.recorder = rec
+	}
+}
+
+// attachRecorder wraps the validator's dependencies with the event recorder, if one is set.
+func (v *EnhancedValidator) attachRecorder() {
+	if v.recorder == nil {
+		return
+	}
+	b := v.basicValidator
+	b.resolver = v.recorder.WrapResolver(b.resolver)
+	b.smtpDialer = v.recorder.WrapDialer(b.smtpDialer)
+	b.httpClient = v.recorder.WrapHTTPDoer(b.httpClient)
+	v.abuseIPDB.httpClient = v.recorder.WrapHTTPDoer(v.abuseIPDB.httpClient)
+}
+
+// DumpReplayLog returns the recorded events of the most recent validation of
+// email that is still in the recorder's buffer, oldest first. It returns nil
+// if no recorder is configured or the validation has been overwritten.
+func (v *EnhancedValidator) DumpReplayLog(email string) []RecordedEvent {
+	if v.recorder == nil {
+		return nil
+	}
+	return v.recorder.lastValidation(email)
+}
+
+// recordValidationEvent marks the end of a validation of email.
+func (v *EnhancedValidator) recordValidationEvent(result *Result) {
+	if v.recorder == nil {
+		return
+	}
+	id, _ := result.Metadata["request_id"].(string)
+	v.recorder.record(RecordedEvent{RequestID: id, Type: EventValidation, Input: result.Email})
+}
+
+// Events returns all buffered events, oldest first.
+func (r *EventRecorder) Events() []RecordedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]RecordedEvent(nil), r.events[:r.next]...)
+	}
+	return append(append([]RecordedEvent(nil), r.events[r.next:]...), r.events[:r.next]...)
+}
+
+// lastValidation returns the events sharing the request ID of the newest EventValidation for email.
+func (r *EventRecorder) lastValidation(email string) []RecordedEvent {
+	events := r.Events()
+
+	requestID := ""
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == EventValidation && events[i].Input == email {
+			requestID = events[i].RequestID
+			break
+		}
+	}
+	if requestID == "" {
+		return nil
+	}
+
+	var matched []RecordedEvent
+	for _, e := range events {
+		if e.RequestID == requestID {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// record appends e to the ring buffer, overwriting the oldest event when full.
+func (r *EventRecorder) record(e RecordedEvent) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	r.mu.Lock()
+	r.events[r.next] = e
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// recordResult records a DNS-style call whose output is JSON encoded.
+func (r *EventRecorder) recordResult(ctx context.Context, typ RecordedEventType, input string, output interface{}, err error) {
+	e := RecordedEvent{RequestID: ExtractRequestID(ctx), Type: typ, Input: input}
+	if err != nil {
+		e.Err = err.Error()
+		// Keep DNS error flags so classifyDNSError behaves the same on replay
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			output = dnsErr
+		} else {
+			output = nil
+		}
+	}
+	if output != nil {
+		if data, jsonErr := json.Marshal(output); jsonErr == nil {
+			e.Output = string(data)
+		}
+	}
+	r.record(e)
+}
+
+// WrapResolver returns a resolver that records every lookup made through resolver.
+func (r *EventRecorder) WrapResolver(resolver DNSResolver) DNSResolver {
+	return &recordingResolver{rec: r, next: resolver}
+}
+
+// recordingResolver records the lookups of the wrapped resolver.
+type recordingResolver struct {
+	rec  *EventRecorder
+	next DNSResolver
+}
+
+func (r *recordingResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, err := r.next.LookupHost(ctx, host)
+	r.rec.recordResult(ctx, EventDNSHost, host, addrs, err)
+	return addrs, err
+}
+
+func (r *recordingResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	mxs, err := r.next.LookupMX(ctx, name)
+	r.rec.recordResult(ctx, EventDNSMX, name, mxs, err)
+	return mxs, err
+}
+
+func (r *recordingResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	ips, err := r.next.LookupIP(ctx, network, host)
+	r.rec.recordResult(ctx, EventDNSIP, network+" "+host, ips, err)
+	return ips, err
+}
+
+func (r *recordingResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txts, err := r.next.LookupTXT(ctx, name)
+	r.rec.recordResult(ctx, EventDNSTXT, name, txts, err)
+	return txts, err
+}
+
+// WrapDialer returns a dialer that records every SMTP conversation held over dialer.
+func (r *EventRecorder) WrapDialer(dialer SMTPDialer) SMTPDialer {
+	return &recordingDialer{rec: r, next: dialer}
+}
+
+// recordingDialer records connections opened by the wrapped dialer.
+type recordingDialer struct {
+	rec  *EventRecorder
+	next SMTPDialer
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.next.DialContext(ctx, network, addr)
+
+	e := RecordedEvent{RequestID: ExtractRequestID(ctx), Type: EventSMTPDial, Input: addr}
+	if err != nil {
+		e.Err = err.Error()
+		d.rec.record(e)
+		return nil, err
+	}
+	e.ConnID = d.rec.connIDs.Add(1)
+	d.rec.record(e)
+
+	return &recordingConn{Conn: conn, rec: d.rec, requestID: e.RequestID, id: e.ConnID}, nil
+}
+
+// recordingConn records the data read from and written to an SMTP connection.
+type recordingConn struct {
+	net.Conn
+	rec       *EventRecorder
+	requestID string
+	id        int64
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.rec.record(RecordedEvent{RequestID: c.requestID, Type: EventSMTPRead, Output: string(p[:n]), ConnID: c.id})
+	}
+	return n, err
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		c.rec.record(RecordedEvent{RequestID: c.requestID, Type: EventSMTPWrite, Input: string(p[:n]), ConnID: c.id})
+	}
+	return n, err
+}
+
+// WrapHTTPDoer returns a client that records every request sent through doer
+// together with its response.
+func (r *EventRecorder) WrapHTTPDoer(doer HTTPDoer) HTTPDoer {
+	return &recordingDoer{rec: r, next: doer}
+}
+
+// recordingDoer records the requests of the wrapped client.
+type recordingDoer struct {
+	rec  *EventRecorder
+	next HTTPDoer
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.next.Do(req)
+
+	e := RecordedEvent{
+		RequestID: ExtractRequestID(req.Context()),
+		Type:      EventHTTP,
+		Input:     req.Method + " " + req.URL.String(),
+	}
+	if err != nil {
+		e.Err = err.Error()
+		d.rec.record(e)
+		return nil, err
+	}
+
+	// Buffer the body so it can be both recorded and read by the caller
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		e.Err = readErr.Error()
+	}
+
+	data, _ := json.Marshal(recordedHTTPResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: string(body)})
+	e.Output = string(data)
+	d.rec.record(e)
+
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+// File: shared/explain.go
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExplainLevel controls how much detail ExplainResult produces.
+type ExplainLevel int
+
+const (
+	// ExplainBrief produces one sentence suitable for end-users.
+	ExplainBrief ExplainLevel = iota
+	// ExplainDetailed produces a paragraph describing every check that ran.
+	ExplainDetailed
+	// ExplainTechnical produces a JSON dump of the result for support debugging.
+	ExplainTechnical
+)
+
+// Brief explanation templates, one per status.
+const (
+	explainBriefValid    = "This email address appears to be valid."
+	explainBriefInvalid  = "This email address doesn't appear to exist."
+	explainBriefRisky    = "This email address may exist, but we couldn't confirm it."
+	explainBriefUnknown  = "We couldn't determine whether this email address exists."
+	explainBriefCatchAll = "The mail server accepts every address, so we couldn't confirm this one exists."
+
+	explainBriefSuspicious = "This email address may exist, but its mail servers have a poor reputation."
+)
+
+// Detailed explanation templates.
+const (
+	explainDetailStatus        = "The address %s was classified as %s: %s."
+	explainDetailSubStatus     = "Sub-status: %s."
+	explainDetailDomain        = "The domain resolves in DNS."
+	explainDetailNoDomain      = "The domain could not be confirmed in DNS."
+	explainDetailMX            = "%d mail server(s) were found."
+	explainDetailSMTP          = "The mail server answered the mailbox check with code %d."
+	explainDetailGreylisted    = "The mail server temporarily deferred the check (greylisting) and it was retried %d time(s)."
+	explainDetailFreeProvider  = "The address is hosted by a free email provider."
+	explainDetailTags          = "Tags: %s."
+	explainDetailIPReputation  = "%d mail server IP(s) were checked for abuse reports."
+	explainTechnicalMarshalErr = "failed to render technical explanation: %v"
+)
+
+// ExplainResult renders a human-readable explanation of the result at the given level.
+func ExplainResult(result *Result, level ExplainLevel) string {
+	if result == nil {
+		return explainBriefUnknown
+	}
+
+	switch level {
+	case ExplainDetailed:
+		return explainDetailed(result)
+	case ExplainTechnical:
+		return explainTechnical(result)
+	default:
+		return explainBrief(result)
+	}
+}
+
+// explainBrief returns a one-sentence explanation for end-users.
+func explainBrief(result *Result) string {
+	switch Status(result.Status) {
+	case StatusValid:
+		return explainBriefValid
+	case StatusInvalid:
+		return explainBriefInvalid
+	case StatusRisky:
+		return explainBriefRisky
+	case StatusCatchAll:
+		return explainBriefCatchAll
+	case StatusSuspicious:
+		return explainBriefSuspicious
+	default:
+		return explainBriefUnknown
+	}
+}
+
+// explainDetailed returns a paragraph describing every check recorded in the result.
+func explainDetailed(result *Result) string {
+	sentences := []string{
+		fmt.Sprintf(explainDetailStatus, result.Email, result.Status, result.Reason),
+	}
+
+	if result.SubStatus != "" {
+		sentences = append(sentences, fmt.Sprintf(explainDetailSubStatus, result.SubStatus))
+	}
+
+	if resolves, ok := result.Metadata["domain_resolves"].(bool); ok && resolves {
+		sentences = append(sentences, explainDetailDomain)
+	} else {
+		sentences = append(sentences, explainDetailNoDomain)
+	}
+
+	if mxCount, ok := result.Metadata["mx_count"].(int); ok {
+		sentences = append(sentences, fmt.Sprintf(explainDetailMX, mxCount))
+	}
+
+	if code, ok := result.Metadata["smtp_code"].(int); ok && code > 0 {
+		sentences = append(sentences, fmt.Sprintf(explainDetailSMTP, code))
+	}
+
+	if result.WasGreylisted {
+		sentences = append(sentences, fmt.Sprintf(explainDetailGreylisted, result.GraylistRetryCount))
+	}
+
+	if isFree, ok := result.Metadata["is_free_provider"].(bool); ok && isFree {
+		sentences = append(sentences, explainDetailFreeProvider)
+	}
+
+	if reputation, ok := result.Metadata["ip_reputation"].([]IPReputationResult); ok {
+		sentences = append(sentences, fmt.Sprintf(explainDetailIPReputation, len(reputation)))
+	}
+
+	if len(result.Tags) > 0 {
+		sentences = append(sentences, fmt.Sprintf(explainDetailTags, strings.Join(result.Tags, ", ")))
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// explainTechnical returns an indented JSON dump of the full result.
+func explainTechnical(result *Result) string {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(explainTechnicalMarshalErr, err)
+	}
+	return string(data)
+}
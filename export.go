@@ -0,0 +1,84 @@
+// File: shared/export.go
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// flusher is implemented by writers that buffer output, such as http.ResponseWriter.
+type flusher interface {
+	Flush()
+}
+
+// ExportResultsToJSON writes results to w as a JSON array while they arrive on
+// the channel, flushing after each element so an HTTP response can start
+// streaming before the batch finishes. It returns when results is closed or
+// ctx is done; on cancellation the array is left unterminated. The producer
+// must close results: remaining results are drained on any early return so a
+// producer blocked on a send isn't leaked.
+func ExportResultsToJSON(ctx context.Context, results <-chan *Result, w io.Writer) (err error) {
+	defer func() {
+		if err != nil {
+			drainResults(results)
+		}
+	}()
+
+	encoder := json.NewEncoder(w)
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return fmt.Errorf("failed to write JSON array start: %w", err)
+	}
+
+	first := true
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case result, ok := <-results:
+			if !ok {
+				if _, err := io.WriteString(w, "]"); err != nil {
+					return fmt.Errorf("failed to write JSON array end: %w", err)
+				}
+				flush(w)
+				return nil
+			}
+			if result == nil {
+				continue
+			}
+
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return fmt.Errorf("failed to write JSON separator: %w", err)
+				}
+			}
+			first = false
+
+			if err := encoder.Encode(result); err != nil {
+				return fmt.Errorf("failed to encode result for %s: %w", result.Email, err)
+			}
+			flush(w)
+		}
+	}
+}
+
+// SetStreamingJSONHeaders sets the content type used for streamed result exports.
+func SetStreamingJSONHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+}
+
+// drainResults discards results until the channel is closed.
+func drainResults(results <-chan *Result) {
+	for range results {
+	}
+}
+
+// flush pushes buffered output to the client if w supports it.
+func flush(w io.Writer) {
+	if f, ok := w.(flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,95 @@
+// File: shared/factory.go
+package shared
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ValidatorFactory builds validators with injectable network dependencies.
+// Nil fields fall back to the production defaults.
+type ValidatorFactory struct {
+	DNSResolver        DNSResolver
+	SMTPDialer         SMTPDialer
+	HTTPClient         HTTPDoer
+	DisposableProvider DisposableProvider
+	Clock              func() time.Time
+}
+
+// DefaultValidatorFactory returns a factory using the system resolver, real
+// TCP dialing, a 10-second HTTP client and the embedded disposable domain list.
+func DefaultValidatorFactory() *ValidatorFactory {
+	return &ValidatorFactory{
+		DNSResolver:        net.DefaultResolver,
+		SMTPDialer:         NewNetSMTPDialer(),
+		HTTPClient:         &http.Client{Timeout: 10 * time.Second},
+		DisposableProvider: NewStaticDisposableProvider(builtinDisposableDomains()),
+		Clock:              time.Now,
+	}
+}
+
+// defaultFactory supplies the dependencies Build uses for nil factory fields.
+// It is built once so validators share one HTTP client and disposable list.
+var defaultFactory = sync.OnceValue(DefaultValidatorFactory)
+
+// Build creates a validator using the factory's dependencies and the given configuration.
+// A non-nil cfg.DisposableDomains takes precedence over the factory's DisposableProvider.
+// cfg is not checked; call cfg.Validate first if it comes from user input.
+func (f *ValidatorFactory) Build(cfg ValidatorConfig) *Validator {
+	defaults := defaultFactory()
+
+	// RFC 5322 compliant email regex (simplified version)
+	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+	v := &Validator{
+		emailRegex: emailRegex,
+		config:     cfg,
+		resolver:   f.DNSResolver,
+		smtpDialer: f.SMTPDialer,
+		httpClient: f.HTTPClient,
+		disposable: f.DisposableProvider,
+		now:        f.Clock,
+		domainInfo: newDomainInfoCache(),
+		platforms:  NewPlatformDetector(),
+		limiter:    newConcurrencyLimiter(cfg.MaxConcurrentValidations),
+
+		suspiciousLocal: suspiciousLocalPartPatterns(cfg),
+	}
+
+	if v.resolver == nil {
+		v.resolver = defaults.DNSResolver
+	}
+	if v.smtpDialer == nil {
+		v.smtpDialer = defaults.SMTPDialer
+	}
+	if v.httpClient == nil {
+		v.httpClient = defaults.HTTPClient
+	}
+	if cfg.DisposableDomains != nil {
+		v.disposable = NewStaticDisposableProvider(cfg.DisposableDomains)
+	}
+	if v.disposable == nil {
+		v.disposable = defaults.DisposableProvider
+	}
+	if v.now == nil {
+		v.now = defaults.Clock
+	}
+	v.smtpCache = NewSMTPCache(v.now)
+
+	return v
+}
+
+// BuildEnhanced creates an enhanced validator whose basic validator and
+// AbuseIPDB client use the factory's dependencies. Options in opts are
+// applied after these, so e.g. WithBasicValidator or WithHTTPClient replace them.
+func (f *ValidatorFactory) BuildEnhanced(cfg ValidatorConfig, abuseIPDBKey string, opts ...EnhancedValidatorOption) *EnhancedValidator {
+	basic := f.Build(cfg)
+	return NewEnhancedValidatorWithOptions(append([]EnhancedValidatorOption{
+		WithAbuseIPDBKey(abuseIPDBKey),
+		WithBasicValidator(basic),
+		withHTTPDoer(basic.httpClient),
+	}, opts...)...)
+}
@@ -0,0 +1,103 @@
+//go:build testing
+
+// File: shared/factory_mocks.go
+package shared
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// testClockTime is the fixed time returned by TestValidatorFactory's clock.
+var testClockTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// TestValidatorFactory returns a factory with deterministic, network-free
+// dependencies: every domain resolves to 192.0.2.1 with a single MX host
+// "mx.<domain>", every SMTP server accepts all recipients, AbuseIPDB reports a
+// clean score for every IP, and the clock is fixed at 2024-01-01T00:00:00Z.
+func TestValidatorFactory() *ValidatorFactory {
+	return &ValidatorFactory{
+		DNSResolver:        staticResolver{},
+		SMTPDialer:         acceptAllSMTPDialer{},
+		HTTPClient:         cleanAbuseIPDBDoer{},
+		DisposableProvider: NewStaticDisposableProvider(DefaultDisposableDomains),
+		Clock:              func() time.Time { return testClockTime },
+	}
+}
+
+// staticResolver answers every lookup with fixed TEST-NET records.
+type staticResolver struct{}
+
+func (staticResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (staticResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return []*net.MX{{Host: "mx." + strings.TrimSuffix(name, ".") + ".", Pref: 10}}, nil
+}
+
+func (staticResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	if network == "ip6" {
+		return nil, nil
+	}
+	return []net.IP{net.ParseIP("192.0.2.1")}, nil
+}
+
+func (staticResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, nil
+}
+
+// acceptAllSMTPDialer returns in-memory connections to an SMTP server that accepts every command.
+type acceptAllSMTPDialer struct{}
+
+func (acceptAllSMTPDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go serveAcceptAllSMTP(server)
+	return client, nil
+}
+
+// serveAcceptAllSMTP speaks just enough SMTP to answer a mailbox probe with 250.
+func serveAcceptAllSMTP(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	if _, err := io.WriteString(conn, "220 mock ESMTP ready\r\n"); err != nil {
+		return
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		if strings.HasPrefix(strings.ToUpper(line), "QUIT") {
+			io.WriteString(conn, "221 bye\r\n")
+			return
+		}
+		if _, err := io.WriteString(conn, "250 OK\r\n"); err != nil {
+			return
+		}
+	}
+}
+
+// cleanAbuseIPDBDoer answers every AbuseIPDB check with a zero abuse score.
+type cleanAbuseIPDBDoer struct{}
+
+func (cleanAbuseIPDBDoer) Do(req *http.Request) (*http.Response, error) {
+	body := fmt.Sprintf(`{"data":{"ipAddress":%q,"isPublic":true,"abuseConfidenceScore":0,"totalReports":0}}`,
+		req.URL.Query().Get("ipAddress"))
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
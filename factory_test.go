@@ -0,0 +1,38 @@
+package shared
+
+import "testing"
+
+func TestBuildEnhancedUsesFactoryDependencies(t *testing.T) {
+	factory := &ValidatorFactory{DNSResolver: acmeOnlyResolver{}, HTTPClient: cleanAbuseIPDBClient}
+
+	v := factory.BuildEnhanced(DefaultValidatorConfig(), "test-key")
+	defer v.Shutdown()
+
+	if v.basicValidator.resolver != DNSResolver(acmeOnlyResolver{}) {
+		t.Errorf("basic validator resolver = %v, want the factory's", v.basicValidator.resolver)
+	}
+	if v.abuseIPDB.httpClient != cleanAbuseIPDBClient {
+		t.Errorf("AbuseIPDB client sends with %v, want the factory's HTTP client", v.abuseIPDB.httpClient)
+	}
+	if v.abuseIPDB.apiKey != "test-key" {
+		t.Errorf("AbuseIPDB key = %q, want test-key", v.abuseIPDB.apiKey)
+	}
+}
+
+func TestBuildEnhancedAppliesCallerOptionsLast(t *testing.T) {
+	factory := &ValidatorFactory{DNSResolver: fakeResolver{}}
+	basic := (&ValidatorFactory{DNSResolver: acmeOnlyResolver{}}).Build(DefaultValidatorConfig())
+
+	v := factory.BuildEnhanced(DefaultValidatorConfig(), "test-key",
+		WithBasicValidator(basic),
+		WithHTTPClient(cleanAbuseIPDBClient),
+	)
+	defer v.Shutdown()
+
+	if v.basicValidator != basic {
+		t.Error("WithBasicValidator was overridden by the factory's validator")
+	}
+	if v.abuseIPDB.httpClient != cleanAbuseIPDBClient {
+		t.Errorf("AbuseIPDB client sends with %v, want the WithHTTPClient client", v.abuseIPDB.httpClient)
+	}
+}
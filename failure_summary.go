@@ -0,0 +1,112 @@
+// File: shared/failure_summary.go
+package shared
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSampleEmails caps the sample addresses kept per failure type.
+const maxSampleEmails = 5
+
+// FailureSummary describes one failure type in a batch.
+type FailureSummary struct {
+	SubStatus    string   `json:"sub_status"`
+	Count        int      `json:"count"`
+	Percentage   float64  `json:"percentage"` // of all results in the batch
+	SampleEmails []string `json:"sample_emails"`
+}
+
+// DomainCount pairs a domain with the number of failed results on it.
+type DomainCount struct {
+	Domain string `json:"domain"`
+	Count  int    `json:"count"`
+}
+
+// GroupResultsByFailureType groups results that aren't valid by SubStatus.
+// Results without a SubStatus are grouped by their Status instead.
+func GroupResultsByFailureType(results []*Result) map[string][]*Result {
+	groups := make(map[string][]*Result)
+	for _, r := range results {
+		if r == nil || Status(r.Status) == StatusValid {
+			continue
+		}
+		key := failureType(r)
+		groups[key] = append(groups[key], r)
+	}
+	return groups
+}
+
+// SummarizeFailures summarizes each failure type in results, most frequent first.
+func SummarizeFailures(results []*Result) []FailureSummary {
+	total := 0
+	for _, r := range results {
+		if r != nil {
+			total++
+		}
+	}
+
+	groups := GroupResultsByFailureType(results)
+	summaries := make([]FailureSummary, 0, len(groups))
+	for subStatus, group := range groups {
+		summary := FailureSummary{
+			SubStatus:  subStatus,
+			Count:      len(group),
+			Percentage: float64(len(group)) / float64(total) * 100,
+		}
+		for _, r := range group[:min(len(group), maxSampleEmails)] {
+			summary.SampleEmails = append(summary.SampleEmails, r.Email)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].SubStatus < summaries[j].SubStatus
+	})
+
+	return summaries
+}
+
+// TopFailureDomains returns the n domains with the most failed results, most frequent first.
+func TopFailureDomains(results []*Result, n int) []DomainCount {
+	counts := make(map[string]int)
+	for _, r := range results {
+		if r == nil || Status(r.Status) == StatusValid {
+			continue
+		}
+		at := strings.LastIndex(r.Email, "@")
+		if at < 0 {
+			continue
+		}
+		counts[strings.ToLower(r.Email[at+1:])]++
+	}
+
+	domains := make([]DomainCount, 0, len(counts))
+	for domain, count := range counts {
+		domains = append(domains, DomainCount{Domain: domain, Count: count})
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].Count != domains[j].Count {
+			return domains[i].Count > domains[j].Count
+		}
+		return domains[i].Domain < domains[j].Domain
+	})
+
+	if n >= 0 && len(domains) > n {
+		domains = domains[:n]
+	}
+
+	return domains
+}
+
+// failureType returns the grouping key for a failed result.
+func failureType(r *Result) string {
+	if r.SubStatus != "" {
+		return r.SubStatus
+	}
+	return strings.ToUpper(r.Status)
+}
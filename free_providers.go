@@ -0,0 +1,106 @@
+// File: shared/free_providers.go
+package shared
+
+import (
+	"strings"
+)
+
+// SubStatusFreeTierProvider marks addresses hosted by a consumer/free-tier provider.
+const SubStatusFreeTierProvider = "FREE_TIER_PROVIDER"
+
+// TagFreeProvider is added to Result.Tags for addresses hosted by a free-tier provider.
+const TagFreeProvider = "free-provider"
+
+// DefaultFreeProviderDomains lists well-known consumer/free-tier email providers.
+var DefaultFreeProviderDomains = map[string]bool{
+	// Google
+	"gmail.com": true, "googlemail.com": true,
+	// Microsoft
+	"hotmail.com": true, "hotmail.co.uk": true, "hotmail.fr": true, "hotmail.de": true,
+	"hotmail.it": true, "hotmail.es": true, "hotmail.ca": true, "hotmail.com.au": true,
+	"hotmail.com.br": true, "hotmail.co.jp": true, "hotmail.nl": true, "hotmail.be": true,
+	"outlook.com": true, "outlook.fr": true, "outlook.de": true, "outlook.it": true,
+	"outlook.es": true, "outlook.jp": true, "outlook.com.au": true, "outlook.com.br": true,
+	"live.com": true, "live.co.uk": true, "live.fr": true, "live.de": true, "live.it": true,
+	"live.nl": true, "live.ca": true, "live.com.au": true, "live.com.mx": true,
+	"msn.com": true, "passport.com": true,
+	// Yahoo
+	"yahoo.com": true, "yahoo.co.uk": true, "yahoo.fr": true, "yahoo.de": true,
+	"yahoo.it": true, "yahoo.es": true, "yahoo.ca": true, "yahoo.com.au": true,
+	"yahoo.com.br": true, "yahoo.com.mx": true, "yahoo.com.ar": true, "yahoo.co.in": true,
+	"yahoo.co.jp": true, "yahoo.com.sg": true, "yahoo.com.ph": true, "yahoo.co.id": true,
+	"yahoo.com.hk": true, "yahoo.com.tw": true, "yahoo.gr": true, "yahoo.ie": true,
+	"yahoo.in": true, "yahoo.se": true, "yahoo.dk": true, "yahoo.no": true, "yahoo.pl": true,
+	"ymail.com": true, "rocketmail.com": true,
+	// Apple
+	"icloud.com": true, "me.com": true, "mac.com": true,
+	// AOL / Verizon
+	"aol.com": true, "aol.co.uk": true, "aol.fr": true, "aol.de": true, "aim.com": true,
+	"verizon.net": true,
+	// Privacy-focused
+	"protonmail.com": true, "protonmail.ch": true, "proton.me": true, "pm.me": true,
+	"tutanota.com": true, "tutanota.de": true, "tutamail.com": true, "tuta.io": true,
+	"tuta.com": true, "keemail.me": true, "mailfence.com": true, "posteo.de": true,
+	"posteo.net": true, "disroot.org": true, "riseup.net": true, "countermail.com": true,
+	"startmail.com": true, "hushmail.com": true, "hush.com": true, "runbox.com": true,
+	"kolabnow.com": true, "ctemplar.com": true, "skiff.com": true,
+	// Zoho / Fastmail / GMX / Mail.com
+	"zohomail.com": true, "zoho.com": true, "fastmail.com": true, "fastmail.fm": true,
+	"gmx.com": true, "gmx.net": true, "gmx.de": true, "gmx.at": true, "gmx.ch": true,
+	"gmx.fr": true, "gmx.co.uk": true, "gmx.us": true, "mail.com": true, "email.com": true,
+	"usa.com": true, "post.com": true, "consultant.com": true, "myself.com": true,
+	"europe.com": true, "engineer.com": true, "asia.com": true, "dr.com": true,
+	"iname.com": true, "writeme.com": true, "cheerful.com": true, "techie.com": true,
+	// Europe
+	"web.de": true, "t-online.de": true, "freenet.de": true, "arcor.de": true,
+	"online.de": true, "orange.fr": true, "wanadoo.fr": true, "free.fr": true,
+	"laposte.net": true, "sfr.fr": true, "neuf.fr": true, "bbox.fr": true,
+	"libero.it": true, "virgilio.it": true, "tiscali.it": true, "alice.it": true,
+	"tin.it": true, "email.it": true, "inwind.it": true, "terra.es": true,
+	"telefonica.net": true, "btinternet.com": true, "sky.com": true,
+	"virginmedia.com": true, "talktalk.net": true, "ntlworld.com": true,
+	"blueyonder.co.uk": true, "tiscali.co.uk": true, "seznam.cz": true,
+	"centrum.cz": true, "email.cz": true, "atlas.cz": true, "wp.pl": true, "o2.pl": true,
+	"onet.pl": true, "interia.pl": true, "op.pl": true, "tlen.pl": true, "poczta.fm": true,
+	"azet.sk": true, "centrum.sk": true, "abv.bg": true, "mail.bg": true, "freemail.hu": true,
+	"citromail.hu": true, "telenet.be": true, "skynet.be": true, "ziggo.nl": true,
+	"kpnmail.nl": true, "planet.nl": true, "home.nl": true, "bluewin.ch": true,
+	"sunrise.ch": true, "chello.at": true, "aon.at": true, "sapo.pt": true,
+	"netcabo.pt": true, "hotmail.pt": true, "mail.ee": true, "inbox.lv": true,
+	"inbox.lt": true, "suomi24.fi": true, "luukku.com": true, "telia.com": true,
+	"online.no": true,
+	// Russia / CIS
+	"yandex.com": true, "yandex.ru": true, "ya.ru": true, "mail.ru": true, "inbox.ru": true,
+	"list.ru": true, "bk.ru": true, "rambler.ru": true, "ukr.net": true, "i.ua": true,
+	"meta.ua": true,
+	// Asia
+	"qq.com": true, "163.com": true, "126.com": true, "yeah.net": true, "sina.com": true,
+	"sina.cn": true, "sohu.com": true, "aliyun.com": true, "139.com": true, "189.cn": true,
+	"naver.com": true, "hanmail.net": true, "daum.net": true, "nate.com": true,
+	"rediffmail.com": true, "indiatimes.com": true, "sify.com": true,
+	"docomo.ne.jp": true, "ezweb.ne.jp": true, "softbank.ne.jp": true, "nifty.com": true,
+	// Americas
+	"comcast.net": true, "att.net": true, "sbcglobal.net": true, "bellsouth.net": true,
+	"charter.net": true, "cox.net": true, "earthlink.net": true, "juno.com": true,
+	"netzero.net": true, "optonline.net": true, "frontier.com": true, "windstream.net": true,
+	"roadrunner.com": true, "rr.com": true, "shaw.ca": true, "rogers.com": true,
+	"sympatico.ca": true, "telus.net": true, "videotron.ca": true, "bol.com.br": true,
+	"uol.com.br": true, "terra.com.br": true, "ig.com.br": true, "prodigy.net.mx": true,
+	// Oceania / Africa
+	"bigpond.com": true, "bigpond.net.au": true, "optusnet.com.au": true,
+	"iinet.net.au": true, "xtra.co.nz": true, "mweb.co.za": true, "webmail.co.za": true,
+	// Misc
+	"lycos.com": true, "excite.com": true, "inbox.com": true, "mail2world.com": true,
+	"hey.com": true, "duck.com": true,
+}
+
+// IsFreeTierProvider checks if the domain belongs to a known consumer/free-tier email provider.
+func IsFreeTierProvider(domain string) bool {
+	return isFreeTierProvider(domain, DefaultFreeProviderDomains)
+}
+
+// isFreeTierProvider checks the domain against the given provider map.
+func isFreeTierProvider(domain string, providers map[string]bool) bool {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	return providers[domain]
+}
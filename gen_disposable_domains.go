@@ -0,0 +1,77 @@
+//go:build ignore
+
+// gen_disposable_domains regenerates disposable_domains.txt from the
+// disposable-email-domains project's blocklist. Run it with go generate.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	sourceURL = "https://raw.githubusercontent.com/disposable-email-domains/disposable-email-domains/main/disposable_email_blocklist.conf"
+	project   = "https://github.com/disposable-email-domains/disposable-email-domains"
+	output    = "disposable_domains.txt"
+
+	// minDomains guards against writing a truncated or error page.
+	minDomains = 10000
+)
+
+func main() {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(sourceURL)
+	if err != nil {
+		log.Fatalf("fetching %s: %v", sourceURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("fetching %s: %s", sourceURL, resp.Status)
+	}
+
+	seen := make(map[string]bool)
+	var domains []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		domain := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if domain == "" || strings.HasPrefix(domain, "#") || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("reading %s: %v", sourceURL, err)
+	}
+	if len(domains) < minDomains {
+		log.Fatalf("%s lists only %d domains, want at least %d", sourceURL, len(domains), minDomains)
+	}
+	slices.Sort(domains)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Disposable email domains\n")
+	fmt.Fprintf(&b, "#\n")
+	fmt.Fprintf(&b, "# Generated by gen_disposable_domains.go; do not edit. Add local entries\n")
+	fmt.Fprintf(&b, "# to DefaultDisposableDomains instead.\n")
+	fmt.Fprintf(&b, "#\n")
+	fmt.Fprintf(&b, "# Source:  %s\n", project)
+	fmt.Fprintf(&b, "# License: CC0 1.0 Universal\n")
+	fmt.Fprintf(&b, "# Fetched: %s (%d domains)\n", time.Now().UTC().Format(time.DateOnly), len(domains))
+	fmt.Fprintf(&b, "#\n")
+	fmt.Fprintf(&b, "# One domain per line. Subdomains of listed domains are not matched\n")
+	fmt.Fprintf(&b, "# automatically.\n")
+	for _, domain := range domains {
+		b.WriteString(domain)
+		b.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(output, []byte(b.String()), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
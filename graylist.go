@@ -0,0 +1,138 @@
+// File: shared/graylist.go
+package shared
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultGraylistPollInterval is used by NewGraylistQueue for non-positive poll intervals.
+const defaultGraylistPollInterval = time.Second
+
+// GraylistQueue is an in-memory Queue that holds greylisted jobs until their
+// NotBefore time has passed. Ready jobs are delivered on ConsumeJobs and final
+// results are delivered on ConsumeResults.
+type GraylistQueue struct {
+	mu        sync.Mutex
+	pending   []ValidationJob
+	jobs      chan ValidationJob
+	results   chan Result
+	done      chan struct{}
+	stopped   chan struct{} // closed when poll exits
+	closeOnce sync.Once
+}
+
+// NewGraylistQueue creates a new greylisting retry queue that checks for ready
+// jobs every pollInterval. A non-positive pollInterval uses one second.
+func NewGraylistQueue(pollInterval time.Duration) *GraylistQueue {
+	if pollInterval <= 0 {
+		pollInterval = defaultGraylistPollInterval
+	}
+
+	q := &GraylistQueue{
+		jobs:    make(chan ValidationJob),
+		results: make(chan Result, 100),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go q.poll(pollInterval)
+
+	return q
+}
+
+// PublishJob adds a job to the queue. The job is released once its NotBefore has passed.
+func (q *GraylistQueue) PublishJob(job ValidationJob) error {
+	select {
+	case <-q.done:
+		return errors.New("graylist queue is closed")
+	default:
+	}
+
+	q.mu.Lock()
+	q.pending = append(q.pending, job)
+	q.mu.Unlock()
+
+	return nil
+}
+
+// ConsumeJobs returns a channel of jobs whose NotBefore has passed.
+func (q *GraylistQueue) ConsumeJobs() (<-chan ValidationJob, error) {
+	return q.jobs, nil
+}
+
+// PublishResult publishes a final result for a greylisted job.
+func (q *GraylistQueue) PublishResult(result Result) error {
+	select {
+	case q.results <- result:
+		return nil
+	case <-q.done:
+		return errors.New("graylist queue is closed")
+	}
+}
+
+// ConsumeResults returns a channel of final results for greylisted jobs.
+func (q *GraylistQueue) ConsumeResults() (<-chan Result, error) {
+	return q.results, nil
+}
+
+// Close stops the queue and waits for its polling goroutine to exit, which
+// closes the ConsumeJobs channel. Pending jobs are discarded.
+func (q *GraylistQueue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.done)
+	})
+	<-q.stopped
+	return nil
+}
+
+// Len returns the number of jobs still waiting for their NotBefore time.
+func (q *GraylistQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// poll periodically releases ready jobs onto the jobs channel.
+func (q *GraylistQueue) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(q.stopped)
+	defer close(q.jobs)
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case now := <-ticker.C:
+			for _, job := range q.popReady(now) {
+				select {
+				case q.jobs <- job:
+				case <-q.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// popReady removes and returns all jobs whose NotBefore is not after now.
+func (q *GraylistQueue) popReady(now time.Time) []ValidationJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var ready []ValidationJob
+	remaining := q.pending[:0]
+	for _, job := range q.pending {
+		if job.NotBefore.After(now) {
+			remaining = append(remaining, job)
+		} else {
+			ready = append(ready, job)
+		}
+	}
+	q.pending = remaining
+
+	return ready
+}
@@ -0,0 +1,115 @@
+// File: shared/idna.go
+package shared
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+// IDNAStandard selects the rules used to validate internationalized domain names.
+type IDNAStandard string
+
+const (
+	// IDNA2003 applies the lenient transitional mapping, e.g. "ß" becomes "ss".
+	IDNA2003 IDNAStandard = "IDNA2003"
+	// IDNA2008 applies the strict rules, rejecting e.g. symbols, emoji and
+	// labels that aren't valid host names.
+	IDNA2008 IDNAStandard = "IDNA2008"
+)
+
+// IDNAConfig holds the settings used to normalize domains.
+type IDNAConfig struct {
+	// Standard is IDNA2003 or IDNA2008. Empty uses IDNA2008.
+	Standard IDNAStandard `json:"standard" yaml:"standard"`
+}
+
+// DefaultIDNAConfig returns the IDNA settings used by DefaultValidatorConfig.
+func DefaultIDNAConfig() IDNAConfig {
+	return IDNAConfig{Standard: IDNA2008}
+}
+
+// Profiles implementing each IDNA standard
+var (
+	idna2003Profile = idna.New(idna.MapForLookup(), idna.Transitional(true))
+	idna2008Profile = idna.New(
+		idna.MapForLookup(),
+		idna.BidiRule(),
+		idna.VerifyDNSLength(true),
+		idna.StrictDomainName(true),
+	)
+)
+
+// ErrIDNAViolation is returned when a domain violates the selected IDNA standard.
+type ErrIDNAViolation struct {
+	Input    string
+	Standard IDNAStandard
+	Detail   string
+}
+
+// Error implements the error interface.
+func (e *ErrIDNAViolation) Error() string {
+	return fmt.Sprintf("domain %q violates %s: %s", e.Input, e.Standard, e.Detail)
+}
+
+// NormalizeDomain converts domain to its lower-cased ASCII (punycode) form
+// using the configured IDNA standard. A trailing dot is removed. An
+// *ErrIDNAViolation is returned if the domain isn't valid under the standard.
+func NormalizeDomain(domain string, cfg IDNAConfig) (string, error) {
+	standard := cfg.Standard
+	if standard == "" {
+		standard = IDNA2008
+	}
+
+	profile := idna2008Profile
+	if standard == IDNA2003 {
+		profile = idna2003Profile
+	}
+
+	ascii, err := profile.ToASCII(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	if err != nil {
+		return "", &ErrIDNAViolation{Input: domain, Standard: standard, Detail: err.Error()}
+	}
+
+	// The UTS #46 tables still accept symbols such as emoji, which IDNA2008 disallows
+	if standard == IDNA2008 {
+		unicodeForm, _ := profile.ToUnicode(ascii)
+		for _, r := range unicodeForm {
+			if !isIDNA2008Rune(r) {
+				return "", &ErrIDNAViolation{Input: domain, Standard: standard, Detail: fmt.Sprintf("disallowed rune %U", r)}
+			}
+		}
+	}
+
+	return strings.ToLower(ascii), nil
+}
+
+// isIDNA2008Rune reports whether r may appear in an IDNA2008 domain: letters,
+// combining marks and digits (RFC 5892 LetterDigits), hyphens, dots and the
+// joiners permitted by CONTEXTJ rules.
+func isIDNA2008Rune(r rune) bool {
+	switch r {
+	case '-', '.', '\u200c', '\u200d':
+		return true
+	}
+	return unicode.In(r, unicode.Ll, unicode.Lo, unicode.Lm, unicode.Mn, unicode.Mc, unicode.Nd)
+}
+
+// SubStatusInvalidDomainLabel marks addresses whose domain has a label
+// reserved by RFC 5891, see HasInvalidLabelHyphens.
+const SubStatusInvalidDomainLabel = "INVALID_DOMAIN_LABEL"
+
+// HasInvalidLabelHyphens reports whether a label of domain has hyphens in its
+// third and fourth positions without being a Punycode "xn--" label. RFC 5891
+// §4.2.3.1 reserves such labels (e.g. "ab--cd") for future encodings, so no
+// registry hands them out. Hyphen pairs elsewhere in a label are allowed.
+func HasInvalidLabelHyphens(domain string) bool {
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) >= 4 && label[2:4] == "--" && !strings.EqualFold(label[:2], "xn") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+//go:build integration
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// The test zone served by CoreDNS. Mail for pipeline.internal goes to MailHog,
+// which accepts every recipient.
+const (
+	integrationCorefile = `pipeline.internal {
+    file /zones/pipeline.internal.db
+    log
+    errors
+}
+`
+	integrationZone = `$ORIGIN pipeline.internal.
+$TTL 60
+@       IN SOA ns.pipeline.internal. admin.pipeline.internal. 1 3600 600 86400 60
+@       IN NS  ns
+ns      IN A   192.0.2.53
+@       IN A   192.0.2.80
+@       IN MX  10 mail
+mail    IN A   192.0.2.25
+nullmx  IN A   192.0.2.81
+nullmx  IN MX  0 .
+nomx    IN A   192.0.2.82
+`
+)
+
+// startContainer starts req and terminates the container when t finishes.
+func startContainer(t *testing.T, ctx context.Context, req testcontainers.ContainerRequest) testcontainers.Container {
+	t.Helper()
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{ContainerRequest: req, Started: true})
+	testcontainers.CleanupContainer(t, c)
+	if err != nil {
+		t.Fatalf("starting %s: %v", req.Image, err)
+	}
+	return c
+}
+
+// containerAddr returns the host address of c's mapped port.
+func containerAddr(t *testing.T, ctx context.Context, c testcontainers.Container, port string) string {
+	t.Helper()
+	host, err := c.Host(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapped, err := c.MappedPort(ctx, nat.Port(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return net.JoinHostPort(host, mapped.Port())
+}
+
+// mappedSMTPDialer sends every SMTP connection to addr, where the test's
+// mail server listens, whatever MX host was looked up.
+type mappedSMTPDialer struct {
+	addr   string
+	dialer net.Dialer
+}
+
+func (d *mappedSMTPDialer) DialContext(ctx context.Context, network, _ string) (net.Conn, error) {
+	return d.dialer.DialContext(ctx, network, d.addr)
+}
+
+func TestIntegrationFullPipeline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	mailhog := startContainer(t, ctx, testcontainers.ContainerRequest{
+		Image:        "mailhog/mailhog:v1.0.1",
+		ExposedPorts: []string{"1025/tcp"},
+		WaitingFor:   wait.ForListeningPort("1025/tcp"),
+	})
+	coredns := startContainer(t, ctx, testcontainers.ContainerRequest{
+		Image:        "coredns/coredns:1.11.3",
+		ExposedPorts: []string{"53/udp"},
+		Cmd:          []string{"-conf", "/Corefile"},
+		Files: []testcontainers.ContainerFile{
+			{Reader: strings.NewReader(integrationCorefile), ContainerFilePath: "/Corefile", FileMode: 0o644},
+			{Reader: strings.NewReader(integrationZone), ContainerFilePath: "/zones/pipeline.internal.db", FileMode: 0o644},
+		},
+		WaitingFor: wait.ForLog("CoreDNS-"),
+	})
+
+	dnsAddr := containerAddr(t, ctx, coredns, "53/udp")
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, dnsAddr)
+		},
+	}
+
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = 10 * time.Second
+	basic := (&ValidatorFactory{
+		DNSResolver: resolver,
+		SMTPDialer:  &mappedSMTPDialer{addr: containerAddr(t, ctx, mailhog, "1025/tcp")},
+	}).Build(cfg)
+
+	cache := NewMemoryResultCache(DefaultResultCacheConfig())
+	v := NewEnhancedValidatorWithOptions(
+		WithBasicValidator(basic),
+		WithHTTPClient(cleanAbuseIPDBClient),
+		WithResultCache(cache, DefaultResultCacheConfig()),
+	)
+	defer v.Shutdown()
+
+	tests := []struct {
+		email string
+		want  Status
+	}{
+		{"alice@pipeline.internal", StatusValid},
+		{"bob.smith@pipeline.internal", StatusValid},
+		{"user@nullmx.pipeline.internal", StatusInvalid},
+		{"user@nomx.pipeline.internal", StatusInvalid},
+		{"user@missing.pipeline.internal", StatusInvalid},
+		{"not-an-address", StatusInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.ValidateEmailWithReputation(tt.email)
+			if got := Status(result.Status); got != tt.want {
+				t.Fatalf("status = %s (%s), want %s", got, result.Reason, tt.want)
+			}
+
+			cached, ok := cache.Get(tt.email)
+			if !ok {
+				t.Fatal("result was not cached")
+			}
+			if cached.Status != result.Status {
+				t.Errorf("cached status = %s, want %s", cached.Status, result.Status)
+			}
+			if again := v.ValidateEmailWithReputation(tt.email); again != cached {
+				t.Error("second validation did not use the cached result")
+			}
+		})
+	}
+
+	// The mail server address from the zone was checked for reputation
+	alice, _ := cache.Get("alice@pipeline.internal")
+	if ips := fmt.Sprint(alice.Metadata["mail_server_ips"]); !strings.Contains(ips, "192.0.2.25") {
+		t.Errorf("mail_server_ips = %s, want the zone's MX address 192.0.2.25", ips)
+	}
+}
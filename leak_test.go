@@ -0,0 +1,126 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// roundTripFunc is an http.RoundTripper answering requests in process.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// cleanAbuseIPDBClient answers every AbuseIPDB request with a clean report.
+var cleanAbuseIPDBClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"data":{"ipAddress":"192.0.2.1","abuseConfidenceScore":0}}`)),
+		Request:    req,
+	}, nil
+})}
+
+func TestEnhancedValidatorShutdownNoLeak(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	cfg := DefaultValidatorConfig()
+	cfg.SMTPTimeout = time.Second
+	cfg.AsyncWorkers = 4
+	basic := (&ValidatorFactory{DNSResolver: mxPerDomainResolver{}, SMTPDialer: &MockSMTPDialer{}}).Build(cfg)
+
+	queue := NewGraylistQueue(10 * time.Millisecond)
+	defer queue.Close()
+
+	v := NewEnhancedValidatorWithOptions(
+		WithBasicValidator(basic),
+		WithHTTPClient(cleanAbuseIPDBClient),
+		WithGraylistQueue(queue),
+		WithCacheExpiry(20*time.Millisecond), // runs the eviction loop during the test
+	)
+
+	var wg sync.WaitGroup
+	for _, email := range []string{"alice@acme.io", "bob@acme.io", "carol@example.org"} {
+		wg.Add(1)
+		v.ValidateEmailAsync(context.Background(), email, func(*Result) { wg.Done() })
+	}
+	wg.Wait()
+	time.Sleep(30 * time.Millisecond)
+
+	v.Shutdown()
+}
+
+func TestGraylistQueueCloseNoLeak(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	q := NewGraylistQueue(time.Millisecond)
+	jobs, err := q.ConsumeJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.PublishJob(ValidationJob{JobID: "1", Email: "alice@acme.io", NotBefore: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-jobs; ok {
+		t.Error("ConsumeJobs channel delivered a job after Close, want it closed")
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestExportResultsToJSONDrainsOnErrorNoLeak(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	results := make(chan *Result)
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(results)
+		for i := 0; i < 10; i++ {
+			results <- &Result{Email: "alice@acme.io", Status: StatusValid.String()}
+		}
+	}()
+
+	if err := ExportResultsToJSON(context.Background(), results, failingWriter{}); err == nil {
+		t.Fatal("ExportResultsToJSON succeeded with a failing writer")
+	}
+	<-produced
+}
+
+func TestExportResultsToJSONDrainsOnCancelNoLeak(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// With both ready, select picks at random; over this many results the
+	// cancelled context is all but certain to be seen before results closes.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := make(chan *Result)
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(results)
+		for i := 0; i < 1000; i++ {
+			results <- &Result{Email: "alice@acme.io", Status: StatusValid.String()}
+		}
+	}()
+
+	if err := ExportResultsToJSON(ctx, results, io.Discard); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExportResultsToJSON error = %v, want context.Canceled", err)
+	}
+	<-produced
+}
@@ -0,0 +1,50 @@
+// File: shared/lmtp.go
+package shared
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// cmdLhlo is the LMTP greeting, used in place of EHLO/HELO
+const cmdLhlo = "LHLO %s"
+
+// Protocol names reported in SMTPResult.ProtocolUsed
+const (
+	protocolSMTP = "SMTP"
+	protocolLMTP = "LMTP"
+)
+
+// LMTPDialer probes mailboxes on local delivery agents that speak LMTP
+// (RFC 2033), such as Dovecot or Cyrus IMAP. The conversation matches SMTP
+// except that the client greets with LHLO and the server listens on port 24.
+type LMTPDialer struct {
+	Dialer SMTPDialer
+	Config SMTPConfig
+}
+
+// NewLMTPDialer creates an LMTPDialer that connects through dialer. A nil
+// dialer connects directly. cfg.UseLMTP is forced on.
+func NewLMTPDialer(dialer SMTPDialer, cfg SMTPConfig) *LMTPDialer {
+	if dialer == nil {
+		dialer = NewNetSMTPDialer()
+	}
+	cfg.UseLMTP = true
+	return &LMTPDialer{Dialer: dialer, Config: cfg}
+}
+
+// Check verifies email against servers in order, like CheckSMTPWithConfig but
+// over LMTP, probing each with checkLMTPServer.
+func (d *LMTPDialer) Check(email string, servers []*net.MX, timeout time.Duration) SMTPResult {
+	return checkServers(servers, d.Config.maxMXAttempts(), func(host string) SMTPResult {
+		return d.checkLMTPServer(email, host, timeout)
+	})
+}
+
+// checkLMTPServer verifies email against a single LMTP server.
+func (d *LMTPDialer) checkLMTPServer(email, host string, timeout time.Duration) SMTPResult {
+	cfg := d.Config
+	cfg.UseLMTP = true
+	return checkSMTPServer(context.Background(), d.Dialer, email, host, timeout, cfg)
+}
@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLMTPDialerCheck(t *testing.T) {
+	var conn *MockSMTPConn
+	dialer := &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+		conn = NewMockSMTPConn("220 lmtp.acme.io LMTP ready", nil)
+		return conn, nil
+	}}
+
+	result := NewLMTPDialer(dialer, DefaultSMTPConfig()).Check("alice@acme.io", []*net.MX{{Host: "lmtp.acme.io.", Pref: 10}}, time.Second)
+
+	if result.Status != StatusValid {
+		t.Fatalf("status = %s (%s), want valid", result.Status, result.Reason)
+	}
+	if result.ProtocolUsed != protocolLMTP {
+		t.Errorf("ProtocolUsed = %q, want %q", result.ProtocolUsed, protocolLMTP)
+	}
+	if dialed := dialer.Dialed(); len(dialed) != 1 || !strings.HasSuffix(dialed[0], ":24") {
+		t.Errorf("dialed %v, want the LMTP port 24", dialed)
+	}
+	if commands := conn.Commands(); len(commands) == 0 || !strings.HasPrefix(commands[0], "LHLO ") {
+		t.Errorf("commands = %q, want LHLO first", commands)
+	}
+}
+
+func TestLMTPDialerCheckLMTPServer(t *testing.T) {
+	dialer := &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+		return NewMockSMTPConn("220 lmtp.acme.io LMTP ready", map[string]string{"RCPT": "550 5.1.1 no such user"}), nil
+	}}
+
+	result := NewLMTPDialer(dialer, DefaultSMTPConfig()).checkLMTPServer("nobody@acme.io", "lmtp.acme.io", time.Second)
+
+	if result.Status != StatusInvalid || result.ProtocolUsed != protocolLMTP {
+		t.Errorf("result = %s over %q (%s), want invalid over LMTP", result.Status, result.ProtocolUsed, result.Reason)
+	}
+	if dialed := dialer.Dialed(); len(dialed) != 1 || dialed[0] != "lmtp.acme.io:24" {
+		t.Errorf("dialed %v, want lmtp.acme.io:24", dialed)
+	}
+}
+
+func TestLMTPDialerCheckTriesNextServer(t *testing.T) {
+	dialer := &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+		if strings.HasPrefix(addr, "lmtp1.") {
+			return NewMockSMTPConn("421 4.3.2 service not available", nil), nil
+		}
+		return NewMockSMTPConn("220 lmtp2.acme.io LMTP ready", nil), nil
+	}}
+	servers := []*net.MX{{Host: "lmtp1.acme.io.", Pref: 10}, {Host: "lmtp2.acme.io.", Pref: 20}}
+
+	result := NewLMTPDialer(dialer, DefaultSMTPConfig()).Check("alice@acme.io", servers, time.Second)
+
+	if result.Status != StatusValid {
+		t.Fatalf("status = %s (%s), want valid from the second server", result.Status, result.Reason)
+	}
+	if dialed := dialer.Dialed(); len(dialed) != 2 {
+		t.Errorf("dialed %v, want both servers", dialed)
+	}
+}
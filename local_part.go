@@ -0,0 +1,141 @@
+// File: shared/local_part.go
+package shared
+
+import (
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SubStatusSuspiciousLocalPart marks addresses whose local part looks randomly generated.
+const SubStatusSuspiciousLocalPart = "SUSPICIOUS_LOCAL_PART"
+
+// TagSuspiciousLocalPart is added to Result.Tags for addresses with a suspicious local part.
+const TagSuspiciousLocalPart = "suspicious-local-part"
+
+// Names of the built-in local part patterns, usable in ValidatorConfig.SuspiciousLocalPartPatterns.
+const (
+	LocalPartAllDigits   = "all_digits"
+	LocalPartHighEntropy = "high_entropy"
+	LocalPartTooLong     = "too_long"
+)
+
+// Defaults for the built-in local part patterns
+const (
+	defaultLocalPartEntropyThreshold = 4.0
+	maxUnsuspiciousLocalPartLength   = 30
+)
+
+// DefaultSuspiciousLocalPartPatterns lists the patterns checked when none are configured.
+var DefaultSuspiciousLocalPartPatterns = []string{LocalPartAllDigits, LocalPartHighEntropy, LocalPartTooLong}
+
+// SuspiciousPattern flags local parts typical of generated or throwaway addresses.
+type SuspiciousPattern struct {
+	Name  string
+	Match func(local string) bool
+}
+
+// NewSuspiciousPatterns builds patterns from names. Besides the built-in names
+// (all_digits, high_entropy, too_long), each name is compiled as a regular
+// expression; invalid expressions are logged and skipped. entropyThreshold is
+// the Shannon entropy in bits per character above which high_entropy matches.
+func NewSuspiciousPatterns(names []string, entropyThreshold float64) []SuspiciousPattern {
+	patterns := make([]SuspiciousPattern, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case LocalPartAllDigits:
+			patterns = append(patterns, SuspiciousPattern{Name: name, Match: isAllDigits})
+		case LocalPartHighEntropy:
+			patterns = append(patterns, SuspiciousPattern{Name: name, Match: func(local string) bool {
+				return ShannonEntropy(local) > entropyThreshold
+			}})
+		case LocalPartTooLong:
+			patterns = append(patterns, SuspiciousPattern{Name: name, Match: func(local string) bool {
+				return utf8.RuneCountInString(local) > maxUnsuspiciousLocalPartLength
+			}})
+		default:
+			re, err := regexp.Compile(name)
+			if err != nil {
+				log.Printf("Ignoring invalid suspicious local part pattern %q: %v", name, err)
+				continue
+			}
+			patterns = append(patterns, SuspiciousPattern{Name: name, Match: re.MatchString})
+		}
+	}
+	return patterns
+}
+
+// DetectSuspiciousLocalPart checks local against patterns and returns the
+// name of the first matching pattern.
+func DetectSuspiciousLocalPart(local string, patterns []SuspiciousPattern) (bool, string) {
+	for _, pattern := range patterns {
+		if pattern.Match(local) {
+			return true, pattern.Name
+		}
+	}
+	return false, ""
+}
+
+// ShannonEntropy returns the Shannon entropy of s in bits per character.
+func ShannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// IsNumericOnlyLocalPart reports whether the local part of email consists of
+// digits only, e.g. "123456@example.com". Such addresses are valid but are
+// common for bot sign-ups and throwaway accounts.
+func IsNumericOnlyLocalPart(email string) bool {
+	return isAllDigits(localPartOf(email))
+}
+
+// LocalPartEntropy returns the Shannon entropy of the local part of email in
+// bits per character; see ShannonEntropy.
+func LocalPartEntropy(email string) float64 {
+	return ShannonEntropy(localPartOf(email))
+}
+
+// localPartOf returns the part of email before its last @, or email itself if it has none.
+func localPartOf(email string) string {
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		return email[:at]
+	}
+	return email
+}
+
+// isAllDigits reports whether s is non-empty and consists of digits only.
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// suspiciousLocalPartPatterns builds the patterns configured in cfg.
+func suspiciousLocalPartPatterns(cfg ValidatorConfig) []SuspiciousPattern {
+	names := cfg.SuspiciousLocalPartPatterns
+	if names == nil {
+		names = DefaultSuspiciousLocalPartPatterns
+	}
+	threshold := cfg.LocalPartEntropyThreshold
+	if threshold <= 0 {
+		threshold = defaultLocalPartEntropyThreshold
+	}
+	return NewSuspiciousPatterns(names, threshold)
+}
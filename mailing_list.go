@@ -0,0 +1,52 @@
+// File: shared/mailing_list.go
+package shared
+
+import (
+	"net"
+	"strings"
+)
+
+// SubStatusMailingList marks addresses that belong to a mailing list rather than a person.
+const SubStatusMailingList = "MAILING_LIST"
+
+// TagMailingList is added to Result.Tags for mailing list addresses.
+const TagMailingList = "mailing-list"
+
+// mailingListLocalParts are local parts used by list managers (Majordomo, LISTSERV, Mailman)
+var mailingListLocalParts = []string{"majordomo", "listserv", "mailman"}
+
+// mailingListSuffixes are local part suffixes list managers add for list administration
+var mailingListSuffixes = []string{"-request", "-owner", "-bounces", "-subscribe", "-unsubscribe"}
+
+// IsMailingListAddress reports whether email looks like a mailing list address:
+// the domain or one of its MX hosts is a lists.* host, or the local part is a
+// well-known list manager address. Mailing lists accept all mail, so an SMTP
+// check says nothing about a person behind the address.
+func IsMailingListAddress(email string, mxRecords []*net.MX) bool {
+	localPart, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	if !ok {
+		return false
+	}
+
+	if strings.HasPrefix(domain, "lists.") {
+		return true
+	}
+	for _, mx := range mxRecords {
+		host := strings.ToLower(mx.Host)
+		if strings.HasPrefix(host, "lists.") || strings.Contains(host, ".lists.") {
+			return true
+		}
+	}
+
+	for _, name := range mailingListLocalParts {
+		if localPart == name || strings.HasPrefix(localPart, name+"-") {
+			return true
+		}
+	}
+	for _, suffix := range mailingListSuffixes {
+		if strings.HasSuffix(localPart, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+// File: shared/markdown.go
+package shared
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// markdownFuncs are the helpers available to the Markdown templates.
+var markdownFuncs = template.FuncMap{
+	"cell":  markdownCell,
+	"badge": statusBadge,
+	"yesno": func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	},
+	"percent": func(n, total int) string {
+		if total == 0 {
+			return "0.0%"
+		}
+		return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+	},
+}
+
+var reportMarkdownTemplate = template.Must(template.New("report").Funcs(markdownFuncs).Parse(
+	`# Validation report: {{cell .Report.Email}}
+
+**Status:** {{badge .Result.Status}}{{with .Result.Reason}} ({{cell .}}){{end}}
+
+| | |
+|---|---|
+| Score | {{.Report.Score}}/100 |
+| Duration | {{.Report.Duration}} |
+| Request ID | {{cell .Report.RequestID}} |
+
+## Format
+
+| Check | Result |
+|---|---|
+| Valid syntax | {{yesno .Report.FormatCheck.Valid}} |
+{{- with .Report.FormatCheck.Error}}
+| Error | {{cell .Error}} |
+{{- end}}
+{{- if .Report.FormatCheck.Valid}}
+| Local part | {{cell .Report.FormatCheck.LocalPart}} |
+| Domain | {{cell .Report.FormatCheck.Domain}} |
+| Role based | {{yesno .Report.RoleBasedCheck}} |
+| Disposable | {{yesno .Report.DisposableCheck}} |
+{{- end}}
+{{if .Report.FormatCheck.Valid}}
+## DNS
+
+| Check | Result |
+|---|---|
+| A/AAAA records | {{len .Report.DNSCheck.ARecords}} |
+| MX records | {{len .Report.DNSCheck.MXRecords}} |
+{{- with .Report.DNSCheck.AError}}
+| A error | {{cell .}} |
+{{- end}}
+{{- with .Report.DNSCheck.MXError}}
+| MX error | {{cell .}} |
+{{- end}}
+{{- with .Report.DNSCheck.SuspiciousPattern}}
+| Suspicious pattern | {{cell .}} |
+{{- end}}
+{{- with .Report.SPFCheck}}
+| SPF | {{if .Found}}{{cell .Record}}{{else}}not found{{end}} |
+{{- end}}
+{{- with .Report.DMARCCheck}}
+| DMARC | {{if .Found}}p={{cell .Policy}}{{else}}not found{{end}} |
+{{- end}}
+{{with .Report.DNSCheck.MXRecords}}
+MX records:
+{{range .}}
+- {{cell .Host}} (preference {{.Pref}})
+{{- end}}
+{{end}}
+## SMTP
+{{if .Report.SMTPCheck.Status}}
+| Check | Result |
+|---|---|
+| Status | {{badge .Report.SMTPCheck.Status.String}} |
+| Reason | {{cell .Report.SMTPCheck.Reason}} |
+| Code | {{.Report.SMTPCheck.Code}} |
+| Confidence | {{.Report.SMTPCheck.Confidence}}/100 |
+| Greylisted | {{yesno .Report.SMTPCheck.Greylisted}} |
+{{- with .Report.SMTPCheck.TLSCertCommonName}}
+| TLS certificate | {{cell .}} |
+{{- end}}
+{{else}}
+SMTP probing was not performed.
+{{end}}
+## IP Reputation
+{{if .Report.IPReputationChecks}}
+| IP | Abuse score | Reports | Country | ISP |
+|---|---|---|---|---|
+{{- range .Report.IPReputationChecks}}
+| {{cell .IPAddress}} | {{if .Error}}error: {{cell .Error}}{{else}}{{.AbuseConfidenceScore}}{{end}} | {{.TotalReports}} | {{cell .CountryCode}} | {{cell .ISP}} |
+{{- end}}
+{{else}}
+No IP reputation checks were performed.
+{{end}}
+{{- end}}
+## Metadata
+
+| Key | Value |
+|---|---|
+{{- range .Metadata}}
+| {{cell .Key}} | {{cell .Value}} |
+{{- end}}
+`))
+
+var batchSummaryMarkdownTemplate = template.Must(template.New("batch").Funcs(markdownFuncs).Parse(
+	`# Batch validation summary
+
+{{.Total}} addresses validated, average score {{printf "%.1f" .AverageScore}} (min {{.MinScore}}, max {{.MaxScore}}).
+
+| Status | Count | Share |
+|---|---|---|
+| {{badge "valid"}} | {{.Valid}} | {{percent .Valid .Total}} |
+| {{badge "invalid"}} | {{.Invalid}} | {{percent .Invalid .Total}} |
+| {{badge "risky"}} | {{.Risky}} | {{percent .Risky .Total}} |
+| {{badge "error"}} | {{.Error}} | {{percent .Error .Total}} |
+
+| Signal | Count |
+|---|---|
+| Disposable | {{.DisposableCount}} |
+| Role based | {{.RoleBasedCount}} |
+| Catch-all | {{.CatchAllCount}} |
+
+## Latency
+
+| Percentile | Duration |
+|---|---|
+| p50 | {{.P50Latency}} |
+| p95 | {{.P95Latency}} |
+| p99 | {{.P99Latency}} |
+{{with .TopFailureReasons}}
+## Top failure reasons
+
+| Reason | Count |
+|---|---|
+{{- range .}}
+| {{cell .Reason}} | {{.Count}} |
+{{- end}}
+{{end}}
+{{- with .Warnings}}
+## Warnings
+{{range .}}
+- {{.}}
+{{- end}}
+{{end -}}
+`))
+
+// markdownMetadataEntry is a metadata key and its formatted value.
+type markdownMetadataEntry struct {
+	Key   string
+	Value string
+}
+
+// FormatResultAsMarkdown renders the report as a Markdown document for human
+// review: the overall status as given by ToResult, a table per check, the MX
+// records and IP reputation scores, and the result metadata.
+func FormatResultAsMarkdown(r *ValidationReport) string {
+	if r == nil {
+		return ""
+	}
+
+	result := r.ToResult()
+	keys := make([]string, 0, len(result.Metadata))
+	for key := range result.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	metadata := make([]markdownMetadataEntry, len(keys))
+	for i, key := range keys {
+		metadata[i] = markdownMetadataEntry{Key: key, Value: fmt.Sprint(result.Metadata[key])}
+	}
+
+	var b strings.Builder
+	err := reportMarkdownTemplate.Execute(&b, struct {
+		Report   *ValidationReport
+		Result   *Result
+		Metadata []markdownMetadataEntry
+	}{r, result, metadata})
+	if err != nil {
+		return fmt.Sprintf("failed to render report: %v\n", err)
+	}
+	return b.String()
+}
+
+// FormatBatchSummaryAsMarkdown renders batch statistics as a Markdown document:
+// status counts, signal counts, latency percentiles, the top failure reasons
+// and any anomaly warnings.
+func FormatBatchSummaryAsMarkdown(stats BatchStats) string {
+	var b strings.Builder
+	if err := batchSummaryMarkdownTemplate.Execute(&b, stats); err != nil {
+		return fmt.Sprintf("failed to render batch summary: %v\n", err)
+	}
+	return b.String()
+}
+
+// statusBadge returns an emoji badge for a result status.
+func statusBadge(status string) string {
+	switch Status(status) {
+	case StatusValid:
+		return "✅ Valid"
+	case StatusInvalid:
+		return "❌ Invalid"
+	case StatusError:
+		return "⛔ Error"
+	case StatusRisky:
+		return "⚠️ Risky"
+	default:
+		return "⚠️ " + markdownCell(status)
+	}
+}
+
+// markdownCell makes s safe to place in a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,48 @@
+// File: shared/metadata.go
+package shared
+
+// MergeMetadata returns a new map holding base deep-merged with overlay.
+// Where both have a key, slices of the same type are appended, nested
+// map[string]interface{} values are merged recursively, and otherwise the
+// overlay value wins. Neither input is modified.
+func MergeMetadata(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+
+	for k, v := range overlay {
+		existing, exists := merged[k]
+		if !exists {
+			merged[k] = v
+			continue
+		}
+		merged[k] = mergeMetadataValue(existing, v)
+	}
+
+	return merged
+}
+
+// MergeMetadata deep-merges m into the result's metadata; see MergeMetadata.
+func (r *Result) MergeMetadata(m map[string]interface{}) {
+	r.Metadata = MergeMetadata(r.Metadata, m)
+}
+
+// mergeMetadataValue merges two values stored under the same key.
+func mergeMetadataValue(base, overlay interface{}) interface{} {
+	switch b := base.(type) {
+	case map[string]interface{}:
+		if o, ok := overlay.(map[string]interface{}); ok {
+			return MergeMetadata(b, o)
+		}
+	case []interface{}:
+		if o, ok := overlay.([]interface{}); ok {
+			return append(append([]interface{}(nil), b...), o...)
+		}
+	case []string:
+		if o, ok := overlay.([]string); ok {
+			return append(append([]string(nil), b...), o...)
+		}
+	}
+	return overlay
+}
@@ -0,0 +1,141 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    map[string]interface{}
+		overlay map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name:    "scalar override",
+			base:    map[string]interface{}{"score": 80, "provider": "gmail"},
+			overlay: map[string]interface{}{"score": 55},
+			want:    map[string]interface{}{"score": 55, "provider": "gmail"},
+		},
+		{
+			name:    "scalar replaced by different type",
+			base:    map[string]interface{}{"mx": "mx.acme.io"},
+			overlay: map[string]interface{}{"mx": []string{"mx1.acme.io", "mx2.acme.io"}},
+			want:    map[string]interface{}{"mx": []string{"mx1.acme.io", "mx2.acme.io"}},
+		},
+		{
+			name:    "string slice append",
+			base:    map[string]interface{}{"warnings": []string{"role"}},
+			overlay: map[string]interface{}{"warnings": []string{"free"}},
+			want:    map[string]interface{}{"warnings": []string{"role", "free"}},
+		},
+		{
+			name:    "interface slice append",
+			base:    map[string]interface{}{"hits": []interface{}{"a", 1}},
+			overlay: map[string]interface{}{"hits": []interface{}{true}},
+			want:    map[string]interface{}{"hits": []interface{}{"a", 1, true}},
+		},
+		{
+			name:    "mismatched slice types override",
+			base:    map[string]interface{}{"hits": []string{"a"}},
+			overlay: map[string]interface{}{"hits": []interface{}{"b"}},
+			want:    map[string]interface{}{"hits": []interface{}{"b"}},
+		},
+		{
+			name: "nested map merge",
+			base: map[string]interface{}{"dns": map[string]interface{}{
+				"mx":  "mx.acme.io",
+				"spf": map[string]interface{}{"present": true},
+			}},
+			overlay: map[string]interface{}{"dns": map[string]interface{}{
+				"dmarc": "reject",
+				"spf":   map[string]interface{}{"policy": "-all"},
+			}},
+			want: map[string]interface{}{"dns": map[string]interface{}{
+				"mx":    "mx.acme.io",
+				"dmarc": "reject",
+				"spf":   map[string]interface{}{"present": true, "policy": "-all"},
+			}},
+		},
+		{
+			name:    "nil base",
+			base:    nil,
+			overlay: map[string]interface{}{"score": 55},
+			want:    map[string]interface{}{"score": 55},
+		},
+		{
+			name:    "nil overlay",
+			base:    map[string]interface{}{"score": 80},
+			overlay: nil,
+			want:    map[string]interface{}{"score": 80},
+		},
+		{
+			name:    "both nil",
+			base:    nil,
+			overlay: nil,
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "nil overlay value wins",
+			base:    map[string]interface{}{"score": 80},
+			overlay: map[string]interface{}{"score": nil},
+			want:    map[string]interface{}{"score": nil},
+		},
+		{
+			name:    "nil base value replaced",
+			base:    map[string]interface{}{"warnings": nil},
+			overlay: map[string]interface{}{"warnings": []string{"free"}},
+			want:    map[string]interface{}{"warnings": []string{"free"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeMetadata(tt.base, tt.overlay)
+			if got == nil {
+				t.Fatal("MergeMetadata returned nil")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeMetadata = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeMetadataLeavesInputs(t *testing.T) {
+	warnings := make([]string, 1, 4) // spare capacity an in-place append would use
+	warnings[0] = "role"
+	base := map[string]interface{}{
+		"warnings": warnings,
+		"dns":      map[string]interface{}{"mx": "mx.acme.io"},
+	}
+	overlay := map[string]interface{}{
+		"warnings": []string{"free"},
+		"dns":      map[string]interface{}{"dmarc": "reject"},
+	}
+
+	merged := MergeMetadata(base, overlay)
+	merged["warnings"].([]string)[0] = "changed"
+
+	if warnings[0] != "role" {
+		t.Error("merged slice shares its backing array with base")
+	}
+	if _, ok := base["dns"].(map[string]interface{})["dmarc"]; ok {
+		t.Error("nested base map was modified")
+	}
+	if len(overlay) != 2 || len(overlay["dns"].(map[string]interface{})) != 1 {
+		t.Error("overlay was modified")
+	}
+}
+
+func TestResultMergeMetadata(t *testing.T) {
+	r := &Result{}
+	r.MergeMetadata(map[string]interface{}{"warnings": []string{"role"}})
+	r.MergeMetadata(map[string]interface{}{"warnings": []string{"free"}, "score": 55})
+
+	want := map[string]interface{}{"warnings": []string{"role", "free"}, "score": 55}
+	if !reflect.DeepEqual(r.Metadata, want) {
+		t.Errorf("Metadata = %#v, want %#v", r.Metadata, want)
+	}
+}
@@ -0,0 +1,41 @@
+// File: shared/metrics.go
+package shared
+
+import (
+	"context"
+	"log"
+)
+
+// MetricsEmitter receives validation statistics, e.g. to publish them to a
+// monitoring system. Record methods are called synchronously on the
+// validation path and should only aggregate; Flush sends what was collected.
+type MetricsEmitter interface {
+	RecordValidation(result *Result)
+	RecordIPCheck(cacheHit bool)
+	Flush(ctx context.Context) error
+}
+
+// WithMetricsEmitter reports every validation and IP reputation lookup to
+// emitter. The emitter is flushed when the validator is shut down.
+func WithMetricsEmitter(emitter MetricsEmitter) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.metrics = emitter
+	}
+}
+
+// recordValidation reports result to the metrics emitter, if one is set.
+func (v *EnhancedValidator) recordValidation(result *Result) {
+	if v.metrics != nil {
+		v.metrics.RecordValidation(result)
+	}
+}
+
+// flushMetrics sends any pending metrics, logging failures.
+func (v *EnhancedValidator) flushMetrics() {
+	if v.metrics == nil {
+		return
+	}
+	if err := v.metrics.Flush(context.Background()); err != nil {
+		log.Printf("Failed to flush validation metrics: %v", err)
+	}
+}
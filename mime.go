@@ -0,0 +1,43 @@
+// File: shared/mime.go
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// ErrInvalidFromHeader is returned by ExtractFromHeader when a message's
+// From: header is missing, cannot be parsed or holds an invalid address.
+var ErrInvalidFromHeader = errors.New("invalid From header")
+
+// ExtractFromHeader parses the headers of a raw RFC 5322 message and returns
+// the addresses in its From: header, display names stripped. Every address
+// must pass IsValidSyntax; the error wraps the *SyntaxError of the first one
+// that doesn't. Only the headers are read, so the body may be omitted.
+func ExtractFromHeader(rawMessage string) ([]string, error) {
+	msg, err := mail.ReadMessage(strings.NewReader(rawMessage))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFromHeader, err)
+	}
+
+	from := msg.Header.Get("From")
+	if from == "" {
+		return nil, fmt.Errorf("%w: header is missing", ErrInvalidFromHeader)
+	}
+
+	addrs, err := mail.ParseAddressList(from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFromHeader, err)
+	}
+
+	emails := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if !IsValidSyntax(addr.Address) {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidFromHeader, addr.Address, CheckSyntax(addr.Address))
+		}
+		emails = append(emails, addr.Address)
+	}
+	return emails, nil
+}
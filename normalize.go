@@ -0,0 +1,65 @@
+// File: shared/normalize.go
+package shared
+
+import (
+	"context"
+	"strings"
+)
+
+// ValidateOptions controls a single validation in ValidateEmailWithOptions
+// and ValidateBatchRequest.
+type ValidateOptions struct {
+	// NormalizeBeforeValidation validates the NormalizeEmail form instead of the input.
+	NormalizeBeforeValidation bool `json:"normalize_before_validation,omitempty"`
+
+	// ReturnNormalized reports the NormalizeEmail form in Result.Email, keeping
+	// the input in Result.Metadata["original_email"]. Otherwise Result.Email
+	// is the input, even if the normalized form was validated.
+	ReturnNormalized bool `json:"return_normalized,omitempty"`
+
+	// SkipSMTP skips mailbox probing even if it is enabled in the config,
+	// e.g. for internal domains whose servers shouldn't be probed.
+	SkipSMTP bool `json:"skip_smtp,omitempty"`
+}
+
+// NormalizeEmail returns the canonical form of an address: surrounding
+// whitespace removed, lower-cased, and the domain converted to its ASCII
+// (punycode) form without a trailing dot. Sub-addresses ("+tag") and dots
+// are kept since their meaning depends on the provider. Addresses without
+// an @ are only trimmed and lower-cased.
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], strings.TrimSuffix(email[at+1:], ".")
+
+	if ascii, err := NormalizeDomain(domain, DefaultIDNAConfig()); err == nil {
+		domain = ascii
+	}
+	return local + "@" + domain
+}
+
+// ValidateEmailWithOptions validates email like ValidateEmailContext, with
+// optional normalization and SMTP probing. The two options are independent: the normalized
+// form can be validated while the input is reported, or the input validated
+// as-is while the normalized form is reported.
+func (v *Validator) ValidateEmailWithOptions(ctx context.Context, email string, opts ValidateOptions) *Result {
+	normalized := NormalizeEmail(email)
+
+	target := email
+	if opts.NormalizeBeforeValidation {
+		target = normalized
+	}
+	result := v.validateEmail(ctx, target, !opts.SkipSMTP)
+
+	if opts.ReturnNormalized {
+		result.Email = normalized
+		result.Metadata["original_email"] = email
+	} else {
+		result.Email = email
+	}
+	return result
+}
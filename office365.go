@@ -0,0 +1,116 @@
+// File: shared/office365.go
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	graphBaseURL      = "https://graph.microsoft.com/v1.0"
+	graphScope        = "https://graph.microsoft.com/.default"
+	microsoftLoginFmt = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+)
+
+// Office365Checker verifies Microsoft 365 mailboxes through the Microsoft Graph
+// API, which is definitive where SMTP probing is not (see PlatformDetector).
+// The application needs the User.Read.All permission in the tenant.
+type Office365Checker struct {
+	httpClient HTTPDoer
+	baseURL    string
+}
+
+// NewOffice365Checker creates a checker authenticating with tokens from ts.
+// The token source is wrapped so tokens are cached until they expire.
+func NewOffice365Checker(ts oauth2.TokenSource) *Office365Checker {
+	return &Office365Checker{
+		httpClient: oauth2.NewClient(context.Background(), oauth2.ReuseTokenSource(nil, ts)),
+		baseURL:    graphBaseURL,
+	}
+}
+
+// NewOffice365CheckerFromCredentials creates a checker using the OAuth2 client
+// credentials flow for an Azure AD application. Tokens are cached and
+// refreshed automatically.
+func NewOffice365CheckerFromCredentials(tenantID, clientID, clientSecret string) *Office365Checker {
+	cfg := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     fmt.Sprintf(microsoftLoginFmt, url.PathEscape(tenantID)),
+		Scopes:       []string{graphScope},
+	}
+
+	return &Office365Checker{
+		httpClient: cfg.Client(context.Background()),
+		baseURL:    graphBaseURL,
+	}
+}
+
+// CheckEmailExists looks up the mailbox with GET /users/{email}. It returns
+// StatusValid on 200, StatusInvalid on 404 and StatusError with an error otherwise.
+func (c *Office365Checker) CheckEmailExists(ctx context.Context, email string) (Status, error) {
+	reqURL := fmt.Sprintf("%s/users/%s", c.baseURL, url.PathEscape(email))
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return StatusError, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return StatusError, withRequestID(ctx, fmt.Errorf("Graph request failed: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return StatusValid, nil
+	case http.StatusNotFound:
+		return StatusInvalid, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return StatusError, withRequestID(ctx, fmt.Errorf("Graph API error: %d - %s", resp.StatusCode, body))
+	}
+}
+
+// WithOffice365Checker verifies addresses on Microsoft 365 domains through the
+// Microsoft Graph API using the given Azure AD application credentials.
+func WithOffice365Checker(tenantID, clientID, clientSecret string) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		v.office365 = NewOffice365CheckerFromCredentials(tenantID, clientID, clientSecret)
+	}
+}
+
+// verifyOffice365 replaces an inconclusive result for a Microsoft 365 domain
+// with the Graph API's answer. Graph failures leave the result unchanged.
+func (v *EnhancedValidator) verifyOffice365(ctx context.Context, domain string, result *Result) {
+	info, err := v.basicValidator.LookupDomainInfo(ctx, domain)
+	if err != nil || v.basicValidator.platforms.Detect(info.MXRecords) != PlatformMicrosoft365 {
+		return
+	}
+
+	status, err := v.office365.CheckEmailExists(ctx, result.Email)
+	if err != nil {
+		result.Metadata["graph_error"] = err.Error()
+		return
+	}
+
+	result.Metadata["platform"] = string(PlatformMicrosoft365)
+	result.Metadata["graph_verified"] = true
+	delete(result.Metadata, "is_catch_all")
+	delete(result.Metadata, "recommendation")
+
+	if status == StatusValid {
+		result.Status = StatusValid.String()
+		result.Reason = "mailbox confirmed by Microsoft Graph"
+	} else {
+		result.Status = StatusInvalid.String()
+		result.Reason = "mailbox does not exist in the Microsoft 365 tenant"
+	}
+}
@@ -0,0 +1,63 @@
+// File: shared/platform.go
+package shared
+
+import (
+	"net"
+	"path"
+	"strings"
+)
+
+// Platform identifies a hosted email platform.
+type Platform string
+
+const (
+	PlatformUnknown         Platform = ""
+	PlatformMicrosoft365    Platform = "Microsoft365"
+	PlatformGoogleWorkspace Platform = "GoogleWorkspace"
+)
+
+// microsoft365Recommendation is attached to Microsoft 365 results whose SMTP check can't be trusted.
+const microsoft365Recommendation = "verify the mailbox with the Microsoft Graph API; SMTP probing is not conclusive for Microsoft 365 tenants"
+
+// PlatformDetector identifies the platform hosting a domain from its MX host names.
+//
+// Hosted platforms affect how SMTP answers must be read. Microsoft 365 (Exchange
+// Online Protection) accepts RCPT TO for any address in a tenant's accepted
+// domains and bounces unknown recipients only after the message is sent, so a
+// 250 from *.mail.protection.outlook.com doesn't prove the mailbox exists.
+// Such results are reported as StatusCatchAll rather than StatusValid. Google
+// Workspace usually rejects unknown recipients during the SMTP conversation,
+// but tenants with catch-all routing accept everything; its results are only
+// annotated with the platform.
+type PlatformDetector struct {
+	patterns map[Platform][]string
+}
+
+// NewPlatformDetector creates a detector for Microsoft 365 and Google Workspace.
+func NewPlatformDetector() *PlatformDetector {
+	return &PlatformDetector{
+		patterns: map[Platform][]string{
+			PlatformMicrosoft365: {"*.mail.protection.outlook.com"},
+			PlatformGoogleWorkspace: {
+				"aspmx.l.google.com", "alt*.aspmx.l.google.com",
+				"aspmx*.googlemail.com", "smtp.google.com",
+			},
+		},
+	}
+}
+
+// Detect returns the platform hosting the domain with the given MX records,
+// or PlatformUnknown.
+func (d *PlatformDetector) Detect(mxRecords []*net.MX) Platform {
+	for _, mx := range mxRecords {
+		host := strings.ToLower(strings.TrimSuffix(mx.Host, "."))
+		for platform, patterns := range d.patterns {
+			for _, pattern := range patterns {
+				if ok, _ := path.Match(pattern, host); ok {
+					return platform
+				}
+			}
+		}
+	}
+	return PlatformUnknown
+}
@@ -0,0 +1,99 @@
+// File: shared/prefetch.go
+package shared
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// closedChan is returned by PrefetchComplete when no prefetch has been started.
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// PrefetchIPReputation resolves the mail server IPs of domains in the
+// background and looks up the reputation of those not cached yet, so a batch
+// validating addresses on these domains finds them in the cache. Domains are
+// handled concurrently, each taking a slot of the basic validator's
+// MaxConcurrentValidations limit like a regular validation does.
+//
+// The prefetch stops early when ctx is cancelled or the validator is shut
+// down. Select on PrefetchComplete to wait for it. The cache of the tenant in
+// ctx (or the configured TenantID) is warmed.
+func (v *EnhancedValidator) PrefetchIPReputation(ctx context.Context, domains []string) {
+	done := make(chan struct{})
+	v.prefetchMu.Lock()
+	v.prefetchDone = done
+	v.prefetchMu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(v.ctx, cancel)
+
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+		defer close(done)
+		defer stop()
+		defer cancel()
+
+		v.prefetch(ctx, domains)
+	}()
+}
+
+// PrefetchComplete returns a channel that is closed once the most recent
+// PrefetchIPReputation call has finished. Without one, the channel is already closed.
+func (v *EnhancedValidator) PrefetchComplete() <-chan struct{} {
+	v.prefetchMu.Lock()
+	defer v.prefetchMu.Unlock()
+	if v.prefetchDone == nil {
+		return closedChan
+	}
+	return v.prefetchDone
+}
+
+// prefetch warms the IP reputation cache for the mail servers of domains.
+func (v *EnhancedValidator) prefetch(ctx context.Context, domains []string) {
+	basic := v.basicValidator
+	seenDomains := make(map[string]bool, len(domains))
+	var seenIPs sync.Map // IPs shared by several domains are only looked up once
+
+	var g errgroup.Group
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+		if domain == "" || seenDomains[domain] {
+			continue
+		}
+		seenDomains[domain] = true
+
+		if err := basic.limiter.acquire(ctx); err != nil {
+			break
+		}
+		g.Go(func() error {
+			defer basic.limiter.release()
+
+			ips, err := getMailServerIPs(ctx, basic.resolver, domain, v.mailServerIPs)
+			if err != nil {
+				slog.Debug("skipping IP reputation prefetch", slog.String("domain", domain), slog.Any("error", err))
+				return nil
+			}
+			for _, ip := range ips {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if _, loaded := seenIPs.LoadOrStore(ip.IP.String(), true); !loaded {
+					v.checkIPReputationWithCache(ctx, ip.IP.String())
+				}
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	slog.Info("IP reputation prefetch finished", slog.Int("domains", len(seenDomains)), slog.Bool("cancelled", ctx.Err() != nil))
+}
@@ -0,0 +1,43 @@
+// File: shared/profanity.go
+package shared
+
+import (
+	"strings"
+)
+
+// SubStatusOffensiveLocalPart marks addresses rejected by the profanity check.
+const SubStatusOffensiveLocalPart = "OFFENSIVE_LOCAL_PART"
+
+// leetReplacer maps common l33t-speak substitutions back to letters
+var leetReplacer = strings.NewReplacer(
+	"@", "a", "4", "a",
+	"3", "e",
+	"1", "i", "!", "i",
+	"0", "o",
+	"5", "s", "$", "s",
+	"7", "t",
+)
+
+// localPartSeparators are stripped so words split up with them still match
+var localPartSeparators = strings.NewReplacer(".", "", "_", "", "-", "", "+", "")
+
+// ContainsProfanity reports whether localPart contains any word of wordlist,
+// ignoring case, l33t-speak substitutions (e.g. "3" for "e", "0" for "o")
+// and separating dots, underscores, hyphens and plus signs. Words match
+// anywhere in the local part, so short words can flag innocent addresses;
+// keep the wordlist specific.
+func ContainsProfanity(localPart string, wordlist []string) bool {
+	normalized := leetReplacer.Replace(strings.ToLower(localPart))
+	joined := localPartSeparators.Replace(normalized)
+
+	for _, word := range wordlist {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		if strings.Contains(normalized, word) || strings.Contains(joined, word) {
+			return true
+		}
+	}
+	return false
+}
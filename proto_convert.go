@@ -0,0 +1,156 @@
+// File: shared/proto_convert.go
+package shared
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	validatorpb "github.com/nibbabob/azlo-validator-shared/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statusToProto maps Status values to their protobuf equivalents
+var statusToProto = map[Status]validatorpb.StatusProto{
+	StatusValid:   validatorpb.StatusProto_STATUS_PROTO_VALID,
+	StatusInvalid: validatorpb.StatusProto_STATUS_PROTO_INVALID,
+	StatusRisky:   validatorpb.StatusProto_STATUS_PROTO_RISKY,
+	StatusUnknown: validatorpb.StatusProto_STATUS_PROTO_UNKNOWN,
+	StatusError:   validatorpb.StatusProto_STATUS_PROTO_ERROR,
+}
+
+// ToProto converts the result to its protobuf form. Every field round-trips
+// through ResultFromProto except for three limits of the wire format:
+//
+//   - Metadata is carried as a google.protobuf.Struct, so values are converted
+//     through their JSON representation: numbers come back as float64,
+//     time.Time and other values marshaled as JSON strings come back as
+//     strings, and structs come back as map[string]interface{}.
+//   - Repeated fields can't tell nil from empty, so empty Tags come back as nil.
+//   - Timestamp keeps its instant but comes back in UTC, without a monotonic
+//     reading.
+func (r *Result) ToProto() *validatorpb.ResultProto {
+	p := &validatorpb.ResultProto{
+		JobId:              r.JobID,
+		Email:              r.Email,
+		Reason:             r.Reason,
+		SubStatus:          r.SubStatus,
+		Tags:               r.Tags,
+		Score:              int32(r.Score),
+		WasGreylisted:      r.WasGreylisted,
+		GraylistRetryCount: int32(r.GraylistRetryCount),
+		Timestamp:          timeToProto(r.Timestamp),
+	}
+
+	// Statuses without an enum equivalent are carried verbatim
+	if status, ok := statusToProto[Status(r.Status)]; ok {
+		p.Status = status
+	} else {
+		p.StatusText = r.Status
+	}
+
+	if r.Duration != 0 {
+		p.Duration = durationpb.New(r.Duration)
+	}
+
+	if r.Metadata != nil {
+		metadata, err := metadataToStruct(r.Metadata)
+		if err != nil {
+			log.Printf("Failed to convert metadata for %s to protobuf: %v", AnonymizeEmail(r.Email, AnonymizeMask), err)
+		} else {
+			p.Metadata = metadata
+		}
+	}
+
+	return p
+}
+
+// ResultFromProto converts a protobuf result back to a Result. See ToProto
+// for how Metadata, Tags and Timestamp are affected by the round trip.
+func ResultFromProto(p *validatorpb.ResultProto) *Result {
+	r := &Result{
+		JobID:              p.GetJobId(),
+		Email:              p.GetEmail(),
+		Status:             p.GetStatusText(),
+		Reason:             p.GetReason(),
+		SubStatus:          p.GetSubStatus(),
+		Tags:               p.GetTags(),
+		Score:              int(p.GetScore()),
+		WasGreylisted:      p.GetWasGreylisted(),
+		GraylistRetryCount: int(p.GetGraylistRetryCount()),
+		Timestamp:          timeFromProto(p.GetTimestamp()),
+	}
+
+	for status, protoStatus := range statusToProto {
+		if p.GetStatus() == protoStatus {
+			r.Status = string(status)
+			break
+		}
+	}
+
+	if p.GetDuration() != nil {
+		r.Duration = p.GetDuration().AsDuration()
+	}
+
+	if p.GetMetadata() != nil {
+		r.Metadata = p.GetMetadata().AsMap()
+	}
+
+	return r
+}
+
+// ToProto converts the job to its protobuf form. Times keep their instant
+// but come back from ValidationJobFromProto in UTC, without a monotonic reading.
+func (j *ValidationJob) ToProto() *validatorpb.ValidationJobProto {
+	return &validatorpb.ValidationJobProto{
+		JobId:     j.JobID,
+		Email:     j.Email,
+		Timestamp: timeToProto(j.Timestamp),
+		NotBefore: timeToProto(j.NotBefore),
+		Attempts:  int32(j.Attempts),
+	}
+}
+
+// ValidationJobFromProto converts a protobuf job back to a ValidationJob.
+func ValidationJobFromProto(p *validatorpb.ValidationJobProto) *ValidationJob {
+	return &ValidationJob{
+		JobID:     p.GetJobId(),
+		Email:     p.GetEmail(),
+		Timestamp: timeFromProto(p.GetTimestamp()),
+		NotBefore: timeFromProto(p.GetNotBefore()),
+		Attempts:  int(p.GetAttempts()),
+	}
+}
+
+// timeToProto converts a time to a Timestamp, mapping the zero time to nil
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timeFromProto converts a Timestamp to a time, mapping nil to the zero time
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// metadataToStruct converts arbitrary metadata to a Struct via its JSON form
+func metadataToStruct(metadata map[string]interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
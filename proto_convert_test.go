@@ -0,0 +1,110 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	validatorpb "github.com/nibbabob/azlo-validator-shared/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// overTheWire marshals and unmarshals m, as a gRPC call would.
+func overTheWire[M proto.Message](t *testing.T, m M, out M) M {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	if err := proto.Unmarshal(data, out); err != nil {
+		t.Fatalf("proto.Unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestResultProtoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *Result
+	}{
+		{"empty", &Result{}},
+		{"fully populated", &Result{
+			JobID:  "job-1",
+			Email:  "john@example.com",
+			Status: StatusValid.String(),
+			Reason: "email appears valid",
+			Metadata: map[string]interface{}{
+				"is_free_provider": true,
+				"email_pattern":    "person_name",
+				"mx_count":         float64(2),
+				"mx_hosts":         []interface{}{"mx1.example.com", "mx2.example.com"},
+				"smtp":             map[string]interface{}{"code": float64(250)},
+			},
+			SubStatus:          SubStatusFreeTierProvider,
+			Tags:               []string{TagFreeProvider, "b2c"},
+			Score:              87,
+			Duration:           1500 * time.Millisecond,
+			WasGreylisted:      true,
+			GraylistRetryCount: 2,
+			Timestamp:          time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC),
+		}},
+		{"status without enum value", &Result{Email: "john@example.com", Status: StatusCatchAll.String()}},
+		{"unknown status", &Result{Email: "john@example.com", Status: "quarantined"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResultFromProto(overTheWire(t, tt.result.ToProto(), &validatorpb.ResultProto{}))
+			if !reflect.DeepEqual(got, tt.result) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.result)
+			}
+		})
+	}
+}
+
+// TestResultProtoRoundTripLossy pins down the documented conversions of ToProto.
+func TestResultProtoRoundTripLossy(t *testing.T) {
+	checkedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &Result{
+		Email:    "john@example.com",
+		Status:   StatusValid.String(),
+		Tags:     []string{},
+		Metadata: map[string]interface{}{"mx_count": 2, "checked_at": checkedAt},
+	}
+
+	got := ResultFromProto(overTheWire(t, r.ToProto(), &validatorpb.ResultProto{}))
+	if got.Tags != nil {
+		t.Errorf("Tags = %#v, want nil", got.Tags)
+	}
+	if got.Metadata["mx_count"] != float64(2) {
+		t.Errorf("Metadata[mx_count] = %#v, want float64(2)", got.Metadata["mx_count"])
+	}
+	if got.Metadata["checked_at"] != checkedAt.Format(time.RFC3339Nano) {
+		t.Errorf("Metadata[checked_at] = %#v, want %q", got.Metadata["checked_at"], checkedAt.Format(time.RFC3339Nano))
+	}
+}
+
+func TestValidationJobProtoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		job  *ValidationJob
+	}{
+		{"empty", &ValidationJob{}},
+		{"fully populated", &ValidationJob{
+			JobID:     "job-1",
+			Email:     "john@example.com",
+			Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC),
+			NotBefore: time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC),
+			Attempts:  3,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidationJobFromProto(overTheWire(t, tt.job.ToProto(), &validatorpb.ValidationJobProto{}))
+			if !reflect.DeepEqual(got, tt.job) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.job)
+			}
+		})
+	}
+}
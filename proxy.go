@@ -0,0 +1,108 @@
+// File: shared/proxy.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+)
+
+// ProxyConfig describes a SOCKS5 proxy used for outbound connections.
+type ProxyConfig struct {
+	URL      string `json:"url" yaml:"url"` // e.g. "socks5://proxy.internal:1080"
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// ErrProxyUnreachable is returned when a connection to the configured proxy itself fails.
+type ErrProxyUnreachable struct {
+	ProxyAddr string
+	Err       error
+}
+
+// Error implements the error interface.
+func (e *ErrProxyUnreachable) Error() string {
+	return fmt.Sprintf("proxy %s unreachable: %v", e.ProxyAddr, e.Err)
+}
+
+// Unwrap returns the underlying connection error.
+func (e *ErrProxyUnreachable) Unwrap() error {
+	return e.Err
+}
+
+// WithProxy routes AbuseIPDB API requests through a SOCKS5 proxy. The HTTP
+// client and transport are copied before the proxy is set, so a client passed
+// in with WithHTTPDoer isn't affected; it must be an *http.Client.
+func WithProxy(cfg ProxyConfig) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		transport, err := c.transport("WithProxy")
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		transport.Proxy = nil
+		transport.DialContext = proxyDialContext(cfg)
+	}
+}
+
+// proxyDialContext returns a dial function that connects through the proxy
+// described by cfg. An invalid configuration is reported on every dial.
+func proxyDialContext(cfg ProxyConfig) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	proxyURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		err = fmt.Errorf("invalid proxy URL: %w", err)
+		return func(context.Context, string, string) (net.Conn, error) { return nil, err }
+	}
+	if cfg.Username != "" {
+		proxyURL.User = url.UserPassword(cfg.Username, cfg.Password)
+	}
+
+	dialer, err := proxy.FromURL(proxyURL, proxyForwardDialer{proxyAddr: proxyURL.Host})
+	if err != nil {
+		err = fmt.Errorf("unsupported proxy %q: %w", proxyURL.Redacted(), err)
+		return func(context.Context, string, string) (net.Conn, error) { return nil, err }
+	}
+
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		err = fmt.Errorf("proxy %q does not support dialing with a context", proxyURL.Redacted())
+		return func(context.Context, string, string) (net.Conn, error) { return nil, err }
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := contextDialer.DialContext(ctx, network, addr)
+		if err != nil {
+			// Surface proxy failures directly rather than wrapped in the SOCKS error
+			var proxyErr *ErrProxyUnreachable
+			if errors.As(err, &proxyErr) {
+				return nil, proxyErr
+			}
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// proxyForwardDialer connects to the proxy server, tagging failures as ErrProxyUnreachable.
+type proxyForwardDialer struct {
+	proxyAddr string
+}
+
+// Dial connects to the proxy server.
+func (d proxyForwardDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext connects to the proxy server.
+func (d proxyForwardDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, &ErrProxyUnreachable{ProxyAddr: d.proxyAddr, Err: err}
+	}
+	return conn, nil
+}
@@ -0,0 +1,203 @@
+// File: shared/rate_limited.go
+package shared
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// TenantIDKey is the context key holding the tenant a validation is performed for.
+const TenantIDKey contextKey = "tenant_id"
+
+// WithTenantID returns a copy of ctx carrying the given tenant ID.
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, TenantIDKey, id)
+}
+
+// ExtractTenantID returns the tenant ID stored in ctx, or "" if there is none.
+func ExtractTenantID(ctx context.Context) string {
+	id, _ := ctx.Value(TenantIDKey).(string)
+	return id
+}
+
+// RateLimiterProvider returns the rate limiter for a tenant. Implementations
+// must return the same limiter for repeated calls with the same tenant.
+type RateLimiterProvider interface {
+	GetLimiter(tenantID string) *rate.Limiter
+}
+
+// RateLimitedValidator wraps an EnhancedValidator with per-tenant rate limits,
+// so one tenant's bulk upload can't exhaust the shared AbuseIPDB quota.
+type RateLimitedValidator struct {
+	validator *EnhancedValidator
+	limiters  RateLimiterProvider
+	timeout   time.Duration
+}
+
+// NewRateLimitedValidator creates a validator that waits up to timeout for a
+// token from the tenant's limiter before each validation.
+func NewRateLimitedValidator(v *EnhancedValidator, limiters RateLimiterProvider, timeout time.Duration) *RateLimitedValidator {
+	return &RateLimitedValidator{
+		validator: v,
+		limiters:  limiters,
+		timeout:   timeout,
+	}
+}
+
+// ValidateEmailWithReputation validates email for the tenant in ctx (see
+// WithTenantID). If no token is available within the timeout, the result has
+// status "error" and reason "rate limit exceeded".
+func (v *RateLimitedValidator) ValidateEmailWithReputation(ctx context.Context, email string) *Result {
+	limiter := v.limiters.GetLimiter(ExtractTenantID(ctx))
+
+	waitCtx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	if err := limiter.Wait(waitCtx); err != nil {
+		return &Result{
+			Email:    email,
+			Status:   StatusError.String(),
+			Reason:   "rate limit exceeded",
+			Metadata: make(map[string]interface{}),
+		}
+	}
+
+	return v.validator.ValidateEmailWithReputationContext(ctx, email)
+}
+
+// InMemoryRateLimiterProvider gives every tenant its own limiter with the same limits.
+type InMemoryRateLimiterProvider struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	limit    rate.Limit
+	burst    int
+}
+
+// NewInMemoryRateLimiterProvider creates a provider allowing each tenant rps
+// validations per second with the given burst.
+func NewInMemoryRateLimiterProvider(rps float64, burst int) *InMemoryRateLimiterProvider {
+	return &InMemoryRateLimiterProvider{
+		limiters: make(map[string]*rate.Limiter),
+		limit:    rate.Limit(rps),
+		burst:    burst,
+	}
+}
+
+// GetLimiter returns the tenant's limiter, creating it on first use.
+func (p *InMemoryRateLimiterProvider) GetLimiter(tenantID string) *rate.Limiter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	limiter, exists := p.limiters[tenantID]
+	if !exists {
+		limiter = rate.NewLimiter(p.limit, p.burst)
+		p.limiters[tenantID] = limiter
+	}
+	return limiter
+}
+
+// RedisHashClient is the subset of a Redis client used by RedisRateLimiterProvider.
+// Adapt your client of choice, e.g. for go-redis:
+//
+//	func (a adapter) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+//		return a.client.HGetAll(ctx, key).Result()
+//	}
+type RedisHashClient interface {
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+}
+
+// RedisRateLimiterProvider reads per-tenant limits from Redis so they can be
+// managed centrally. Each tenant's limits are stored in the hash
+// "<prefix><tenantID>" with fields "rps" and "burst"; tenants without a hash
+// use the default limits. Limits are re-read every refresh interval and
+// applied to the tenant's existing limiter.
+type RedisRateLimiterProvider struct {
+	client       RedisHashClient
+	keyPrefix    string
+	defaultLimit rate.Limit
+	defaultBurst int
+	refresh      time.Duration
+
+	mu       sync.Mutex
+	limiters map[string]*redisTenantLimiter
+}
+
+// redisTenantLimiter is a tenant's limiter and when its limits were last read.
+type redisTenantLimiter struct {
+	limiter  *rate.Limiter
+	loadedAt time.Time
+}
+
+// NewRedisRateLimiterProvider creates a provider reading limits from Redis
+// hashes prefixed with keyPrefix, falling back to defaultRPS and defaultBurst.
+func NewRedisRateLimiterProvider(client RedisHashClient, keyPrefix string, defaultRPS float64, defaultBurst int, refresh time.Duration) *RedisRateLimiterProvider {
+	return &RedisRateLimiterProvider{
+		client:       client,
+		keyPrefix:    keyPrefix,
+		defaultLimit: rate.Limit(defaultRPS),
+		defaultBurst: defaultBurst,
+		refresh:      refresh,
+		limiters:     make(map[string]*redisTenantLimiter),
+	}
+}
+
+// GetLimiter returns the tenant's limiter, refreshing its limits from Redis when stale.
+func (p *RedisRateLimiterProvider) GetLimiter(tenantID string) *rate.Limiter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, exists := p.limiters[tenantID]
+	if !exists {
+		entry = &redisTenantLimiter{limiter: rate.NewLimiter(p.defaultLimit, p.defaultBurst)}
+		p.limiters[tenantID] = entry
+	}
+
+	if time.Since(entry.loadedAt) >= p.refresh {
+		limit, burst, err := p.loadLimits(tenantID)
+		if err != nil {
+			// Keep the current limits; Redis outages shouldn't block validation
+			log.Printf("Failed to load rate limits for tenant %s: %v", tenantID, err)
+		} else {
+			entry.limiter.SetLimit(limit)
+			entry.limiter.SetBurst(burst)
+		}
+		entry.loadedAt = time.Now()
+	}
+
+	return entry.limiter
+}
+
+// loadLimits reads the tenant's limits from Redis.
+func (p *RedisRateLimiterProvider) loadLimits(tenantID string) (rate.Limit, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	fields, err := p.client.HGetAll(ctx, p.keyPrefix+tenantID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	limit, burst := p.defaultLimit, p.defaultBurst
+	if rps, ok := fields["rps"]; ok {
+		parsed, err := strconv.ParseFloat(rps, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid rps %q: %w", rps, err)
+		}
+		limit = rate.Limit(parsed)
+	}
+	if b, ok := fields["burst"]; ok {
+		parsed, err := strconv.Atoi(b)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid burst %q: %w", b, err)
+		}
+		burst = parsed
+	}
+
+	return limit, burst, nil
+}
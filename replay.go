@@ -0,0 +1,239 @@
+// File: shared/replay.go
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// replaySMTPTimeout bounds each replayed SMTP conversation
+const replaySMTPTimeout = 10 * time.Second
+
+// ReplayValidation re-runs the validation captured in events, e.g. from
+// DumpReplayLog, answering every DNS lookup, SMTP conversation and HTTP
+// request from the recording instead of the network. The validation runs
+// with DefaultValidatorConfig; SMTP probing is enabled if the recording
+// contains SMTP traffic and IP reputation is checked if it contains HTTP
+// requests. Lookups missing from the recording fail as "not found".
+func ReplayValidation(events []RecordedEvent) *Result {
+	email := ""
+	var hasSMTP, hasHTTP bool
+	for _, e := range events {
+		switch e.Type {
+		case EventValidation:
+			email = e.Input
+		case EventSMTPDial:
+			hasSMTP = true
+		case EventHTTP:
+			hasHTTP = true
+		}
+	}
+	if email == "" {
+		return &Result{
+			Status: StatusError.String(),
+			Reason: "no validation event recorded",
+		}
+	}
+
+	factory := DefaultValidatorFactory()
+	factory.DNSResolver = newReplayResolver(events)
+	factory.SMTPDialer = newReplayDialer(events)
+	factory.HTTPClient = newReplayDoer(events)
+
+	cfg := DefaultValidatorConfig()
+	if hasSMTP {
+		cfg.SMTPTimeout = replaySMTPTimeout
+	}
+
+	if !hasHTTP {
+		return factory.Build(cfg).ValidateEmail(email)
+	}
+
+	v := factory.BuildEnhanced(cfg, "replay")
+	defer v.Shutdown()
+	return v.ValidateEmailWithReputation(email)
+}
+
+// replayQueues hands out recorded events per key in recording order. Once a
+// key's events are used up, its last event is repeated.
+type replayQueues struct {
+	mu     sync.Mutex
+	events map[string][]RecordedEvent
+}
+
+func (q *replayQueues) add(key string, e RecordedEvent) {
+	q.events[key] = append(q.events[key], e)
+}
+
+func (q *replayQueues) next(key string) (RecordedEvent, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	events := q.events[key]
+	if len(events) == 0 {
+		return RecordedEvent{}, false
+	}
+	if len(events) > 1 {
+		q.events[key] = events[1:]
+	}
+	return events[0], true
+}
+
+// replayResolver answers lookups from recorded DNS events.
+type replayResolver struct {
+	queues replayQueues
+}
+
+func newReplayResolver(events []RecordedEvent) *replayResolver {
+	r := &replayResolver{queues: replayQueues{events: make(map[string][]RecordedEvent)}}
+	for _, e := range events {
+		switch e.Type {
+		case EventDNSHost, EventDNSMX, EventDNSIP, EventDNSTXT:
+			r.queues.add(string(e.Type)+" "+e.Input, e)
+		}
+	}
+	return r
+}
+
+// lookup decodes the recorded answer for the query into out.
+func (r *replayResolver) lookup(typ RecordedEventType, input, name string, out interface{}) error {
+	e, ok := r.queues.next(string(typ) + " " + input)
+	if !ok {
+		return &net.DNSError{Err: "no recorded response", Name: name, IsNotFound: true}
+	}
+	if e.Err != "" {
+		dnsErr := &net.DNSError{}
+		if e.Output != "" && json.Unmarshal([]byte(e.Output), dnsErr) == nil {
+			return dnsErr
+		}
+		return errors.New(e.Err)
+	}
+	if e.Output == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(e.Output), out)
+}
+
+func (r *replayResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	var addrs []string
+	err := r.lookup(EventDNSHost, host, host, &addrs)
+	return addrs, err
+}
+
+func (r *replayResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	var mxs []*net.MX
+	err := r.lookup(EventDNSMX, name, name, &mxs)
+	return mxs, err
+}
+
+func (r *replayResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	var ips []net.IP
+	err := r.lookup(EventDNSIP, network+" "+host, host, &ips)
+	return ips, err
+}
+
+func (r *replayResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	var txts []string
+	err := r.lookup(EventDNSTXT, name, name, &txts)
+	return txts, err
+}
+
+// replayDialer serves recorded SMTP conversations over in-memory connections.
+type replayDialer struct {
+	dials        replayQueues
+	conversation map[int64][]RecordedEvent
+}
+
+func newReplayDialer(events []RecordedEvent) *replayDialer {
+	d := &replayDialer{
+		dials:        replayQueues{events: make(map[string][]RecordedEvent)},
+		conversation: make(map[int64][]RecordedEvent),
+	}
+	for _, e := range events {
+		switch e.Type {
+		case EventSMTPDial:
+			d.dials.add(e.Input, e)
+		case EventSMTPRead, EventSMTPWrite:
+			d.conversation[e.ConnID] = append(d.conversation[e.ConnID], e)
+		}
+	}
+	return d
+}
+
+func (d *replayDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	e, ok := d.dials.next(addr)
+	if !ok {
+		return nil, fmt.Errorf("no recorded connection to %s", addr)
+	}
+	if e.Err != "" {
+		return nil, errors.New(e.Err)
+	}
+
+	client, server := net.Pipe()
+	go serveRecordedConversation(server, d.conversation[e.ConnID])
+	return client, nil
+}
+
+// serveRecordedConversation plays the server side of a recorded SMTP
+// conversation: recorded reads are sent to the client and recorded writes
+// are consumed from it.
+func serveRecordedConversation(conn net.Conn, events []RecordedEvent) {
+	defer conn.Close()
+
+	for _, e := range events {
+		var err error
+		if e.Type == EventSMTPRead {
+			_, err = io.WriteString(conn, e.Output)
+		} else {
+			_, err = io.ReadFull(conn, make([]byte, len(e.Input)))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// replayDoer answers HTTP requests from recorded responses.
+type replayDoer struct {
+	queues replayQueues
+}
+
+func newReplayDoer(events []RecordedEvent) *replayDoer {
+	d := &replayDoer{queues: replayQueues{events: make(map[string][]RecordedEvent)}}
+	for _, e := range events {
+		if e.Type == EventHTTP {
+			d.queues.add(e.Input, e)
+		}
+	}
+	return d
+}
+
+func (d *replayDoer) Do(req *http.Request) (*http.Response, error) {
+	e, ok := d.queues.next(req.Method + " " + req.URL.String())
+	if !ok {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL)
+	}
+	if e.Err != "" && e.Output == "" {
+		return nil, errors.New(e.Err)
+	}
+
+	var recorded recordedHTTPResponse
+	if err := json.Unmarshal([]byte(e.Output), &recorded); err != nil {
+		return nil, fmt.Errorf("invalid recorded response: %w", err)
+	}
+	return &http.Response{
+		StatusCode: recorded.StatusCode,
+		Status:     fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		Header:     recorded.Header,
+		Body:       io.NopCloser(strings.NewReader(recorded.Body)),
+		Request:    req,
+	}, nil
+}
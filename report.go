@@ -0,0 +1,213 @@
+// File: shared/report.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// SyntaxCheckDetail holds the outcome of the format check.
+type SyntaxCheckDetail struct {
+	Valid     bool         `json:"valid"`
+	Error     *SyntaxError `json:"error,omitempty"`
+	LocalPart string       `json:"local_part,omitempty"`
+	Domain    string       `json:"domain,omitempty"`
+}
+
+// DNSCheckDetail holds the outcome of the DNS checks.
+type DNSCheckDetail struct {
+	MXRecords         []*net.MX `json:"mx_records,omitempty"`
+	ARecords          []net.IP  `json:"a_records,omitempty"` // A and AAAA records
+	MXError           string    `json:"mx_error,omitempty"`
+	AError            string    `json:"a_error,omitempty"`
+	SuspiciousPattern string    `json:"suspicious_pattern,omitempty"`
+}
+
+// DomainAgeResult describes when a domain was registered.
+type DomainAgeResult struct {
+	CreatedAt time.Time     `json:"created_at"`
+	Age       time.Duration `json:"age"`
+}
+
+// ValidationReport is a verbose counterpart to Result holding every
+// intermediate check, intended for debugging rather than production use.
+type ValidationReport struct {
+	Email     string        `json:"email"`
+	RequestID string        `json:"request_id"`
+	Duration  time.Duration `json:"duration"`
+	Score     int           `json:"score"` // ComputeScore with the validator's ScoringWeights
+
+	FormatCheck        SyntaxCheckDetail    `json:"format_check"`
+	DNSCheck           DNSCheckDetail       `json:"dns_check"`
+	SMTPCheck          SMTPResult           `json:"smtp_check"` // zero when SMTP probing is disabled
+	IPReputationChecks []IPReputationResult `json:"ip_reputation_checks,omitempty"`
+	DisposableCheck    bool                 `json:"disposable_check"`
+	RoleBasedCheck     bool                 `json:"role_based_check"`
+	DomainAgeCheck     *DomainAgeResult     `json:"domain_age_check,omitempty"` // nil when not checked
+	SPFCheck           *SPFResult           `json:"spf_check,omitempty"`        // nil if the lookup failed
+	DMARCCheck         *DMARCResult         `json:"dmarc_check,omitempty"`      // nil if the lookup failed
+	DKIMCheck          *DKIMResult          `json:"dkim_check,omitempty"`       // nil if the lookup failed
+	BotScore           int                  `json:"bot_score,omitempty"`        // zero unless EnableEntropyCheck is set
+}
+
+// GenerateReport runs every check for email and records the intermediate
+// results. Unlike ValidateEmail it doesn't stop at the first failure. An error
+// is returned only if ctx is done before the checks complete.
+func (v *Validator) GenerateReport(ctx context.Context, email string) (*ValidationReport, error) {
+	start := v.now()
+	ctx, requestID := ensureRequestID(ctx)
+	report := &ValidationReport{Email: email, RequestID: requestID}
+	defer func() {
+		report.Duration = v.now().Sub(start)
+		report.Score = ComputeScore(report, v.config.ScoringWeights)
+	}()
+
+	// Format
+	if err := CheckSyntax(email); err != nil {
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			syntaxErr = &SyntaxError{Detail: err.Error()}
+		}
+		report.FormatCheck.Error = syntaxErr
+		return report, nil
+	}
+
+	at := strings.LastIndex(email, "@")
+	localPart, domain := email[:at], email[at+1:]
+	report.FormatCheck = SyntaxCheckDetail{Valid: true, LocalPart: localPart, Domain: domain}
+	report.RoleBasedCheck = IsRoleBased(localPart, DefaultRoleBasedAccounts)
+	if v.config.EnableEntropyCheck {
+		report.BotScore = v.botScore(localPart)
+	}
+
+	// DNS
+	info, err := v.LookupDomainInfo(ctx, domain)
+	if err != nil {
+		return report, fmt.Errorf("report generation interrupted: %w", err)
+	}
+
+	switch {
+	case info.HasNullMX:
+		report.DNSCheck.MXError = ErrNullMX.Error()
+	case len(info.MXRecords) == 0:
+		report.DNSCheck.MXError = ErrNoMXRecords.Error()
+	}
+	report.DNSCheck.MXRecords = info.MXRecords
+	report.DNSCheck.ARecords = append(append([]net.IP(nil), info.ARecords...), info.AAAARecords...)
+	if len(report.DNSCheck.ARecords) == 0 {
+		report.DNSCheck.AError = ErrNoARecords.Error()
+	}
+	report.DNSCheck.SuspiciousPattern = suspiciousDomainPattern(domain)
+
+	// Disposable
+	isDisposable, err := v.disposable.IsDisposable(ctx, strings.ToLower(domain))
+	if err == nil {
+		report.DisposableCheck = isDisposable
+	}
+
+	// Email authentication
+	report.SPFCheck = info.SPF
+	report.DMARCCheck = info.DMARC
+	report.DKIMCheck = info.DKIM
+
+	// SMTP
+	if v.config.SMTPTimeout > 0 && len(info.MXRecords) > 0 {
+		report.SMTPCheck = checkSMTP(ctx, v.smtpDialer, email, info.MXRecords, v.config.SMTPTimeout, v.config.SMTP)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("report generation interrupted: %w", err)
+	}
+
+	return report, nil
+}
+
+// GenerateReport runs every basic check followed by IP reputation checks for
+// the domain's mail servers.
+func (v *EnhancedValidator) GenerateReport(ctx context.Context, email string) (*ValidationReport, error) {
+	report, err := v.basicValidator.GenerateReport(ctx, email)
+	if err != nil || !report.FormatCheck.Valid {
+		return report, err
+	}
+
+	ctx = WithRequestID(ctx, report.RequestID)
+	ips, err := getMailServerIPs(ctx, v.basicValidator.resolver, report.FormatCheck.Domain, v.mailServerIPs)
+	if err != nil {
+		return report, nil
+	}
+
+	for _, ip := range ips {
+		report.IPReputationChecks = append(report.IPReputationChecks, *v.checkIPReputationWithCache(ctx, ip.IP.String()))
+	}
+	report.Score = ComputeScore(report, v.basicValidator.config.ScoringWeights)
+
+	return report, nil
+}
+
+// ToResult summarizes the report as a Result, applying the same rules as ValidateEmail.
+func (r *ValidationReport) ToResult() *Result {
+	result := &Result{
+		Email:    r.Email,
+		Duration: r.Duration,
+		Score:    r.Score,
+		Metadata: map[string]interface{}{"request_id": r.RequestID},
+	}
+
+	switch {
+	case !r.FormatCheck.Valid:
+		result.Status = StatusInvalid.String()
+		result.Reason = "invalid email format"
+	case len(r.DNSCheck.ARecords) == 0:
+		result.Status = StatusInvalid.String()
+		result.Reason = "domain does not resolve"
+	case r.DNSCheck.MXError == ErrNullMX.Error():
+		result.Status = StatusInvalid.String()
+		result.Reason = "domain does not accept email (null MX)"
+	case len(r.DNSCheck.MXRecords) == 0:
+		result.Status = StatusInvalid.String()
+		result.Reason = "no MX records found"
+	case r.DNSCheck.SuspiciousPattern != "":
+		result.Status = StatusInvalid.String()
+		result.Reason = fmt.Sprintf("domain contains suspicious pattern: %s", r.DNSCheck.SuspiciousPattern)
+	case r.DisposableCheck:
+		result.Status = StatusInvalid.String()
+		result.Reason = "disposable email domain detected"
+		result.Metadata["is_disposable"] = true
+	case r.SMTPCheck.Status != "" && r.SMTPCheck.Status != StatusValid:
+		result.Status = r.SMTPCheck.Status.String()
+		result.Reason = r.SMTPCheck.Reason
+		result.WasGreylisted = r.SMTPCheck.Greylisted
+	case r.hasHighRiskIP():
+		result.Status = StatusSuspicious.String()
+		result.Reason = "mail server IP has poor reputation"
+	default:
+		result.Status = StatusValid.String()
+		result.Reason = "email appears valid"
+	}
+
+	if r.RoleBasedCheck {
+		result.Metadata["is_role_based"] = true
+	}
+	if r.SMTPCheck.Status != "" {
+		result.Metadata["smtp_code"] = r.SMTPCheck.Code
+	}
+	if r.BotScore > 0 {
+		result.Metadata["bot_score"] = r.BotScore
+	}
+
+	return result
+}
+
+// hasHighRiskIP applies the same reputation threshold as ValidateEmailWithReputation.
+func (r *ValidationReport) hasHighRiskIP() bool {
+	for _, ip := range r.IPReputationChecks {
+		if ComputeRiskLevel(&ip, DefaultRiskConfig()) >= RiskHigh {
+			return true
+		}
+	}
+	return false
+}
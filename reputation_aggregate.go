@@ -0,0 +1,101 @@
+// File: shared/reputation_aggregate.go
+package shared
+
+import (
+	"math"
+	"sort"
+)
+
+// AggregationMode selects how per-IP reputation is combined into a domain verdict.
+type AggregationMode string
+
+const (
+	// AggModeAny flags the domain if any mail server IP is high risk.
+	AggModeAny AggregationMode = "any"
+	// AggModeAll flags the domain only if every mail server IP is high risk.
+	AggModeAll AggregationMode = "all"
+	// AggModeMajority flags the domain if more than half of its IPs are high risk.
+	AggModeMajority AggregationMode = "majority"
+	// AggModeAverage flags the domain if the weighted average score is above the high risk threshold.
+	AggModeAverage AggregationMode = "average"
+)
+
+// DomainReputationScore aggregates the reputation of a domain's mail server IPs.
+// IPs whose lookup failed are left out.
+type DomainReputationScore struct {
+	IPCount         int     `json:"ip_count"`
+	HighRiskCount   int     `json:"high_risk_count"`
+	WeightedAverage float64 `json:"weighted_average"` // weighted by distinct reporters
+	WorstCase       int     `json:"worst_case"`
+	Median          float64 `json:"median"`
+}
+
+// AggregateDomainReputation combines per-IP reputation results using the
+// default risk settings. The weighted average gives each IP a weight of
+// 1 + log2(NumDistinctUsers+1), so widely reported IPs count more.
+func AggregateDomainReputation(ips []*IPReputationResult) DomainReputationScore {
+	return aggregateDomainReputation(ips, DefaultRiskConfig())
+}
+
+// aggregateDomainReputation combines per-IP results, grading each with cfg.
+func aggregateDomainReputation(ips []*IPReputationResult, cfg RiskConfig) DomainReputationScore {
+	var (
+		agg         DomainReputationScore
+		scores      []int
+		weightedSum float64
+		weightTotal float64
+	)
+
+	for _, ip := range ips {
+		if ip == nil || ip.Error != "" {
+			continue
+		}
+		agg.IPCount++
+		if ComputeRiskLevel(ip, cfg) >= RiskHigh {
+			agg.HighRiskCount++
+		}
+
+		score := ip.AbuseConfidenceScore
+		scores = append(scores, score)
+		agg.WorstCase = max(agg.WorstCase, score)
+
+		weight := 1 + math.Log2(float64(ip.NumDistinctUsers)+1)
+		weightedSum += weight * float64(score)
+		weightTotal += weight
+	}
+
+	if agg.IPCount == 0 {
+		return agg
+	}
+
+	agg.WeightedAverage = weightedSum / weightTotal
+
+	sort.Ints(scores)
+	mid := len(scores) / 2
+	if len(scores)%2 == 0 {
+		agg.Median = float64(scores[mid-1]+scores[mid]) / 2
+	} else {
+		agg.Median = float64(scores[mid])
+	}
+
+	return agg
+}
+
+// IsHighRisk reports whether the domain counts as high risk under mode.
+// An unknown or empty mode behaves like AggModeAny.
+func (s DomainReputationScore) IsHighRisk(mode AggregationMode) bool {
+	if s.IPCount == 0 {
+		return false
+	}
+
+	switch mode {
+	case AggModeAll:
+		return s.HighRiskCount == s.IPCount
+	case AggModeMajority:
+		return s.HighRiskCount*2 > s.IPCount
+	case AggModeAverage:
+		return s.WeightedAverage > riskHighScore
+	default:
+		return s.HighRiskCount > 0
+	}
+}
@@ -0,0 +1,66 @@
+// File: shared/request_id.go
+package shared
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// contextKey is the type of context keys defined by this package.
+type contextKey string
+
+// RequestIDKey is the context key holding the request ID that correlates the
+// DNS, SMTP and IP reputation operations of a single validation.
+const RequestIDKey contextKey = "request_id"
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, id)
+}
+
+// ExtractRequestID returns the request ID stored in ctx, or "" if there is none.
+// Validation entry points generate a UUID when the caller didn't supply one.
+func ExtractRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDKey).(string)
+	return id
+}
+
+// ensureRequestID returns ctx and its request ID, adding a new UUID if ctx has none.
+func ensureRequestID(ctx context.Context) (context.Context, string) {
+	if id := ExtractRequestID(ctx); id != "" {
+		return ctx, id
+	}
+
+	id := newRequestID()
+	return WithRequestID(ctx, id), id
+}
+
+// newRequestID generates a random (version 4) UUID.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// withRequestID prefixes err with the request ID from ctx, keeping it matchable with errors.Is.
+func withRequestID(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if id := ExtractRequestID(ctx); id != "" {
+		return fmt.Errorf("[request_id=%s] %w", id, err)
+	}
+	return err
+}
+
+// requestIDPrefix returns a log prefix for the request ID in ctx.
+func requestIDPrefix(ctx context.Context) string {
+	if id := ExtractRequestID(ctx); id != "" {
+		return fmt.Sprintf("[request_id=%s] ", id)
+	}
+	return ""
+}
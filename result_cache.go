@@ -0,0 +1,141 @@
+// File: shared/result_cache.go
+package shared
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResultCache stores validation results keyed by email address.
+type ResultCache interface {
+	Get(email string) (*Result, bool)
+	Set(email string, result *Result)
+	Delete(email string)
+}
+
+// ResultCacheConfig selects how long results are cached based on their status.
+type ResultCacheConfig struct {
+	ValidTTL   time.Duration `json:"valid_ttl" yaml:"valid_ttl"`
+	InvalidTTL time.Duration `json:"invalid_ttl" yaml:"invalid_ttl"`
+	RiskyTTL   time.Duration `json:"risky_ttl" yaml:"risky_ttl"`
+	ErrorTTL   time.Duration `json:"error_ttl" yaml:"error_ttl"`
+	DefaultTTL time.Duration `json:"default_ttl" yaml:"default_ttl"` // used for unrecognized statuses
+}
+
+// DefaultResultCacheConfig caches valid results longest and error results shortest,
+// since errors are often transient.
+func DefaultResultCacheConfig() ResultCacheConfig {
+	return ResultCacheConfig{
+		ValidTTL:   24 * time.Hour,
+		InvalidTTL: 6 * time.Hour,
+		RiskyTTL:   time.Hour,
+		ErrorTTL:   5 * time.Minute,
+		DefaultTTL: time.Hour,
+	}
+}
+
+// TTLFor returns the TTL for a result with the given status.
+func (c ResultCacheConfig) TTLFor(status string) time.Duration {
+	switch Status(status) {
+	case StatusValid:
+		return c.ValidTTL
+	case StatusInvalid:
+		return c.InvalidTTL
+	case StatusRisky:
+		return c.RiskyTTL
+	case StatusError:
+		return c.ErrorTTL
+	default:
+		return c.DefaultTTL
+	}
+}
+
+// resultCacheEntry is a cached result with its expiry time.
+type resultCacheEntry struct {
+	key       string // as passed to Set, before normalization
+	result    *Result
+	expiresAt time.Time
+}
+
+// MemoryResultCache is an in-memory ResultCache with per-status TTLs.
+type MemoryResultCache struct {
+	mu          sync.RWMutex
+	entries     map[string]resultCacheEntry
+	CacheConfig ResultCacheConfig
+}
+
+// NewMemoryResultCache creates an in-memory result cache.
+func NewMemoryResultCache(cfg ResultCacheConfig) *MemoryResultCache {
+	return &MemoryResultCache{
+		entries:     make(map[string]resultCacheEntry),
+		CacheConfig: cfg,
+	}
+}
+
+// Get returns the cached result for email if it hasn't expired.
+func (c *MemoryResultCache) Get(email string) (*Result, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, exists := c.entries[resultCacheKey(email)]
+	if !exists || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.result, true
+}
+
+// Set caches the result using the TTL for its status. Results with a zero TTL are not cached.
+func (c *MemoryResultCache) Set(email string, result *Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.CacheConfig.TTLFor(result.Status)
+	if ttl <= 0 {
+		return
+	}
+
+	c.entries[resultCacheKey(email)] = resultCacheEntry{
+		key:       email,
+		result:    result,
+		expiresAt: time.Now().Add(ttl),
+	}
+}
+
+// Delete removes the cached result for email.
+func (c *MemoryResultCache) Delete(email string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, resultCacheKey(email))
+}
+
+// ExpiringBefore returns the cached results that are still fresh but expire
+// before deadline, keyed as they were passed to Set.
+func (c *MemoryResultCache) ExpiringBefore(deadline time.Time) map[string]*Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	expiring := make(map[string]*Result)
+	for _, entry := range c.entries {
+		if !now.After(entry.expiresAt) && entry.expiresAt.Before(deadline) {
+			expiring[entry.key] = entry.result
+		}
+	}
+	return expiring
+}
+
+// SetCacheConfig replaces the TTL configuration. Existing entries keep their expiry.
+func (c *MemoryResultCache) SetCacheConfig(cfg ResultCacheConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.CacheConfig = cfg
+}
+
+// resultCacheKey normalizes an email address for use as a cache key.
+func resultCacheKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,95 @@
+// File: shared/result_cache_renew.go
+package shared
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentRenewals bounds the re-validations run by one AutoRenewCache scan.
+const maxConcurrentRenewals = 10
+
+// RenewableResultCache is a ResultCache that can list entries about to
+// expire, as needed by AutoRenewCache. MemoryResultCache implements it.
+type RenewableResultCache interface {
+	ResultCache
+	// ExpiringBefore returns the fresh results expiring before deadline, keyed as passed to Set.
+	ExpiringBefore(deadline time.Time) map[string]*Result
+}
+
+// AutoRenewCache starts a goroutine that re-validates cached results of
+// validator expiring within renewBefore and replaces them in the cache, so
+// hot addresses don't periodically miss the cache. Renewed results have
+// Metadata["cache_renewed"] set to true. A renewal ending in an error status
+// leaves the old entry to expire normally, since errors are often transient.
+// Renewals aren't audited or counted in metrics as validations.
+//
+// The cache is scanned every renewBefore/2 until ctx is cancelled or the
+// validator is shut down. Nothing is started if renewBefore isn't positive or
+// the validator's result cache doesn't implement RenewableResultCache.
+func AutoRenewCache(ctx context.Context, validator *EnhancedValidator, renewBefore time.Duration) {
+	cache, ok := validator.resultCache.(RenewableResultCache)
+	if !ok {
+		log.Printf("Result cache auto-renewal not started: cache of type %T can't list expiring entries", validator.resultCache)
+		return
+	}
+	if renewBefore <= 0 {
+		return
+	}
+
+	validator.wg.Add(1)
+	go func() {
+		defer validator.wg.Done()
+
+		ticker := time.NewTicker(renewBefore / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-validator.ctx.Done():
+				return
+			case <-ticker.C:
+				validator.renewExpiring(ctx, cache, renewBefore)
+			}
+		}
+	}()
+}
+
+// renewExpiring re-validates the cached results expiring within renewBefore.
+func (v *EnhancedValidator) renewExpiring(ctx context.Context, cache RenewableResultCache, renewBefore time.Duration) {
+	expiring := cache.ExpiringBefore(time.Now().Add(renewBefore))
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentRenewals)
+	for key, cached := range expiring {
+		tenant, ok := tenantFromCacheKey(key, cached.Email)
+		if !ok {
+			continue
+		}
+		g.Go(func() error {
+			result := v.validateWithReputation(WithTenantID(ctx, tenant), cached.Email)
+			if Status(result.Status) == StatusError || ctx.Err() != nil {
+				return nil
+			}
+			result.Metadata["cache_renewed"] = true
+			cache.Set(key, result)
+			return nil
+		})
+	}
+	g.Wait()
+}
+
+// tenantFromCacheKey reverses tenantCacheKey for a key known to hold email.
+func tenantFromCacheKey(key, email string) (string, bool) {
+	if key == email {
+		return "", true
+	}
+	tenant, found := strings.CutSuffix(key, "/"+email)
+	return tenant, found && tenant != ""
+}
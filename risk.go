@@ -0,0 +1,123 @@
+// File: shared/risk.go
+package shared
+
+import (
+	"math"
+	"time"
+)
+
+// RiskLevel grades how likely an IP is to be involved in abuse.
+type RiskLevel int
+
+const (
+	RiskLow RiskLevel = iota
+	RiskMedium
+	RiskHigh
+	RiskCritical
+)
+
+// String returns the lower-case name of the risk level.
+func (l RiskLevel) String() string {
+	switch l {
+	case RiskMedium:
+		return "medium"
+	case RiskHigh:
+		return "high"
+	case RiskCritical:
+		return "critical"
+	default:
+		return "low"
+	}
+}
+
+// Abuse confidence score thresholds for each risk level
+const (
+	riskMediumScore   = 25
+	riskHighScore     = 75 // exclusive, matching the reputation check
+	riskCriticalScore = 90 // exclusive
+	riskHighReports   = 50 // exclusive
+)
+
+// defaultDistinctUsersWeight scales the distinct reporter multiplier.
+const defaultDistinctUsersWeight = 0.3
+
+// defaultRecentReportThreshold is how recent a report must be to raise the risk level.
+const defaultRecentReportThreshold = 7 * 24 * time.Hour
+
+// RiskConfig holds the settings used by ComputeRiskLevel.
+type RiskConfig struct {
+	// RecentReportThreshold is how recently an IP must have been reported for
+	// the report to raise its risk level. Zero uses 7 days.
+	RecentReportThreshold time.Duration `json:"recent_report_threshold" yaml:"recent_report_threshold"`
+
+	// DistinctUsersWeight scales how much the number of independent reporters
+	// amplifies the abuse score. Zero ignores the reporter count.
+	DistinctUsersWeight float64 `json:"distinct_users_weight" yaml:"distinct_users_weight"`
+}
+
+// DefaultRiskConfig returns the risk settings used by DefaultValidatorConfig.
+func DefaultRiskConfig() RiskConfig {
+	return RiskConfig{
+		RecentReportThreshold: defaultRecentReportThreshold,
+		DistinctUsersWeight:   defaultDistinctUsersWeight,
+	}
+}
+
+// IsRecentlyReported reports whether the IP was last reported less than threshold ago.
+func (r *IPReputationResult) IsRecentlyReported(threshold time.Duration) bool {
+	return !r.LastReportedAt.IsZero() && time.Since(r.LastReportedAt) < threshold
+}
+
+// ComputeRiskLevel grades an IP reputation result. The effective score (see
+// EffectiveAbuseScore) sets the base level (above 90 critical, above 75 high,
+// 25 and up medium);
+// more than 50 reports is at least high. An IP reported within
+// cfg.RecentReportThreshold is raised one level, unless its score is low.
+func ComputeRiskLevel(result *IPReputationResult, cfg RiskConfig) RiskLevel {
+	if result == nil || result.IsWhitelisted {
+		return RiskLow
+	}
+
+	score := EffectiveAbuseScore(result, cfg.DistinctUsersWeight)
+	level := RiskLow
+	switch {
+	case score > riskCriticalScore:
+		level = RiskCritical
+	case score > riskHighScore:
+		level = RiskHigh
+	case score >= riskMediumScore:
+		level = RiskMedium
+	}
+	if result.TotalReports > riskHighReports {
+		level = max(level, RiskHigh)
+	}
+
+	threshold := cfg.RecentReportThreshold
+	if threshold <= 0 {
+		threshold = defaultRecentReportThreshold
+	}
+	if level >= RiskMedium && result.IsRecentlyReported(threshold) {
+		level = min(level+1, RiskCritical)
+	}
+
+	return level
+}
+
+// EffectiveAbuseScore amplifies the abuse confidence score by the number of
+// distinct reporters, since many independent reporters are a stronger signal
+// than one reporter filing many reports:
+//
+//	score * (1 + weight*(log2(NumDistinctUsers+1) - 1))
+//
+// A single reporter leaves the score unchanged; 50 reporters with weight 0.3
+// multiply it by about 2.4. The result is capped at 100. Results without a
+// reporter count keep their score.
+func EffectiveAbuseScore(result *IPReputationResult, weight float64) int {
+	score := result.AbuseConfidenceScore
+	if result.NumDistinctUsers < 1 || weight <= 0 {
+		return score
+	}
+
+	multiplier := 1 + weight*(math.Log2(float64(result.NumDistinctUsers)+1)-1)
+	return min(int(math.Round(float64(score)*multiplier)), 100)
+}
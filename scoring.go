@@ -0,0 +1,165 @@
+// File: shared/scoring.go
+package shared
+
+import (
+	"math"
+	"time"
+)
+
+// domainAgeFullScore is the domain age at which the domain age check scores fully.
+const domainAgeFullScore = 365 * 24 * time.Hour
+
+// ScoringWeights sets how much each check contributes to a deliverability score.
+// Weights are relative; they don't need to sum to 1.
+type ScoringWeights struct {
+	Format       float64 `json:"format" yaml:"format"`
+	DomainDNS    float64 `json:"domain_dns" yaml:"domain_dns"`
+	MXReachable  float64 `json:"mx_reachable" yaml:"mx_reachable"`
+	SMTP         float64 `json:"smtp" yaml:"smtp"`
+	IPReputation float64 `json:"ip_reputation" yaml:"ip_reputation"`
+	DMARC        float64 `json:"dmarc" yaml:"dmarc"`
+	SPF          float64 `json:"spf" yaml:"spf"`
+	DomainAge    float64 `json:"domain_age" yaml:"domain_age"`
+
+	// NumericLocalPenalty is subtracted, as a fraction of the full score,
+	// from addresses whose local part is all digits (see
+	// IsNumericOnlyLocalPart). It is absolute rather than relative to the
+	// other weights, so Normalize leaves it alone. Zero disables it.
+	NumericLocalPenalty float64 `json:"numeric_local_penalty,omitempty" yaml:"numeric_local_penalty,omitempty"`
+}
+
+// DefaultScoringWeights returns weights that favor the mailbox-level checks.
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		Format:       0.10,
+		DomainDNS:    0.10,
+		MXReachable:  0.15,
+		SMTP:         0.30,
+		IPReputation: 0.15,
+		DMARC:        0.07,
+		SPF:          0.08,
+		DomainAge:    0.05,
+	}
+}
+
+// Normalize returns the weights scaled to sum to 1. Negative weights are
+// treated as 0; if all weights are 0 the weights are returned unchanged.
+func (w ScoringWeights) Normalize() ScoringWeights {
+	fields := w.fields()
+	total := 0.0
+	for _, f := range fields {
+		*f = math.Max(*f, 0)
+		total += *f
+	}
+	if total == 0 {
+		return w
+	}
+
+	for _, f := range fields {
+		*f /= total
+	}
+	return w
+}
+
+// fields returns pointers to every relative weight, for bulk updates.
+func (w *ScoringWeights) fields() []*float64 {
+	return []*float64{
+		&w.Format, &w.DomainDNS, &w.MXReachable, &w.SMTP,
+		&w.IPReputation, &w.DMARC, &w.SPF, &w.DomainAge,
+	}
+}
+
+// ComputeScore scores the report from 0 to 100 using weights. Checks that
+// weren't performed (e.g. SMTP when probing is disabled) are left out and the
+// remaining weights are rescaled. An invalid format always scores 0,
+// all-digit local parts lose NumericLocalPenalty, and a BotScore above 50
+// costs one point per point over 50.
+func ComputeScore(report *ValidationReport, weights ScoringWeights) int {
+	if report == nil || !report.FormatCheck.Valid {
+		return 0
+	}
+
+	var sum, totalWeight float64
+	add := func(weight, score float64) {
+		if weight <= 0 {
+			return
+		}
+		sum += weight * score
+		totalWeight += weight
+	}
+
+	add(weights.Format, 1)
+	add(weights.DomainDNS, boolScore(len(report.DNSCheck.ARecords) > 0))
+	add(weights.MXReachable, boolScore(len(report.DNSCheck.MXRecords) > 0))
+
+	if report.SMTPCheck.Status != "" {
+		add(weights.SMTP, float64(smtpScore(report.SMTPCheck.Status))/100)
+	}
+	if len(report.IPReputationChecks) > 0 {
+		add(weights.IPReputation, float64(reputationScore(report.IPReputationChecks, false))/100)
+	}
+	if report.DMARCCheck != nil {
+		add(weights.DMARC, dmarcScore(report.DMARCCheck))
+	}
+	if report.SPFCheck != nil {
+		add(weights.SPF, spfScore(report.SPFCheck))
+	}
+	if report.DomainAgeCheck != nil {
+		add(weights.DomainAge, math.Min(float64(report.DomainAgeCheck.Age)/float64(domainAgeFullScore), 1))
+	}
+
+	if totalWeight == 0 {
+		return 0
+	}
+
+	score := 100 * sum / totalWeight
+	if isAllDigits(report.FormatCheck.LocalPart) {
+		score -= 100 * math.Max(weights.NumericLocalPenalty, 0)
+	}
+	score -= float64(max(report.BotScore-50, 0))
+	return min(max(int(math.Round(score)), 0), 100)
+}
+
+// boolScore converts a pass/fail check to a score.
+func boolScore(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// dmarcScore rates a DMARC policy by how strictly it's enforced.
+func dmarcScore(dmarc *DMARCResult) float64 {
+	if !dmarc.Found {
+		return 0
+	}
+
+	switch dmarc.Policy {
+	case "reject":
+		return 1
+	case "quarantine":
+		return 0.8
+	default:
+		return 0.5
+	}
+}
+
+// spfScore rates an SPF record by its "all" qualifier.
+func spfScore(spf *SPFResult) float64 {
+	if !spf.Found {
+		return 0
+	}
+
+	switch spf.All {
+	case "-":
+		return 1
+	case "~":
+		return 0.8
+	case "?":
+		return 0.5
+	case "+":
+		return 0.3 // "+all" authorizes every sender
+	default:
+		return 0.6
+	}
+}
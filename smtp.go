@@ -2,35 +2,219 @@ package shared
 
 import (
 	"bufio"
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
 
 const (
 	smtpPort   = 25
+	lmtpPort   = 24
 	heloDomain = "my-validator-service.com"
 	fromEmail  = "verify@my-validator-service.com"
+
+	defaultMaxMXAttempts = 3
 )
 
 // SMTP command templates
 const (
 	cmdHelo     = "HELO %s"
+	cmdEhlo     = "EHLO %s"
 	cmdMailFrom = "MAIL FROM:<%s>"
 	cmdRcptTo   = "RCPT TO:<%s>"
 	cmdQuit     = "QUIT"
+	cmdVrfy     = "VRFY %s"
 )
 
+// SMTPConfig holds the settings used when probing mailboxes over SMTP.
+type SMTPConfig struct {
+	Port       int    `json:"port" yaml:"port"`
+	HeloDomain string `json:"helo_domain" yaml:"helo_domain"`
+	FromEmail  string `json:"from_email" yaml:"from_email"`
+
+	// EnablePipelining greets with EHLO and, if the server advertises
+	// PIPELINING (RFC 2920), sends MAIL FROM, RCPT TO and QUIT in one write.
+	EnablePipelining bool `json:"enable_pipelining" yaml:"enable_pipelining"`
+
+	// UseLMTP probes with LMTP (RFC 2033) on LMTPPort instead of SMTP on Port,
+	// for local delivery agents such as Dovecot or Cyrus IMAP.
+	UseLMTP  bool `json:"use_lmtp" yaml:"use_lmtp"`
+	LMTPPort int  `json:"lmtp_port" yaml:"lmtp_port"`
+
+	// UseEHLO greets with EHLO instead of HELO, for servers that refuse HELO.
+	UseEHLO bool `json:"use_ehlo" yaml:"use_ehlo"`
+
+	// UseVRFY asks the server with VRFY before falling back to RCPT TO. Most
+	// servers disable VRFY, so this only helps with providers known to answer it.
+	UseVRFY bool `json:"use_vrfy" yaml:"use_vrfy"`
+
+	// RecordConversation fills SMTPResult.RawConversation with the SMTP
+	// dialogue, for debugging. Off by default to save memory.
+	RecordConversation bool `json:"record_conversation" yaml:"record_conversation"`
+
+	// MaxMXAttempts limits how many MX servers are tried, in priority order,
+	// before giving up with a risky result. Zero uses 3.
+	MaxMXAttempts int `json:"max_mx_attempts" yaml:"max_mx_attempts"`
+
+	// UseSTARTTLS greets with EHLO and upgrades to TLS before probing if the
+	// server advertises STARTTLS (RFC 3207).
+	UseSTARTTLS bool `json:"use_starttls" yaml:"use_starttls"`
+
+	// VerifyTLSHostname checks the server certificate against the MX host
+	// name after STARTTLS; when off, any certificate is accepted. On in
+	// DefaultSMTPConfig.
+	VerifyTLSHostname bool `json:"verify_tls_hostname" yaml:"verify_tls_hostname"`
+}
+
+// DefaultSMTPConfig returns the SMTP settings used by CheckSMTP.
+func DefaultSMTPConfig() SMTPConfig {
+	return SMTPConfig{
+		Port:       smtpPort,
+		HeloDomain: heloDomain,
+		FromEmail:  fromEmail,
+		LMTPPort:   lmtpPort,
+
+		MaxMXAttempts:     defaultMaxMXAttempts,
+		VerifyTLSHostname: true,
+	}
+}
+
+// maxMXAttempts returns the number of MX servers to try.
+func (c SMTPConfig) maxMXAttempts() int {
+	if c.MaxMXAttempts <= 0 {
+		return defaultMaxMXAttempts
+	}
+	return c.MaxMXAttempts
+}
+
+// port returns the port to probe for the configured protocol.
+func (c SMTPConfig) port() int {
+	if !c.UseLMTP {
+		return c.Port
+	}
+	if c.LMTPPort <= 0 {
+		return lmtpPort
+	}
+	return c.LMTPPort
+}
+
+// protocol returns the name of the configured protocol, "SMTP" or "LMTP".
+func (c SMTPConfig) protocol() string {
+	if c.UseLMTP {
+		return protocolLMTP
+	}
+	return protocolSMTP
+}
+
+// NetSMTPDialer dials mail servers over TCP using a net.Dialer, optionally through a SOCKS5 proxy.
+type NetSMTPDialer struct {
+	Dialer net.Dialer
+
+	proxyDial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// SMTPDialerOption configures a NetSMTPDialer.
+type SMTPDialerOption func(*NetSMTPDialer)
+
+// WithSMTPProxy routes SMTP connections through a SOCKS5 proxy.
+func WithSMTPProxy(cfg ProxyConfig) SMTPDialerOption {
+	return func(d *NetSMTPDialer) {
+		d.proxyDial = proxyDialContext(cfg)
+	}
+}
+
+// NewNetSMTPDialer creates a dialer that connects to mail servers directly,
+// or through a proxy if WithSMTPProxy is given.
+func NewNetSMTPDialer(opts ...SMTPDialerOption) *NetSMTPDialer {
+	d := &NetSMTPDialer{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// DialContext connects to the address on the named network.
+func (d *NetSMTPDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.proxyDial != nil {
+		return d.proxyDial(ctx, network, addr)
+	}
+	return d.Dialer.DialContext(ctx, network, addr)
+}
+
 // SMTPResult represents the result of SMTP validation.
 type SMTPResult struct {
-	Status Status
-	Reason string
-	Code   int
+	Status     Status
+	Reason     string
+	Code       int
+	Greylisted bool  // server answered with a temporary 4xx failure
+	Err        error // ErrSMTPConnectionFailed or ErrSMTPCommandFailed when the conversation broke down
+
+	PipeliningUsed bool   // MAIL FROM, RCPT TO and QUIT were sent in a single batch
+	ProtocolUsed   string // "SMTP" or "LMTP"
+
+	// Confidence (0-100) rates how far Status can be trusted; see smtpConfidence.
+	Confidence int
+
+	// RawConversation is the dialogue with the server, when SMTPConfig.RecordConversation is set.
+	RawConversation []SMTPExchange
+
+	// TLSCertCommonName and TLSCertExpiry describe the server certificate
+	// when the connection was upgraded with STARTTLS.
+	TLSCertCommonName string
+	TLSCertExpiry     time.Time
 }
 
 // CheckSMTP performs the mailbox verification using SMTP.
 func CheckSMTP(email string, servers []*net.MX, timeout time.Duration) SMTPResult {
+	return CheckSMTPWithConfig(email, servers, timeout, DefaultSMTPConfig())
+}
+
+// CheckSMTPWithConfig performs the mailbox verification using the given SMTP settings.
+func CheckSMTPWithConfig(email string, servers []*net.MX, timeout time.Duration, cfg SMTPConfig) SMTPResult {
+	return checkSMTP(context.Background(), NewNetSMTPDialer(), email, servers, timeout, cfg)
+}
+
+// ShuffleSamePriorityMX returns records sorted by preference, with records of
+// equal preference in random order. RFC 5321 §5.1 asks senders to spread load
+// across equal-preference servers this way rather than always picking the
+// first. records itself is not modified.
+func ShuffleSamePriorityMX(records []*net.MX) []*net.MX {
+	shuffled := slices.Clone(records)
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return shuffled[i].Pref < shuffled[j].Pref
+	})
+
+	for start := 0; start < len(shuffled); {
+		end := start + 1
+		for end < len(shuffled) && shuffled[end].Pref == shuffled[start].Pref {
+			end++
+		}
+		group := shuffled[start:end]
+		rand.Shuffle(len(group), func(i, j int) {
+			group[i], group[j] = group[j], group[i]
+		})
+		start = end
+	}
+	return shuffled
+}
+
+// checkSMTP performs the mailbox verification, dialing servers through dialer.
+func checkSMTP(ctx context.Context, dialer SMTPDialer, email string, servers []*net.MX, timeout time.Duration, cfg SMTPConfig) SMTPResult {
+	return checkServers(servers, cfg.maxMXAttempts(), func(host string) SMTPResult {
+		return checkSMTPServer(ctx, dialer, email, host, timeout, cfg)
+	})
+}
+
+// checkServers runs check against servers in priority order until one gives a
+// definitive answer, trying at most maxAttempts of them.
+func checkServers(servers []*net.MX, maxAttempts int, check func(host string) SMTPResult) SMTPResult {
 	if len(servers) == 0 {
 		return SMTPResult{
 			Status: StatusInvalid,
@@ -39,19 +223,41 @@ func CheckSMTP(email string, servers []*net.MX, timeout time.Duration) SMTPResul
 		}
 	}
 
-	// Try each MX server in priority order
-	for _, server := range servers {
-		result := checkSMTPServer(email, server.Host, timeout)
+	// Try the MX servers in priority order, up to MaxMXAttempts of them
+	servers = ShuffleSamePriorityMX(servers)
+	attempts := min(len(servers), maxAttempts)
+
+	var greylisted *SMTPResult
+	for _, server := range servers[:attempts] {
+		result := check(server.Host)
 
 		// If we get a definitive answer (valid or invalid), return it
 		if result.Status == StatusValid || result.Status == StatusInvalid {
 			return result
 		}
 
+		// Remember greylisting so the caller can schedule a retry
+		if result.Greylisted && greylisted == nil {
+			greylisted = &result
+		}
+
 		// If risky/error, try next server
 		continue
 	}
 
+	if greylisted != nil {
+		return *greylisted
+	}
+
+	// The attempt limit was reached before a definitive answer
+	if attempts < len(servers) {
+		return SMTPResult{
+			Status: StatusRisky,
+			Reason: fmt.Sprintf("Tried %d of %d SMTP servers, all returned uncertain results", attempts, len(servers)),
+			Code:   0,
+		}
+	}
+
 	// All servers failed or returned risky status
 	return SMTPResult{
 		Status: StatusRisky,
@@ -61,22 +267,65 @@ func CheckSMTP(email string, servers []*net.MX, timeout time.Duration) SMTPResul
 }
 
 // checkSMTPServer checks a single SMTP server.
-func checkSMTPServer(email, serverHost string, timeout time.Duration) SMTPResult {
-	serverAddr := net.JoinHostPort(serverHost, fmt.Sprintf("%d", smtpPort))
+func checkSMTPServer(ctx context.Context, dialer SMTPDialer, email, serverHost string, timeout time.Duration, cfg SMTPConfig) (result SMTPResult) {
+	traits := smtpServerTraits{majorProvider: isMajorMailProvider(serverHost)}
+	var cert *x509.Certificate // set once STARTTLS succeeded
+	defer func() {
+		result.Err = withRequestID(ctx, result.Err)
+		result.ProtocolUsed = cfg.protocol()
+		result.Confidence = smtpConfidence(result, traits)
+		if cert != nil {
+			result.TLSCertCommonName = cert.Subject.CommonName
+			result.TLSCertExpiry = cert.NotAfter
+		}
+	}()
+
+	serverAddr := net.JoinHostPort(serverHost, fmt.Sprintf("%d", cfg.port()))
+
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	conn, err := net.DialTimeout("tcp", serverAddr, timeout)
+	conn, err := dialer.DialContext(dialCtx, "tcp", serverAddr)
 	if err != nil {
+		var proxyErr *ErrProxyUnreachable
+		if errors.As(err, &proxyErr) {
+			return SMTPResult{
+				Status: StatusRisky,
+				Reason: "Could not connect to proxy",
+				Code:   0,
+				Err:    proxyErr,
+			}
+		}
 		return SMTPResult{
 			Status: StatusRisky,
 			Reason: fmt.Sprintf("Could not connect to SMTP server %s", serverHost),
 			Code:   0,
+			Err:    fmt.Errorf("%w %s: %v", ErrSMTPConnectionFailed, serverHost, err),
 		}
 	}
 	defer conn.Close()
+	tcpConn := conn // conn is wrapped for recording and replaced by STARTTLS
+
+	if cfg.RecordConversation {
+		recorded := newConversationConn(conn)
+		conn = recorded
+		defer func() {
+			result.RawConversation = recorded.conversation()
+		}()
+	}
 
-	// Set read/write deadlines
+	// Set read/write deadlines, never past the caller's deadline
 	deadline := time.Now().Add(timeout)
-	conn.SetDeadline(deadline)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	tcpConn.SetDeadline(deadline)
+
+	// Abort blocked reads and writes as soon as ctx is cancelled
+	stop := context.AfterFunc(ctx, func() {
+		tcpConn.SetDeadline(time.Now())
+	})
+	defer stop()
 
 	reader := bufio.NewReader(conn)
 
@@ -87,15 +336,57 @@ func checkSMTPServer(email, serverHost string, timeout time.Duration) SMTPResult
 			Status: StatusRisky,
 			Reason: fmt.Sprintf("Server greeting failed: %d %s", code, msg),
 			Code:   code,
+			Err:    fmt.Errorf("%w: greeting rejected with %d", ErrSMTPCommandFailed, code),
+		}
+	}
+	traits.bannerMatchesHost = bannerNamesHost(msg, serverHost)
+
+	// LMTP has no HELO fallback; LHLO is the only greeting
+	if cfg.UseLMTP {
+		extensions, ok := sendExtendedHello(conn, reader, cmdLhlo, cfg.HeloDomain)
+		if !ok {
+			return SMTPResult{
+				Status: StatusRisky,
+				Reason: "LHLO command rejected",
+				Code:   0,
+				Err:    fmt.Errorf("%w: LHLO rejected", ErrSMTPCommandFailed),
+			}
+		}
+		traits.tls = extensions[extStartTLS]
+		if cfg.UseSTARTTLS && traits.tls {
+			session, err := startTLS(ctx, conn, reader, serverHost, cmdLhlo, cfg)
+			if err != nil {
+				return startTLSFailedResult(serverHost, err)
+			}
+			conn, reader, extensions, cert = session.conn, session.reader, session.extensions, session.cert
+		}
+		return probeMailbox(conn, reader, email, cfg, extensions[extPipelining])
+	}
+
+	// Greet with EHLO when pipelining or STARTTLS may be used or HELO is
+	// refused, falling back to HELO
+	if cfg.EnablePipelining || cfg.UseEHLO || cfg.UseSTARTTLS {
+		extensions, ok := sendExtendedHello(conn, reader, cmdEhlo, cfg.HeloDomain)
+		if ok {
+			traits.tls = extensions[extStartTLS]
+			if cfg.UseSTARTTLS && traits.tls {
+				session, err := startTLS(ctx, conn, reader, serverHost, cmdEhlo, cfg)
+				if err != nil {
+					return startTLSFailedResult(serverHost, err)
+				}
+				conn, reader, extensions, cert = session.conn, session.reader, session.extensions, session.cert
+			}
+			return probeMailbox(conn, reader, email, cfg, extensions[extPipelining])
 		}
 	}
 
 	// Send HELO command
-	if err := send(conn, fmt.Sprintf(cmdHelo, heloDomain)); err != nil {
+	if err := send(conn, fmt.Sprintf(cmdHelo, cfg.HeloDomain)); err != nil {
 		return SMTPResult{
 			Status: StatusRisky,
 			Reason: "HELO command failed",
 			Code:   0,
+			Err:    fmt.Errorf("%w: HELO: %v", ErrSMTPCommandFailed, err),
 		}
 	}
 	code, msg = readResponse(reader)
@@ -104,23 +395,62 @@ func checkSMTPServer(email, serverHost string, timeout time.Duration) SMTPResult
 			Status: StatusRisky,
 			Reason: fmt.Sprintf("HELO command rejected: %d %s", code, msg),
 			Code:   code,
+			Err:    fmt.Errorf("%w: HELO rejected with %d", ErrSMTPCommandFailed, code),
+		}
+	}
+
+	return probeMailbox(conn, reader, email, cfg, false)
+}
+
+// probeMailbox checks email on a greeted connection: with VRFY if enabled,
+// then with a pipelined or step-by-step mail transaction.
+func probeMailbox(conn net.Conn, reader *bufio.Reader, email string, cfg SMTPConfig, pipelining bool) SMTPResult {
+	if cfg.UseVRFY {
+		if result, ok := verifyMailbox(conn, reader, email); ok {
+			send(conn, cmdQuit)
+			return result
 		}
 	}
+	if pipelining && cfg.EnablePipelining {
+		return pipelineTransaction(conn, reader, email, cfg)
+	}
+	return mailTransaction(conn, reader, email, cfg)
+}
+
+// verifyMailbox asks the server about email with VRFY. ok is false if the
+// server couldn't or wouldn't say (e.g. 252 or 502), in which case the
+// caller should fall back to RCPT TO.
+func verifyMailbox(conn net.Conn, reader *bufio.Reader, email string) (SMTPResult, bool) {
+	if err := send(conn, fmt.Sprintf(cmdVrfy, email)); err != nil {
+		return SMTPResult{}, false
+	}
+	code, msg := readResponse(reader)
+	switch code {
+	case 250, 251, 550, 551, 553:
+		return analyzeSMTPResponse(code, msg), true
+	default:
+		return SMTPResult{}, false
+	}
+}
 
+// mailTransaction sends MAIL FROM and RCPT TO one at a time, waiting for each reply.
+func mailTransaction(conn net.Conn, reader *bufio.Reader, email string, cfg SMTPConfig) SMTPResult {
 	// Send MAIL FROM command
-	if err := send(conn, fmt.Sprintf(cmdMailFrom, fromEmail)); err != nil {
+	if err := send(conn, fmt.Sprintf(cmdMailFrom, cfg.FromEmail)); err != nil {
 		return SMTPResult{
 			Status: StatusRisky,
 			Reason: "MAIL FROM command failed",
 			Code:   0,
+			Err:    fmt.Errorf("%w: MAIL FROM: %v", ErrSMTPCommandFailed, err),
 		}
 	}
-	code, msg = readResponse(reader)
+	code, msg := readResponse(reader)
 	if code < 200 || code >= 300 {
 		return SMTPResult{
 			Status: StatusRisky,
 			Reason: fmt.Sprintf("MAIL FROM command rejected: %d %s", code, msg),
 			Code:   code,
+			Err:    fmt.Errorf("%w: MAIL FROM rejected with %d", ErrSMTPCommandFailed, code),
 		}
 	}
 
@@ -130,6 +460,7 @@ func checkSMTPServer(email, serverHost string, timeout time.Duration) SMTPResult
 			Status: StatusRisky,
 			Reason: "RCPT TO command failed",
 			Code:   0,
+			Err:    fmt.Errorf("%w: RCPT TO: %v", ErrSMTPCommandFailed, err),
 		}
 	}
 	code, msg = readResponse(reader)
@@ -169,9 +500,10 @@ func analyzeSMTPResponse(code int, msg string) SMTPResult {
 		}
 	case code >= 400:
 		return SMTPResult{
-			Status: StatusRisky,
-			Reason: "Greylisted or temporary server issue",
-			Code:   code,
+			Status:     StatusRisky,
+			Reason:     "Greylisted or temporary server issue",
+			Code:       code,
+			Greylisted: true,
 		}
 	default:
 		return SMTPResult{
@@ -0,0 +1,128 @@
+// File: shared/smtp_behavior.go
+package shared
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// embeddedSMTPBehaviors is the provider behavior database shipped with the package.
+//
+//go:embed smtp_behaviors.yaml
+var embeddedSMTPBehaviors string
+
+// SMTPBehavior describes how a mail provider responds to SMTP mailbox probes.
+type SMTPBehavior struct {
+	MXPatterns       []string `json:"mx_patterns" yaml:"mx_patterns"` // path.Match patterns for MX host names
+	IsCatchAll       bool     `json:"is_catch_all" yaml:"is_catch_all"`
+	ThrottlesDensely bool     `json:"throttles_densely" yaml:"throttles_densely"`
+	RequiresTLS      bool     `json:"requires_tls" yaml:"requires_tls"` // probed only with SMTPConfig.UseSTARTTLS
+	RejectsHELO      bool     `json:"rejects_helo" yaml:"rejects_helo"`
+	UseVRFY          bool     `json:"use_vrfy" yaml:"use_vrfy"`
+}
+
+// SMTPBehaviorDB maps provider names to their SMTP behavior.
+type SMTPBehaviorDB map[string]SMTPBehavior
+
+// builtinSMTPBehaviors is the parsed embedded database, shared by every validator using it.
+var builtinSMTPBehaviors = sync.OnceValue(func() SMTPBehaviorDB {
+	db, err := LoadSMTPBehaviorDB(strings.NewReader(embeddedSMTPBehaviors))
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded SMTP behavior database: %v", err))
+	}
+	return db
+})
+
+// DefaultSMTPBehaviorDB returns a copy of the behavior database embedded in the package.
+func DefaultSMTPBehaviorDB() SMTPBehaviorDB {
+	db := make(SMTPBehaviorDB, len(builtinSMTPBehaviors()))
+	for name, behavior := range builtinSMTPBehaviors() {
+		db[name] = behavior
+	}
+	return db
+}
+
+// LoadSMTPBehaviorDB reads a behavior database from YAML, keyed by provider name.
+func LoadSMTPBehaviorDB(r io.Reader) (SMTPBehaviorDB, error) {
+	var db SMTPBehaviorDB
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&db); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode SMTP behavior database: %w", err)
+	}
+	if db == nil {
+		db = SMTPBehaviorDB{}
+	}
+
+	for name, behavior := range db {
+		for _, pattern := range behavior.MXPatterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid MX pattern %q for %s: %w", pattern, name, err)
+			}
+		}
+	}
+	return db, nil
+}
+
+// Match returns the provider whose MX patterns match one of mxRecords.
+// Providers are checked in name order so the result is deterministic.
+func (db SMTPBehaviorDB) Match(mxRecords []*net.MX) (string, SMTPBehavior, bool) {
+	names := make([]string, 0, len(db))
+	for name := range db {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, mx := range mxRecords {
+		host := strings.ToLower(strings.TrimSuffix(mx.Host, "."))
+		for _, name := range names {
+			for _, pattern := range db[name].MXPatterns {
+				if ok, _ := path.Match(pattern, host); ok {
+					return name, db[name], true
+				}
+			}
+		}
+	}
+	return "", SMTPBehavior{}, false
+}
+
+// smtpBehaviorDB returns the configured behavior database, or the embedded one.
+func (v *Validator) smtpBehaviorDB() SMTPBehaviorDB {
+	if v.config.SMTPBehaviors != nil {
+		return v.config.SMTPBehaviors
+	}
+	return builtinSMTPBehaviors()
+}
+
+// applySMTPBehavior adjusts the SMTP probe for a known provider. It returns
+// probe=false if the probe should be skipped, and decided=true if the
+// behavior determined the final status of result.
+func applySMTPBehavior(result *Result, name string, behavior SMTPBehavior, cfg *SMTPConfig) (probe, decided bool) {
+	result.Metadata["smtp_behavior_override"] = name
+
+	switch {
+	case behavior.IsCatchAll:
+		result.Status = StatusCatchAll.String()
+		result.Reason = fmt.Sprintf("%s accepts all recipients during SMTP", name)
+		result.Metadata["is_catch_all"] = true
+		return false, true
+	case behavior.ThrottlesDensely:
+		result.Metadata["smtp_skipped"] = fmt.Sprintf("%s throttles SMTP probes", name)
+		return false, false
+	case behavior.RequiresTLS && !cfg.UseSTARTTLS:
+		result.Metadata["smtp_skipped"] = fmt.Sprintf("%s requires STARTTLS", name)
+		return false, false
+	}
+
+	cfg.UseEHLO = cfg.UseEHLO || behavior.RejectsHELO
+	cfg.UseVRFY = cfg.UseVRFY || behavior.UseVRFY
+	return true, false
+}
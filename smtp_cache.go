@@ -0,0 +1,90 @@
+// File: shared/smtp_cache.go
+package shared
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCatchAllCacheTTL is how long a catch-all domain is remembered when no TTL is configured.
+const defaultCatchAllCacheTTL = 24 * time.Hour
+
+// SMTPCache remembers what SMTP checks revealed about whole domains, so
+// probes that can't tell anything new are skipped. Currently it records
+// catch-all domains: once a domain accepts every recipient, probing other
+// mailboxes on it is pointless.
+type SMTPCache struct {
+	mu              sync.RWMutex
+	catchAllDomains map[string]time.Time // lower-cased domain -> expiry
+	now             func() time.Time
+}
+
+// NewSMTPCache creates an empty cache using now as its clock.
+func NewSMTPCache(now func() time.Time) *SMTPCache {
+	if now == nil {
+		now = time.Now
+	}
+	return &SMTPCache{catchAllDomains: make(map[string]time.Time), now: now}
+}
+
+// GetDomainStatus returns StatusCatchAll if domain was found to be catch-all
+// and the entry hasn't expired. ok is false if nothing is known about domain.
+func (c *SMTPCache) GetDomainStatus(domain string) (status Status, ok bool) {
+	domain = strings.ToLower(domain)
+
+	c.mu.RLock()
+	expiresAt, found := c.catchAllDomains[domain]
+	c.mu.RUnlock()
+
+	if !found || !c.now().Before(expiresAt) {
+		return "", false
+	}
+	return StatusCatchAll, true
+}
+
+// MarkCatchAll records domain as catch-all for ttl.
+func (c *SMTPCache) MarkCatchAll(domain string, ttl time.Duration) {
+	domain = strings.ToLower(domain)
+	expiresAt := c.now().Add(ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries while holding the lock anyway
+	now := c.now()
+	for d, exp := range c.catchAllDomains {
+		if !now.Before(exp) {
+			delete(c.catchAllDomains, d)
+		}
+	}
+	c.catchAllDomains[domain] = expiresAt
+}
+
+// SMTPCache returns the cache of domain-level SMTP findings, e.g. to check
+// whether a domain is known to be catch-all.
+func (v *Validator) SMTPCache() *SMTPCache {
+	return v.smtpCache
+}
+
+// markCatchAll records domain as catch-all for ValidatorConfig.CatchAllCacheTTL.
+func (v *Validator) markCatchAll(domain string) {
+	ttl := v.config.CatchAllCacheTTL
+	if ttl <= 0 {
+		ttl = defaultCatchAllCacheTTL
+	}
+	v.smtpCache.MarkCatchAll(domain, ttl)
+}
+
+// applyCachedCatchAll reports result as catch-all without an SMTP check if
+// domain is cached as catch-all. It returns true if it did.
+func (v *Validator) applyCachedCatchAll(result *Result, domain string) bool {
+	if status, ok := v.smtpCache.GetDomainStatus(domain); !ok || status != StatusCatchAll {
+		return false
+	}
+	result.Status = StatusCatchAll.String()
+	result.Reason = "domain accepts all recipients during SMTP"
+	result.Metadata["is_catch_all"] = true
+	result.Metadata["smtp_catch_all_cached"] = true
+	return true
+}
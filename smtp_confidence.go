@@ -0,0 +1,78 @@
+// File: shared/smtp_confidence.go
+package shared
+
+import (
+	"net"
+	"strings"
+)
+
+// Confidence adjustments, see smtpConfidence
+const (
+	confidenceDefinitive    = 70  // RCPT TO was accepted or the mailbox rejected
+	confidenceUncertain     = 30  // anything else
+	confidenceProbeRejected = 20  // the server rejected a made-up address
+	confidenceTLS           = 5   // the server offers STARTTLS
+	confidenceBannerMatch   = 5   // the greeting names the host we connected to
+	confidenceMajorProvider = 10  // a well-known mail provider
+	confidenceThrottled     = -20 // greylisted or throttled
+	confidenceCatchAll      = -40 // the server accepts every recipient
+)
+
+// smtpServerTraits are properties of the mail server observed during a check.
+type smtpServerTraits struct {
+	tls               bool
+	bannerMatchesHost bool
+	majorProvider     bool
+}
+
+// smtpConfidence rates an SMTP result from 0 to 100:
+//
+//	base       70 for a definitive answer (valid or invalid), else 30
+//	+5         the server advertises STARTTLS
+//	+5         the greeting banner names the MX host, as on a server whose PTR record is set up properly
+//	+10        the MX host belongs to a provider in the SMTP behavior database or a detected platform
+//	-20        the server greylisted or throttled the check
+//
+// Catch-all detection adjusts the result afterwards: +20 if the server
+// rejected a made-up probe address, -40 if it accepted it or the platform is
+// known to accept every recipient (see adjustConfidence).
+func smtpConfidence(result SMTPResult, traits smtpServerTraits) int {
+	confidence := confidenceUncertain
+	if result.Status == StatusValid || result.Status == StatusInvalid {
+		confidence = confidenceDefinitive
+	}
+	if traits.tls {
+		confidence += confidenceTLS
+	}
+	if traits.bannerMatchesHost {
+		confidence += confidenceBannerMatch
+	}
+	if traits.majorProvider {
+		confidence += confidenceMajorProvider
+	}
+	if result.Greylisted {
+		confidence += confidenceThrottled
+	}
+	return adjustConfidence(confidence, 0)
+}
+
+// adjustConfidence adds delta to confidence, keeping it within 0-100.
+func adjustConfidence(confidence, delta int) int {
+	return min(max(confidence+delta, 0), 100)
+}
+
+// bannerNamesHost reports whether the greeting banner starts with host, as in "mx.example.com ESMTP Postfix".
+func bannerNamesHost(banner, host string) bool {
+	name, _, _ := strings.Cut(strings.TrimSpace(banner), " ")
+	host = strings.TrimSuffix(host, ".")
+	return name != "" && strings.EqualFold(strings.TrimSuffix(name, "."), host)
+}
+
+// isMajorMailProvider reports whether host belongs to a provider with known SMTP behavior.
+func isMajorMailProvider(host string) bool {
+	mx := []*net.MX{{Host: host}}
+	if _, _, ok := builtinSMTPBehaviors().Match(mx); ok {
+		return true
+	}
+	return NewPlatformDetector().Detect(mx) != PlatformUnknown
+}
@@ -0,0 +1,99 @@
+// File: shared/smtp_conversation.go
+package shared
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Directions of an SMTPExchange
+const (
+	DirectionClient = "C"
+	DirectionServer = "S"
+)
+
+// SMTPExchange is one line of an SMTP conversation: a command sent by the
+// client or a reply line received from the server.
+type SMTPExchange struct {
+	Direction string    `json:"direction"`          // "C" or "S"
+	Command   string    `json:"command,omitempty"`  // client lines
+	Response  string    `json:"response,omitempty"` // server lines, including the code
+	Code      int       `json:"code,omitempty"`     // server reply code
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ConversationText renders RawConversation as "C: ..." and "S: ..." lines.
+func (r SMTPResult) ConversationText() string {
+	var b strings.Builder
+	for _, ex := range r.RawConversation {
+		line := ex.Command
+		if ex.Direction == DirectionServer {
+			line = ex.Response
+		}
+		fmt.Fprintf(&b, "%s: %s\n", ex.Direction, line)
+	}
+	return b.String()
+}
+
+// conversationConn records the lines written to and read from an SMTP connection.
+type conversationConn struct {
+	net.Conn
+
+	mu        sync.Mutex
+	exchanges []SMTPExchange
+	written   strings.Builder // partial client line
+	read      strings.Builder // partial server line
+}
+
+func newConversationConn(conn net.Conn) *conversationConn {
+	return &conversationConn{Conn: conn}
+}
+
+func (c *conversationConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.mu.Lock()
+	c.written.Write(p[:n])
+	for _, line := range takeLines(&c.written) {
+		c.exchanges = append(c.exchanges, SMTPExchange{Direction: DirectionClient, Command: line, Timestamp: time.Now()})
+	}
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *conversationConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.mu.Lock()
+	c.read.Write(p[:n])
+	for _, line := range takeLines(&c.read) {
+		ex := SMTPExchange{Direction: DirectionServer, Response: line, Timestamp: time.Now()}
+		if len(line) >= 3 {
+			fmt.Sscanf(line[:3], "%d", &ex.Code)
+		}
+		c.exchanges = append(c.exchanges, ex)
+	}
+	c.mu.Unlock()
+	return n, err
+}
+
+// conversation returns the recorded exchanges.
+func (c *conversationConn) conversation() []SMTPExchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]SMTPExchange(nil), c.exchanges...)
+}
+
+// takeLines removes the complete CRLF-terminated lines from b and returns them.
+func takeLines(b *strings.Builder) []string {
+	data := b.String()
+	end := strings.LastIndex(data, "\r\n")
+	if end < 0 {
+		return nil
+	}
+
+	b.Reset()
+	b.WriteString(data[end+2:])
+	return strings.Split(data[:end], "\r\n")
+}
@@ -0,0 +1,119 @@
+// File: shared/smtp_pipelining.go
+package shared
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// EHLO keywords advertising RFC 2920 pipelining and RFC 3207 STARTTLS support
+const (
+	extPipelining = "PIPELINING"
+	extStartTLS   = "STARTTLS"
+)
+
+// sendExtendedHello greets the server with an EHLO or LHLO command template
+// and returns the upper-cased extension keywords it advertised. ok is false
+// if the greeting failed or was rejected; after a rejected EHLO the
+// connection is still usable for a HELO greeting.
+func sendExtendedHello(conn net.Conn, reader *bufio.Reader, cmdTemplate, domain string) (extensions map[string]bool, ok bool) {
+	if err := send(conn, fmt.Sprintf(cmdTemplate, domain)); err != nil {
+		return nil, false
+	}
+	code, lines := readMultilineResponse(reader)
+	if code < 200 || code >= 300 {
+		return nil, false
+	}
+
+	// The first line is the server greeting, the rest are extension keywords
+	extensions = make(map[string]bool)
+	for _, line := range lines[1:] {
+		keyword, _, _ := strings.Cut(line, " ")
+		extensions[strings.ToUpper(keyword)] = true
+	}
+	return extensions, true
+}
+
+// pipelineTransaction sends MAIL FROM, RCPT TO and QUIT in a single write and
+// then reads the replies, which the server returns in command order.
+func pipelineTransaction(conn net.Conn, reader *bufio.Reader, email string, cfg SMTPConfig) SMTPResult {
+	batch := fmt.Sprintf(cmdMailFrom, cfg.FromEmail) + "\r\n" +
+		fmt.Sprintf(cmdRcptTo, email) + "\r\n" +
+		cmdQuit
+	if err := send(conn, batch); err != nil {
+		return SMTPResult{
+			Status:         StatusRisky,
+			Reason:         "Pipelined commands failed",
+			Code:           0,
+			Err:            fmt.Errorf("%w: pipelined MAIL FROM/RCPT TO: %v", ErrSMTPCommandFailed, err),
+			PipeliningUsed: true,
+		}
+	}
+
+	// A rejected MAIL FROM makes the RCPT TO reply meaningless (usually 503
+	// bad sequence), so report the MAIL FROM failure instead.
+	code, msg := readResponse(reader)
+	if code < 200 || code >= 300 {
+		return SMTPResult{
+			Status:         StatusRisky,
+			Reason:         fmt.Sprintf("MAIL FROM command rejected: %d %s", code, msg),
+			Code:           code,
+			Err:            fmt.Errorf("%w: MAIL FROM rejected with %d", ErrSMTPCommandFailed, code),
+			PipeliningUsed: true,
+		}
+	}
+
+	// Some servers answer a batch out of step, e.g. by sending a QUIT reply
+	// (221) early. Such a reply is not an answer to RCPT TO, so treat the
+	// result as uncertain rather than as a confirmed mailbox.
+	code, msg = readResponse(reader)
+	if code == 221 {
+		return SMTPResult{
+			Status:         StatusRisky,
+			Reason:         "Unexpected reply order from pipelined commands",
+			Code:           code,
+			Err:            fmt.Errorf("%w: pipelined RCPT TO answered with %d", ErrSMTPCommandFailed, code),
+			PipeliningUsed: true,
+		}
+	}
+
+	result := analyzeSMTPResponse(code, msg)
+	result.PipeliningUsed = true
+	return result
+}
+
+// readMultilineResponse reads a possibly multi-line SMTP reply ("250-..."
+// continuation lines followed by a final "250 ..." line) and returns the
+// reply code with the text of each line.
+func readMultilineResponse(r *bufio.Reader) (int, []string) {
+	var lines []string
+	for {
+		line, _, err := r.ReadLine()
+		if err != nil {
+			return 0, lines
+		}
+
+		responseLine := string(line)
+		if len(responseLine) < 3 {
+			return 0, append(lines, responseLine)
+		}
+
+		var code int
+		if _, err := fmt.Sscanf(responseLine[:3], "%d", &code); err != nil {
+			return 0, append(lines, responseLine)
+		}
+
+		text := ""
+		if len(responseLine) > 4 {
+			text = strings.TrimSpace(responseLine[4:])
+		}
+		lines = append(lines, text)
+
+		// A hyphen after the code marks a continuation line
+		if len(responseLine) == 3 || responseLine[3] != '-' {
+			return code, lines
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// File: shared/smtp_pool.go
+package shared
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// cmdNoop asks the server to do nothing, used to check that a connection is alive
+const cmdNoop = "NOOP"
+
+// SMTPPoolConfig holds the settings of an SMTPPool.
+type SMTPPoolConfig struct {
+	// MaxIdlePerHost is the number of idle connections kept per server address.
+	MaxIdlePerHost int `json:"max_idle_per_host" yaml:"max_idle_per_host"`
+
+	// PingBeforeBorrow sends NOOP on an idle connection before handing it out,
+	// discarding it if the server has closed it in the meantime.
+	PingBeforeBorrow bool `json:"ping_before_borrow" yaml:"ping_before_borrow"`
+
+	// PingTimeout bounds the NOOP round trip.
+	PingTimeout time.Duration `json:"ping_timeout" yaml:"ping_timeout"`
+
+	// DialTimeout bounds connecting to the server and reading its greeting.
+	DialTimeout time.Duration `json:"dial_timeout" yaml:"dial_timeout"`
+}
+
+// DefaultSMTPPoolConfig returns the settings used by NewSMTPPool when none are customized.
+func DefaultSMTPPoolConfig() SMTPPoolConfig {
+	return SMTPPoolConfig{
+		MaxIdlePerHost:   2,
+		PingBeforeBorrow: true,
+		PingTimeout:      5 * time.Second,
+		DialTimeout:      10 * time.Second,
+	}
+}
+
+// SMTPPool keeps idle SMTP connections per server address so repeated checks
+// against the same mail server can skip the TCP handshake and greeting.
+// Connections handed out by Borrow have already received the server greeting.
+type SMTPPool struct {
+	Config SMTPPoolConfig
+
+	dialer SMTPDialer
+
+	mu     sync.Mutex
+	idle   map[string][]net.Conn
+	closed bool
+}
+
+// NewSMTPPool creates a pool that opens new connections through dialer. Zero
+// timeouts use the DefaultSMTPPoolConfig values.
+func NewSMTPPool(dialer SMTPDialer, cfg SMTPPoolConfig) *SMTPPool {
+	defaults := DefaultSMTPPoolConfig()
+	if cfg.PingTimeout <= 0 {
+		cfg.PingTimeout = defaults.PingTimeout
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaults.DialTimeout
+	}
+
+	return &SMTPPool{
+		Config: cfg,
+		dialer: dialer,
+		idle:   make(map[string][]net.Conn),
+	}
+}
+
+// Borrow returns a connection to addr, reusing an idle one if possible. Stale
+// idle connections are discarded when PingBeforeBorrow is set. The caller
+// must either Return the connection or close it.
+func (p *SMTPPool) Borrow(ctx context.Context, addr string) (net.Conn, error) {
+	for {
+		conn := p.popIdle(addr)
+		if conn == nil {
+			break
+		}
+		if !p.Config.PingBeforeBorrow || PingConnection(conn, p.Config.PingTimeout) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+
+	return p.dial(ctx, addr)
+}
+
+// Return puts conn back into the pool for addr. The caller should have ended
+// any mail transaction (e.g. with RSET). Connections beyond MaxIdlePerHost, or
+// returned after Close, are closed instead.
+func (p *SMTPPool) Return(addr string, conn net.Conn) {
+	conn.SetDeadline(time.Time{})
+
+	p.mu.Lock()
+	if p.closed || len(p.idle[addr]) >= p.Config.MaxIdlePerHost {
+		p.mu.Unlock()
+		send(conn, cmdQuit)
+		conn.Close()
+		return
+	}
+	p.idle[addr] = append(p.idle[addr], conn)
+	p.mu.Unlock()
+}
+
+// Close closes every idle connection. Borrowed connections returned afterwards are closed.
+func (p *SMTPPool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = make(map[string][]net.Conn)
+	p.closed = true
+	p.mu.Unlock()
+
+	var errs []error
+	for _, conns := range idle {
+		for _, conn := range conns {
+			send(conn, cmdQuit)
+			errs = append(errs, conn.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// popIdle removes and returns the most recently returned idle connection for addr.
+func (p *SMTPPool) popIdle(addr string) net.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conns := p.idle[addr]
+	if len(conns) == 0 {
+		return nil
+	}
+	conn := conns[len(conns)-1]
+	p.idle[addr] = conns[:len(conns)-1]
+	return conn
+}
+
+// dial opens a new connection to addr and reads the server greeting.
+func (p *SMTPPool) dial(ctx context.Context, addr string) (net.Conn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, p.Config.DialTimeout)
+	defer cancel()
+
+	conn, err := p.dialer.DialContext(dialCtx, "tcp", addr)
+	if err != nil {
+		return nil, withRequestID(ctx, fmt.Errorf("%w %s: %v", ErrSMTPConnectionFailed, addr, err))
+	}
+
+	deadline, _ := dialCtx.Deadline()
+	conn.SetDeadline(deadline)
+	code, _ := readResponse(bufio.NewReader(conn))
+	conn.SetDeadline(time.Time{})
+
+	if code < 200 || code >= 300 {
+		conn.Close()
+		return nil, withRequestID(ctx, fmt.Errorf("%w: greeting rejected with %d", ErrSMTPCommandFailed, code))
+	}
+	return conn, nil
+}
+
+// PingConnection sends NOOP on conn and reports whether the server answered
+// with a 2xx reply within timeout. conn must be idle, i.e. not in the middle
+// of reading a reply.
+func PingConnection(conn net.Conn, timeout time.Duration) bool {
+	conn.SetDeadline(time.Now().Add(timeout))
+	defer conn.SetDeadline(time.Time{})
+
+	if err := send(conn, cmdNoop); err != nil {
+		return false
+	}
+	code, _ := readResponse(bufio.NewReader(conn))
+	return code >= 200 && code < 300
+}
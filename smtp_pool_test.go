@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestSMTPPoolBorrowDiscardsConnClosedMidSession(t *testing.T) {
+	var conns []*MockSMTPConn
+	dialer := &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+		conn := NewMockSMTPConn("220 mock ESMTP ready", nil)
+		if len(conns) == 0 {
+			conn.CloseAfter = 2 // the server hangs up during the first session
+		}
+		conns = append(conns, conn)
+		return conn, nil
+	}}
+
+	pool := NewSMTPPool(dialer, DefaultSMTPPoolConfig())
+	defer pool.Close()
+
+	const addr = "mx.acme.io.:25"
+	first, err := pool.Borrow(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Borrow: %v", err)
+	}
+	for _, cmd := range []string{"EHLO localhost", "MAIL FROM:<probe@example.com>"} {
+		if err := send(first, cmd); err != nil {
+			t.Fatalf("send %q: %v", cmd, err)
+		}
+	}
+	if !conns[0].Closed() {
+		t.Fatal("mock connection is still open after CloseAfter commands")
+	}
+	pool.Return(addr, first)
+
+	second, err := pool.Borrow(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Borrow after the server hung up: %v", err)
+	}
+	if second == first {
+		t.Fatal("Borrow handed out the connection the server closed")
+	}
+	if got := len(dialer.Dialed()); got != 2 {
+		t.Errorf("dialed %d connections, want 2", got)
+	}
+	if !PingConnection(second, DefaultSMTPPoolConfig().PingTimeout) {
+		t.Error("fresh connection does not answer NOOP")
+	}
+}
+
+func TestSMTPPoolBorrowReusesLiveConn(t *testing.T) {
+	dialer := &MockSMTPDialer{}
+	pool := NewSMTPPool(dialer, DefaultSMTPPoolConfig())
+	defer pool.Close()
+
+	const addr = "mx.acme.io.:25"
+	first, err := pool.Borrow(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Borrow: %v", err)
+	}
+	pool.Return(addr, first)
+
+	second, err := pool.Borrow(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Borrow: %v", err)
+	}
+	if second != first {
+		t.Error("Borrow dialed a new connection instead of reusing the idle one")
+	}
+	if got := len(dialer.Dialed()); got != 1 {
+		t.Errorf("dialed %d connections, want 1", got)
+	}
+}
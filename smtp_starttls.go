@@ -0,0 +1,91 @@
+// File: shared/smtp_starttls.go
+package shared
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	cmdStartTLS = "STARTTLS"
+
+	// tlsCertExpiryWarning is how close to expiry a server certificate gets a warning logged
+	tlsCertExpiryWarning = 30 * 24 * time.Hour
+)
+
+// tlsSession is the state of an SMTP connection after STARTTLS.
+type tlsSession struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	extensions map[string]bool   // advertised in the greeting over TLS
+	cert       *x509.Certificate // the server's leaf certificate
+}
+
+// startTLS upgrades a greeted connection with STARTTLS (RFC 3207) and greets
+// the server again with cmdTemplate, since extensions advertised before TLS
+// must be discarded. The certificate is checked against serverHost unless
+// cfg.VerifyTLSHostname is off. A certificate close to expiry is logged but
+// accepted.
+func startTLS(ctx context.Context, conn net.Conn, reader *bufio.Reader, serverHost, cmdTemplate string, cfg SMTPConfig) (tlsSession, error) {
+	if err := send(conn, cmdStartTLS); err != nil {
+		return tlsSession{}, fmt.Errorf("%w: STARTTLS: %v", ErrSMTPCommandFailed, err)
+	}
+	if code, msg := readResponse(reader); code != 220 {
+		return tlsSession{}, fmt.Errorf("%w: STARTTLS rejected with %d %s", ErrSMTPCommandFailed, code, msg)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: strings.TrimSuffix(serverHost, "."),
+		MinVersion: tls.VersionTLS12,
+	}
+	if !cfg.VerifyTLSHostname {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	// Keep recording the conversation, in plain text, above the TLS layer
+	recorded, isRecorded := conn.(*conversationConn)
+	if isRecorded {
+		conn = recorded.Conn
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return tlsSession{}, fmt.Errorf("%w with %s: %v", ErrSMTPTLSFailed, serverHost, err)
+	}
+	conn = tlsConn
+	if isRecorded {
+		recorded.Conn = tlsConn
+		conn = recorded
+	}
+
+	session := tlsSession{conn: conn, reader: bufio.NewReader(conn)}
+	if certs := tlsConn.ConnectionState().PeerCertificates; len(certs) > 0 {
+		session.cert = certs[0]
+		if remaining := time.Until(session.cert.NotAfter); remaining < tlsCertExpiryWarning {
+			log.Printf("%sTLS certificate of SMTP server %s expires %s", requestIDPrefix(ctx), serverHost, session.cert.NotAfter.Format(time.RFC3339))
+		}
+	}
+
+	extensions, ok := sendExtendedHello(session.conn, session.reader, cmdTemplate, cfg.HeloDomain)
+	if !ok {
+		return tlsSession{}, fmt.Errorf("%w: greeting rejected after STARTTLS", ErrSMTPCommandFailed)
+	}
+	session.extensions = extensions
+	return session, nil
+}
+
+// startTLSFailedResult describes a failed STARTTLS negotiation with serverHost.
+func startTLSFailedResult(serverHost string, err error) SMTPResult {
+	return SMTPResult{
+		Status: StatusRisky,
+		Reason: fmt.Sprintf("STARTTLS with %s failed", serverHost),
+		Code:   0,
+		Err:    err,
+	}
+}
@@ -0,0 +1,291 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockSMTPConn is an in-memory net.Conn playing an SMTP server: it sends
+// greeting on connect and answers each command line written to it with a
+// canned reply, without any network or goroutines.
+type MockSMTPConn struct {
+	// Replies maps a command verb ("EHLO", "MAIL", "RCPT", ...) to its reply,
+	// e.g. "550 5.1.1 no such user". Lines of multi-line replies are separated
+	// by "\r\n". Verbs without a reply get "250 OK", or "221 Bye" for QUIT.
+	Replies map[string]string
+
+	// RcptReplies maps a recipient address to the RCPT TO reply for it,
+	// taking precedence over Replies["RCPT"].
+	RcptReplies map[string]string
+
+	// CloseAfter makes the server hang up after answering that many commands.
+	// Zero keeps the connection open.
+	CloseAfter int
+
+	mu       sync.Mutex
+	pending  bytes.Buffer // replies not read yet
+	partial  string       // command line not terminated yet
+	commands []string
+	closed   bool // by either side
+}
+
+// NewMockSMTPConn returns a connection that has sent greeting and answers commands with replies.
+func NewMockSMTPConn(greeting string, replies map[string]string) *MockSMTPConn {
+	c := &MockSMTPConn{Replies: replies}
+	c.pending.WriteString(greeting + "\r\n")
+	return c
+}
+
+// Commands returns the command lines received so far.
+func (c *MockSMTPConn) Commands() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.commands...)
+}
+
+// Closed reports whether either side has closed the connection.
+func (c *MockSMTPConn) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *MockSMTPConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending.Len() == 0 {
+		return 0, io.EOF
+	}
+	return c.pending.Read(p)
+}
+
+func (c *MockSMTPConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+
+	c.partial += string(p)
+	for {
+		line, rest, found := strings.Cut(c.partial, "\r\n")
+		if !found {
+			break
+		}
+		c.partial = rest
+		c.commands = append(c.commands, line)
+		c.pending.WriteString(c.reply(line) + "\r\n")
+
+		if c.CloseAfter > 0 && len(c.commands) >= c.CloseAfter {
+			c.closed = true
+			c.partial = ""
+			break
+		}
+	}
+	return len(p), nil
+}
+
+// reply returns the canned reply to a command line.
+func (c *MockSMTPConn) reply(line string) string {
+	verb, _, _ := strings.Cut(line, " ")
+	verb = strings.ToUpper(verb)
+	if verb == "RCPT" {
+		_, rcpt, _ := strings.Cut(line, "<")
+		rcpt, _, _ = strings.Cut(rcpt, ">")
+		if reply, ok := c.RcptReplies[strings.ToLower(rcpt)]; ok {
+			return reply
+		}
+	}
+	if reply, ok := c.Replies[verb]; ok {
+		return reply
+	}
+	if verb == "QUIT" {
+		return "221 Bye"
+	}
+	return "250 OK"
+}
+
+func (c *MockSMTPConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *MockSMTPConn) LocalAddr() net.Addr                { return mockAddr("client") }
+func (c *MockSMTPConn) RemoteAddr() net.Addr               { return mockAddr("server") }
+func (c *MockSMTPConn) SetDeadline(t time.Time) error      { return nil }
+func (c *MockSMTPConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *MockSMTPConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// mockAddr is the address of either end of a MockSMTPConn.
+type mockAddr string
+
+func (a mockAddr) Network() string { return "mock" }
+func (a mockAddr) String() string  { return string(a) }
+
+// MockSMTPDialer is an SMTPDialer handing out mock connections and recording
+// the addresses dialed.
+type MockSMTPDialer struct {
+	// Dial returns the connection for addr. Nil connects to a MockSMTPConn
+	// accepting every command.
+	Dial func(addr string) (net.Conn, error)
+
+	mu     sync.Mutex
+	dialed []string
+}
+
+func (d *MockSMTPDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.mu.Lock()
+	d.dialed = append(d.dialed, addr)
+	d.mu.Unlock()
+
+	if d.Dial == nil {
+		return NewMockSMTPConn("220 mock ESMTP ready", nil), nil
+	}
+	return d.Dial(addr)
+}
+
+// Dialed returns the addresses dialed so far.
+func (d *MockSMTPDialer) Dialed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.dialed...)
+}
+
+func TestAnalyzeSMTPResponse(t *testing.T) {
+	tests := []struct {
+		code       int
+		status     Status
+		greylisted bool
+	}{
+		{250, StatusValid, false},
+		{251, StatusValid, false},
+		{550, StatusInvalid, false},
+		{551, StatusInvalid, false},
+		{553, StatusInvalid, false},
+		{552, StatusRisky, false},
+		{554, StatusRisky, false},
+		{421, StatusRisky, true},
+		{450, StatusRisky, true},
+		{451, StatusRisky, true},
+		{0, StatusRisky, false},
+		{199, StatusRisky, false},
+	}
+
+	for _, tt := range tests {
+		got := analyzeSMTPResponse(tt.code, "message")
+		if got.Status != tt.status || got.Greylisted != tt.greylisted || got.Code != tt.code {
+			t.Errorf("analyzeSMTPResponse(%d) = {Status: %s, Greylisted: %v, Code: %d}, want {Status: %s, Greylisted: %v, Code: %d}",
+				tt.code, got.Status, got.Greylisted, got.Code, tt.status, tt.greylisted, tt.code)
+		}
+	}
+}
+
+func TestCheckSMTPServerWithMockConn(t *testing.T) {
+	tests := []struct {
+		name       string
+		greeting   string
+		replies    map[string]string
+		cfg        func(*SMTPConfig)
+		status     Status
+		code       int
+		greylisted bool
+		pipelined  bool
+	}{
+		{name: "mailbox exists", greeting: "220 mx.example.com ESMTP", status: StatusValid, code: 250},
+		{name: "mailbox unknown", greeting: "220 mx.example.com ESMTP",
+			replies: map[string]string{"RCPT": "550 5.1.1 no such user"}, status: StatusInvalid, code: 550},
+		{name: "greylisted", greeting: "220 mx.example.com ESMTP",
+			replies: map[string]string{"RCPT": "451 4.7.1 try again later"}, status: StatusRisky, code: 451, greylisted: true},
+		{name: "greeting rejected", greeting: "554 no service", status: StatusRisky, code: 554},
+		{name: "MAIL FROM rejected", greeting: "220 mx.example.com ESMTP",
+			replies: map[string]string{"MAIL": "553 sender rejected"}, status: StatusRisky, code: 553},
+		{name: "EHLO with pipelining", greeting: "220 mx.example.com ESMTP",
+			replies: map[string]string{"EHLO": "250-mx.example.com\r\n250 PIPELINING", "RCPT": "550 no such user"},
+			cfg:     func(cfg *SMTPConfig) { cfg.EnablePipelining = true },
+			status:  StatusInvalid, code: 550, pipelined: true},
+		{name: "EHLO refused falls back to HELO", greeting: "220 mx.example.com ESMTP",
+			replies: map[string]string{"EHLO": "502 command not implemented"},
+			cfg:     func(cfg *SMTPConfig) { cfg.UseEHLO = true },
+			status:  StatusValid, code: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := NewMockSMTPConn(tt.greeting, tt.replies)
+			dialer := &MockSMTPDialer{Dial: func(string) (net.Conn, error) { return conn, nil }}
+			cfg := DefaultSMTPConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+
+			got := checkSMTPServer(context.Background(), dialer, "john@example.com", "mx.example.com", time.Second, cfg)
+			if got.Status != tt.status || got.Code != tt.code || got.Greylisted != tt.greylisted || got.PipeliningUsed != tt.pipelined {
+				t.Errorf("checkSMTPServer = {Status: %s, Code: %d, Greylisted: %v, PipeliningUsed: %v}, want {Status: %s, Code: %d, Greylisted: %v, PipeliningUsed: %v}\ncommands: %q",
+					got.Status, got.Code, got.Greylisted, got.PipeliningUsed, tt.status, tt.code, tt.greylisted, tt.pipelined, conn.Commands())
+			}
+			if !conn.Closed() {
+				t.Error("connection was not closed")
+			}
+			if dialed := dialer.Dialed(); len(dialed) != 1 || dialed[0] != "mx.example.com:25" {
+				t.Errorf("dialed %q, want [mx.example.com:25]", dialed)
+			}
+		})
+	}
+}
+
+func TestCheckSMTPStopsAfterMaxMXAttempts(t *testing.T) {
+	// Listed out of order; every server but the lowest priority one is down
+	servers := []*net.MX{
+		{Host: "mx4.example.com.", Pref: 40},
+		{Host: "mx1.example.com.", Pref: 10},
+		{Host: "backup.example.com.", Pref: 90},
+		{Host: "mx3.example.com.", Pref: 30},
+		{Host: "mx2.example.com.", Pref: 20},
+		{Host: "mx5.example.com.", Pref: 50},
+	}
+	byPriority := []string{"mx1.example.com.:25", "mx2.example.com.:25", "mx3.example.com.:25", "mx4.example.com.:25", "mx5.example.com.:25"}
+
+	for _, maxAttempts := range []int{0, 1, 2, 3, 5} {
+		t.Run(fmt.Sprintf("MaxMXAttempts=%d", maxAttempts), func(t *testing.T) {
+			dialer := &MockSMTPDialer{Dial: func(addr string) (net.Conn, error) {
+				switch {
+				case strings.HasPrefix(addr, "backup."):
+					return NewMockSMTPConn("220 backup ESMTP ready", nil), nil
+				case strings.HasPrefix(addr, "mx2."):
+					return NewMockSMTPConn("421 4.3.2 service not available", nil), nil
+				default:
+					return nil, errors.New("connection refused")
+				}
+			}}
+			cfg := DefaultSMTPConfig()
+			cfg.MaxMXAttempts = maxAttempts
+
+			got := checkSMTP(context.Background(), dialer, "john@example.com", servers, time.Second, cfg)
+
+			want := maxAttempts
+			if want == 0 {
+				want = defaultMaxMXAttempts
+			}
+			if dialed := dialer.Dialed(); !slices.Equal(dialed, byPriority[:want]) {
+				t.Errorf("dialed %q, want %q", dialed, byPriority[:want])
+			}
+			if got.Status != StatusRisky {
+				t.Errorf("status = %s (%s), want risky", got.Status, got.Reason)
+			}
+			if wantReason := fmt.Sprintf("Tried %d of %d SMTP servers", want, len(servers)); !strings.Contains(got.Reason, wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", got.Reason, wantReason)
+			}
+		})
+	}
+}
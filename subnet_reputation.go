@@ -0,0 +1,154 @@
+// File: shared/subnet_reputation.go
+package shared
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+)
+
+// Prefix lengths used to group IPs into subnets
+const (
+	subnetBitsIPv4 = 24
+	subnetBitsIPv6 = 64
+)
+
+// SubnetScore aggregates the reputation of the checked IPs in one subnet.
+type SubnetScore struct {
+	Subnet        string `json:"subnet"` // e.g. "192.0.2.0/24"
+	AverageScore  int    `json:"average_score"`
+	WorstScore    int    `json:"worst_score"`
+	ReportedCount int    `json:"reported_count"` // IPs with at least one report
+	TotalIPs      int    `json:"total_ips"`
+}
+
+// SubnetReputationAggregator groups IP reputation results by subnet, /24 for
+// IPv4 and /64 for IPv6. Mail servers in the same block usually share an
+// operator, and often their reputation.
+type SubnetReputationAggregator struct {
+	subnets map[string][]*IPReputationResult
+}
+
+// NewSubnetReputationAggregator creates an empty aggregator.
+func NewSubnetReputationAggregator() *SubnetReputationAggregator {
+	return &SubnetReputationAggregator{subnets: make(map[string][]*IPReputationResult)}
+}
+
+// Add records result under its subnet. Results for unparseable IPs, failed
+// lookups and IPs already added are ignored.
+func (a *SubnetReputationAggregator) Add(result *IPReputationResult) {
+	if result == nil || result.Error != "" {
+		return
+	}
+	subnet, ok := SubnetOf(result.IPAddress)
+	if !ok {
+		return
+	}
+	for _, existing := range a.subnets[subnet] {
+		if existing.IPAddress == result.IPAddress {
+			return
+		}
+	}
+	a.subnets[subnet] = append(a.subnets[subnet], result)
+}
+
+// Score returns the aggregate for subnet, as returned by SubnetOf.
+func (a *SubnetReputationAggregator) Score(subnet string) (SubnetScore, bool) {
+	results, ok := a.subnets[subnet]
+	if !ok {
+		return SubnetScore{}, false
+	}
+
+	score := SubnetScore{Subnet: subnet, TotalIPs: len(results)}
+	total := 0
+	for _, r := range results {
+		total += r.AbuseConfidenceScore
+		score.WorstScore = max(score.WorstScore, r.AbuseConfidenceScore)
+		if r.TotalReports > 0 {
+			score.ReportedCount++
+		}
+	}
+	score.AverageScore = total / len(results)
+	return score, true
+}
+
+// Scores returns the aggregate of every subnet, ordered by subnet.
+func (a *SubnetReputationAggregator) Scores() []SubnetScore {
+	scores := make([]SubnetScore, 0, len(a.subnets))
+	for subnet := range a.subnets {
+		score, _ := a.Score(subnet)
+		scores = append(scores, score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Subnet < scores[j].Subnet
+	})
+	return scores
+}
+
+// SubnetOf returns the /24 (IPv4) or /64 (IPv6) subnet containing ip in CIDR notation.
+func SubnetOf(ip string) (string, bool) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", false
+	}
+
+	bits, size := subnetBitsIPv6, 128
+	if v4 := parsed.To4(); v4 != nil {
+		parsed, bits, size = v4, subnetBitsIPv4, 32
+	}
+	subnet := net.IPNet{IP: parsed.Mask(net.CIDRMask(bits, size)), Mask: net.CIDRMask(bits, size)}
+	return subnet.String(), true
+}
+
+// subnetReputation aggregates the checked results together with cached
+// results of other IPs in the same subnets, so reports against neighbouring
+// addresses count even though they aren't among the domain's mail servers.
+func (v *EnhancedValidator) subnetReputation(ctx context.Context, checked []*IPReputationResult) *SubnetReputationAggregator {
+	agg := NewSubnetReputationAggregator()
+	subnets := make(map[string]bool)
+	for _, r := range checked {
+		agg.Add(r)
+		if r != nil {
+			if subnet, ok := SubnetOf(r.IPAddress); ok {
+				subnets[subnet] = true
+			}
+		}
+	}
+
+	now := time.Now()
+	v.cacheMutex.RLock()
+	for ip, entry := range v.ipCache[v.tenantID(ctx)] {
+		if entry.expired(now) {
+			continue
+		}
+		if subnet, ok := SubnetOf(ip); ok && subnets[subnet] {
+			agg.Add(entry.result)
+		}
+	}
+	v.cacheMutex.RUnlock()
+
+	return agg
+}
+
+// singleSubnetHighRisk reports whether every checked IP lies in one subnet
+// whose worst known score is high risk.
+func singleSubnetHighRisk(agg *SubnetReputationAggregator, checked []*IPReputationResult) (SubnetScore, bool) {
+	subnet := ""
+	for _, r := range checked {
+		if r == nil || r.Error != "" {
+			continue
+		}
+		s, ok := SubnetOf(r.IPAddress)
+		if !ok || (subnet != "" && s != subnet) {
+			return SubnetScore{}, false
+		}
+		subnet = s
+	}
+	if subnet == "" {
+		return SubnetScore{}, false
+	}
+
+	score, ok := agg.Score(subnet)
+	return score, ok && score.WorstScore > riskHighScore
+}
@@ -1,54 +1,182 @@
 package shared
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
 
-// A more comprehensive regex for email syntax validation.
-var emailRegex = regexp.MustCompile(`^(?i)[a-z0-9!#$%&'*+\/=?^_\x60{|}~-]+(?:\.[a-z0-9!#$%&'*+\/=?^_\x60{|}~-]+)*@(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$`)
+// Regexes for the dot-atom local part and the domain part of an email address.
+var (
+	localPartRegex = regexp.MustCompile(`^(?i)[a-z0-9!#$%&'*+\/=?^_\x60{|}~-]+(?:\.[a-z0-9!#$%&'*+\/=?^_\x60{|}~-]+)*$`)
+	domainRegex    = regexp.MustCompile(`^(?i)(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$`)
+)
+
+// Syntax error codes reported by CheckSyntax.
+const (
+	SyntaxErrEmpty              = "EMPTY"
+	SyntaxErrTooLong            = "TOO_LONG"
+	SyntaxErrMissingAt          = "MISSING_AT"
+	SyntaxErrLocalPartLength    = "LOCAL_PART_LENGTH"
+	SyntaxErrDomainLength       = "DOMAIN_LENGTH"
+	SyntaxErrInvalidLocalPart   = "INVALID_LOCAL_PART"
+	SyntaxErrInvalidDomain      = "INVALID_DOMAIN"
+	SyntaxErrConsecutiveDots    = "CONSECUTIVE_DOTS"
+	SyntaxErrLeadingTrailingDot = "LEADING_OR_TRAILING_DOT"
+	SyntaxErrOnlySpecialChars   = "ONLY_SPECIAL_CHARS"
+	SyntaxErrUnterminatedQuote  = "UNTERMINATED_QUOTE"
+	SyntaxErrInvalidQuotedChar  = "INVALID_QUOTED_CHAR"
+	SyntaxErrHeaderInjection    = "HEADER_INJECTION"
+)
+
+// SyntaxError describes why an email address failed syntax validation.
+type SyntaxError struct {
+	Code   string
+	Detail string
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Detail)
+}
 
 // IsValidSyntax checks the basic format of the email address.
 func IsValidSyntax(email string) bool {
-	if len(email) == 0 || len(email) > 254 {
-		return false
-	}
+	return CheckSyntax(email) == nil
+}
 
-	// Check for basic format
-	if !emailRegex.MatchString(email) {
-		return false
+// CheckSyntax validates the email address format and returns a *SyntaxError
+// describing the first problem found, or nil if the address is valid.
+// Local parts may be a dot-atom or an RFC 5321 §4.1.2 quoted string.
+func CheckSyntax(email string) error {
+	if len(email) == 0 {
+		return &SyntaxError{Code: SyntaxErrEmpty, Detail: "email address is empty"}
+	}
+	if len(email) > 254 {
+		return &SyntaxError{Code: SyntaxErrTooLong, Detail: "email address exceeds 254 characters"}
+	}
+	if HasHeaderInjection(email) {
+		return &SyntaxError{Code: SyntaxErrHeaderInjection, Detail: "email address contains control characters"}
 	}
 
-	// Additional checks
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
-		return false
+	// Split on the last @ since a quoted local part may itself contain @
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return &SyntaxError{Code: SyntaxErrMissingAt, Detail: "email address has no @"}
 	}
 
-	localPart := parts[0]
-	domain := parts[1]
+	localPart := email[:at]
+	domain := email[at+1:]
 
 	// Local part length check (RFC 5321)
-	if len(localPart) > 64 {
-		return false
+	if len(localPart) == 0 || len(localPart) > 64 {
+		return &SyntaxError{Code: SyntaxErrLocalPartLength, Detail: "local part must be 1-64 characters"}
 	}
 
 	// Domain length check
-	if len(domain) > 253 {
-		return false
+	if len(domain) == 0 || len(domain) > 253 {
+		return &SyntaxError{Code: SyntaxErrDomainLength, Detail: "domain must be 1-253 characters"}
+	}
+
+	if strings.HasPrefix(localPart, `"`) {
+		if err := checkQuotedLocalPart(localPart); err != nil {
+			return err
+		}
+	} else if err := checkDotAtomLocalPart(localPart); err != nil {
+		return err
+	}
+
+	// Check for consecutive dots in the domain
+	if strings.Contains(domain, "..") {
+		return &SyntaxError{Code: SyntaxErrConsecutiveDots, Detail: "domain contains consecutive dots"}
 	}
 
+	if !domainRegex.MatchString(domain) {
+		return &SyntaxError{Code: SyntaxErrInvalidDomain, Detail: "domain has an invalid format"}
+	}
+
+	return nil
+}
+
+// percentEncodedControls are URL-encoded CR, LF and NUL, which turn into
+// header injections if the address is decoded before use
+var percentEncodedControls = []string{"%0a", "%0d", "%00"}
+
+// HasHeaderInjection reports whether email contains characters that could
+// inject headers when it is placed in a To: or Cc: field: CR, LF, NUL or any
+// other ASCII control character, literally or as URL-encoded CR, LF or NUL.
+func HasHeaderInjection(email string) bool {
+	for i := 0; i < len(email); i++ {
+		if email[i] < 32 || email[i] == 127 {
+			return true
+		}
+	}
+
+	lower := strings.ToLower(email)
+	for _, encoded := range percentEncodedControls {
+		if strings.Contains(lower, encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDotAtomLocalPart validates an unquoted local part.
+func checkDotAtomLocalPart(localPart string) error {
 	// Check for consecutive dots
-	if strings.Contains(email, "..") {
-		return false
+	if strings.Contains(localPart, "..") {
+		return &SyntaxError{Code: SyntaxErrConsecutiveDots, Detail: "local part contains consecutive dots"}
 	}
 
 	// Check if local part starts or ends with dot
 	if strings.HasPrefix(localPart, ".") || strings.HasSuffix(localPart, ".") {
-		return false
+		return &SyntaxError{Code: SyntaxErrLeadingTrailingDot, Detail: "local part starts or ends with a dot"}
+	}
+
+	if !localPartRegex.MatchString(localPart) {
+		return &SyntaxError{Code: SyntaxErrInvalidLocalPart, Detail: "local part contains invalid characters"}
+	}
+
+	// A local part made up entirely of special characters is rejected
+	hasAlnum := strings.IndexFunc(localPart, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+	}) >= 0
+	if !hasAlnum {
+		return &SyntaxError{Code: SyntaxErrOnlySpecialChars, Detail: "local part contains only special characters"}
 	}
 
-	return true
+	return nil
+}
+
+// checkQuotedLocalPart validates a quoted-string local part per RFC 5321 §4.1.2:
+// qtextSMTP is %d32-33 / %d35-91 / %d93-126 and quoted-pairSMTP is a backslash
+// followed by %d32-126.
+func checkQuotedLocalPart(localPart string) error {
+	if len(localPart) < 2 || !strings.HasSuffix(localPart, `"`) {
+		return &SyntaxError{Code: SyntaxErrUnterminatedQuote, Detail: "quoted local part is not terminated"}
+	}
+
+	content := localPart[1 : len(localPart)-1]
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\\':
+			// quoted-pair: the backslash must escape a printable character
+			if i+1 >= len(content) {
+				return &SyntaxError{Code: SyntaxErrUnterminatedQuote, Detail: "quoted local part ends with an escaped quote"}
+			}
+			i++
+			if content[i] < 32 || content[i] > 126 {
+				return &SyntaxError{Code: SyntaxErrInvalidQuotedChar, Detail: "invalid escaped character in quoted local part"}
+			}
+		case c == '"':
+			return &SyntaxError{Code: SyntaxErrInvalidQuotedChar, Detail: "unescaped quote in quoted local part"}
+		case c < 32 || c > 126:
+			return &SyntaxError{Code: SyntaxErrInvalidQuotedChar, Detail: "invalid character in quoted local part"}
+		}
+	}
+
+	return nil
 }
 
 // IsDisposable checks if the domain is a known disposable email provider.
@@ -57,6 +185,14 @@ func IsDisposable(domain string, disposableDomains map[string]bool) bool {
 	return disposableDomains[domain]
 }
 
+// DefaultRoleBasedAccounts lists common role-based local parts.
+var DefaultRoleBasedAccounts = map[string]bool{
+	"admin": true, "administrator": true, "webmaster": true, "hostmaster": true,
+	"postmaster": true, "abuse": true, "info": true, "support": true, "sales": true,
+	"contact": true, "help": true, "noreply": true, "no-reply": true, "billing": true,
+	"marketing": true, "office": true, "security": true, "team": true, "hr": true,
+}
+
 // IsRoleBased checks if the local part of the email is a role-based account.
 func IsRoleBased(localPart string, roleBasedAccounts map[string]bool) bool {
 	localPart = strings.ToLower(localPart)
@@ -0,0 +1,47 @@
+// File: shared/syntax_mode.go
+package shared
+
+import (
+	"net/mail"
+)
+
+// SyntaxMode selects the address parser ValidateEmail uses for its format check.
+type SyntaxMode string
+
+const (
+	// SyntaxModePragmatic accepts the plain addresses people type into forms,
+	// matched by a simple regex. This is the default.
+	SyntaxModePragmatic SyntaxMode = "pragmatic"
+	// SyntaxModeRFC5322 parses the input as an RFC 5322 address with net/mail.
+	//
+	// It is more permissive than the pragmatic mode: display names
+	// ("John Doe <john@example.com>"), comments, quoted local parts and
+	// dotless domains such as "localhost" are accepted, and the bare address
+	// is validated from there on. It is also stricter: local parts with
+	// leading, trailing or consecutive dots are rejected. Use it for addresses
+	// taken from message headers rather than form inputs.
+	SyntaxModeRFC5322 SyntaxMode = "rfc5322"
+)
+
+// IsValidSyntaxRFC5322 reports whether email parses as an RFC 5322 address,
+// optionally with a display name. See SyntaxModeRFC5322 for how this differs
+// from IsValidSyntax.
+func IsValidSyntaxRFC5322(email string) bool {
+	_, err := mail.ParseAddress(email)
+	return err == nil
+}
+
+// checkFormat runs the format check of the configured syntax mode and returns
+// the address to validate further, which in RFC 5322 mode is stripped of any
+// display name and comments.
+func (v *Validator) checkFormat(email string) (string, bool) {
+	if v.config.SyntaxMode != SyntaxModeRFC5322 {
+		return email, v.emailRegex.MatchString(email)
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", false
+	}
+	return addr.Address, true
+}
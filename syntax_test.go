@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCheckSyntaxRFC5321LocalPart covers the RFC 5321 §4.1.2 local part edge cases.
+func TestCheckSyntaxRFC5321LocalPart(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		code  string // empty when the address is valid
+	}{
+		{"single dot", ".@example.com", SyntaxErrLeadingTrailingDot},
+		{"only dots", "...@example.com", SyntaxErrConsecutiveDots},
+		{"only special characters", "!#$%@example.com", SyntaxErrOnlySpecialChars},
+		{"hyphens around dot atom", "-john-@example.com", ""},
+		{"hyphens around quoted string", `"-john-"@example.com`, ""},
+		{"space in quoted string", `"john doe"@example.com`, ""},
+		{"escaped quote", `"john\"doe"@example.com`, ""},
+		{"escaped at sign", `"john\@doe"@example.com`, ""},
+		{"unescaped at sign in quoted string", `"john@doe"@example.com`, ""},
+		{"escaped backslash", `"john\\doe"@example.com`, ""},
+		{"escaped nested quotes", `"john \"jd\" doe"@example.com`, ""},
+		{"unescaped nested quotes", `"john "jd" doe"@example.com`, SyntaxErrInvalidQuotedChar},
+		{"unterminated quote", `"john@example.com`, SyntaxErrUnterminatedQuote},
+		{"escaped closing quote", `"john\"@example.com`, SyntaxErrUnterminatedQuote},
+		{"empty quoted string", `""@example.com`, ""},
+		{"non-ASCII in quoted string", "\"jöhn\"@example.com", SyntaxErrInvalidQuotedChar},
+		{"non-ASCII after backslash", "\"john\\ö\"@example.com", SyntaxErrInvalidQuotedChar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSyntax(tt.email)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("CheckSyntax(%q) = %v, want nil", tt.email, err)
+				}
+				if !IsValidSyntax(tt.email) {
+					t.Errorf("IsValidSyntax(%q) = false, want true", tt.email)
+				}
+				return
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("CheckSyntax(%q) = %v, want a *SyntaxError", tt.email, err)
+			}
+			if syntaxErr.Code != tt.code {
+				t.Errorf("CheckSyntax(%q) code = %s, want %s", tt.email, syntaxErr.Code, tt.code)
+			}
+			if IsValidSyntax(tt.email) {
+				t.Errorf("IsValidSyntax(%q) = true, want false", tt.email)
+			}
+		})
+	}
+}
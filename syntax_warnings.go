@@ -0,0 +1,94 @@
+// File: shared/syntax_warnings.go
+package shared
+
+import (
+	"strings"
+)
+
+// Syntax warning codes reported by ValidateSyntaxDetailed.
+const (
+	SyntaxWarnPlusAddressing   = "PLUS_ADDRESSING"
+	SyntaxWarnLongLocalPart    = "LONG_LOCAL_PART"
+	SyntaxWarnNumericLocalPart = "NUMERIC_LOCAL_PART"
+	SyntaxWarnHyphenPlacement  = "HYPHEN_PLACEMENT"
+)
+
+// SyntaxWarning describes a pattern that is valid per RFC but commonly causes
+// delivery problems. Unlike SyntaxError it doesn't make an address invalid.
+type SyntaxWarning struct {
+	Code   string
+	Detail string
+}
+
+// ValidateSyntaxDetailed checks the address like CheckSyntax and additionally
+// reports soft issues: plus addressing, local parts over 30 characters,
+// numeric-only local parts, and hyphens at the edge of the local part or in
+// the third and fourth position of a domain label (reserved for IDNA "xn--").
+// Warnings are only reported for addresses that pass CheckSyntax.
+func ValidateSyntaxDetailed(email string) (valid bool, warnings []SyntaxWarning) {
+	if CheckSyntax(email) != nil {
+		return false, nil
+	}
+	return true, syntaxWarnings(email)
+}
+
+// syntaxWarnings returns the soft issues of a syntactically valid address.
+func syntaxWarnings(email string) []SyntaxWarning {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+	localPart := email[:at]
+	domain := email[at+1:]
+
+	var warnings []SyntaxWarning
+	if strings.Contains(localPart, "+") {
+		warnings = append(warnings, SyntaxWarning{
+			Code:   SyntaxWarnPlusAddressing,
+			Detail: "local part contains '+', which some mail systems reject or strip",
+		})
+	}
+	if len(localPart) > maxUnsuspiciousLocalPartLength {
+		warnings = append(warnings, SyntaxWarning{
+			Code:   SyntaxWarnLongLocalPart,
+			Detail: "local part is longer than 30 characters",
+		})
+	}
+	if isAllDigits(localPart) {
+		warnings = append(warnings, SyntaxWarning{
+			Code:   SyntaxWarnNumericLocalPart,
+			Detail: "local part consists only of digits",
+		})
+	}
+	if hyphenPlacementIssue(localPart, domain) {
+		warnings = append(warnings, SyntaxWarning{
+			Code:   SyntaxWarnHyphenPlacement,
+			Detail: "hyphen at the start or end of the local part, or in a reserved domain label position",
+		})
+	}
+	return warnings
+}
+
+// syntaxWarningCodes returns the codes of warnings, as stored in Result.Metadata.
+func syntaxWarningCodes(warnings []SyntaxWarning) []string {
+	codes := make([]string, len(warnings))
+	for i, w := range warnings {
+		codes[i] = w.Code
+	}
+	return codes
+}
+
+// hyphenPlacementIssue reports hyphens that are legal but unusual: at either
+// end of the local part, or "--" at the third position of a domain label
+// other than the IDNA "xn--" prefix (RFC 5891 §4.2.3.1).
+func hyphenPlacementIssue(localPart, domain string) bool {
+	if strings.HasPrefix(localPart, "-") || strings.HasSuffix(localPart, "-") {
+		return true
+	}
+	for _, label := range strings.Split(strings.ToLower(domain), ".") {
+		if len(label) >= 4 && label[2:4] == "--" && !strings.HasPrefix(label, "xn--") {
+			return true
+		}
+	}
+	return false
+}
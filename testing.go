@@ -0,0 +1,460 @@
+//go:build testing
+
+// File: shared/testing.go
+package shared
+
+import (
+	"bufio"
+	cryptorand "crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SMTPRequest records a single command received by MockSMTPServer.
+type SMTPRequest struct {
+	RemoteAddr string
+	Command    string // upper-cased verb, e.g. "RCPT"
+	Args       string // everything after the verb
+	Time       time.Time
+}
+
+// smtpReply is a canned SMTP response.
+type smtpReply struct {
+	code    int
+	message string
+}
+
+// MockSMTPServer is a local SMTP server for integration tests. It listens on
+// a random loopback port and answers RCPT TO according to its configuration.
+// Unknown recipients are rejected with 550 unless SetCatchAll is used.
+//
+// Example:
+//
+//	srv, err := NewMockSMTPServer()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer srv.Close()
+//	srv.SetRCPTResponse("alice@example.org", 250, "OK")
+//	host, port, _ := net.SplitHostPort(srv.Addr())
+//	cfg := DefaultSMTPConfig()
+//	cfg.Port, _ = strconv.Atoi(port)
+//	result := CheckSMTPWithConfig("alice@example.org", []*net.MX{{Host: host}}, time.Second, cfg)
+type MockSMTPServer struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+	conns    map[net.Conn]struct{} // open sessions, guarded by mu
+
+	mu            sync.Mutex
+	greeting      string
+	rcptResponses map[string]smtpReply
+	catchAll      *smtpReply
+	tlsConfig     *tls.Config
+	greylisted    map[string]bool
+	greylistAfter time.Duration
+	firstSeen     map[string]time.Time
+	requests      []SMTPRequest
+}
+
+// NewMockSMTPServer starts a mock SMTP server on 127.0.0.1 with a random port.
+func NewMockSMTPServer() (*MockSMTPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start mock SMTP server: %w", err)
+	}
+
+	s := &MockSMTPServer{
+		listener:      listener,
+		greeting:      "mock.smtp.local ESMTP ready",
+		rcptResponses: make(map[string]smtpReply),
+		greylisted:    make(map[string]bool),
+		firstSeen:     make(map[string]time.Time),
+		conns:         make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns the host:port the server is listening on.
+func (s *MockSMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server, closes open sessions and waits for their goroutines to exit.
+func (s *MockSMTPServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// SetRCPTResponse sets the response to RCPT TO for a specific address.
+func (s *MockSMTPServer) SetRCPTResponse(email string, code int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rcptResponses[strings.ToLower(email)] = smtpReply{code: code, message: message}
+}
+
+// SetCatchAll answers RCPT TO for every address without a specific response with code.
+func (s *MockSMTPServer) SetCatchAll(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.catchAll = &smtpReply{code: code, message: "catch-all"}
+}
+
+// SetGreeting sets the banner sent in the 220 greeting.
+func (s *MockSMTPServer) SetGreeting(banner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.greeting = banner
+}
+
+// EnableTLS advertises STARTTLS and upgrades sessions using the given PEM-encoded certificate and key.
+func (s *MockSMTPServer) EnableTLS(cert, key []byte) error {
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tlsConfig = &tls.Config{Certificates: []tls.Certificate{pair}}
+	return nil
+}
+
+// EnableGraylisting answers RCPT TO for the given addresses with 450 until
+// retryAfter has elapsed since the address was first seen.
+func (s *MockSMTPServer) EnableGraylisting(emails []string, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, email := range emails {
+		s.greylisted[strings.ToLower(email)] = true
+	}
+	s.greylistAfter = retryAfter
+}
+
+// Requests returns a copy of every command received so far.
+func (s *MockSMTPServer) Requests() []SMTPRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]SMTPRequest, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// serve accepts connections until the listener is closed.
+func (s *MockSMTPServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+			}()
+			s.handleSession(conn)
+		}()
+	}
+}
+
+// handleSession speaks SMTP with a single client.
+func (s *MockSMTPServer) handleSession(conn net.Conn) {
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	s.mu.Lock()
+	greeting := s.greeting
+	tlsConfig := s.tlsConfig
+	s.mu.Unlock()
+
+	reader := bufio.NewReader(conn)
+	reply := func(code int, message string) bool {
+		_, err := fmt.Fprintf(conn, "%d %s\r\n", code, message)
+		return err == nil
+	}
+
+	if !reply(220, greeting) {
+		return
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		verb, args, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+		s.record(conn.RemoteAddr().String(), verb, args)
+
+		switch verb {
+		case "HELO":
+			reply(250, "mock.smtp.local")
+		case "EHLO":
+			fmt.Fprint(conn, "250-mock.smtp.local\r\n")
+			if tlsConfig != nil {
+				fmt.Fprint(conn, "250-STARTTLS\r\n")
+			}
+			reply(250, "PIPELINING")
+		case "STARTTLS":
+			if tlsConfig == nil {
+				reply(502, "STARTTLS not supported")
+				continue
+			}
+			reply(220, "Ready to start TLS")
+			tlsConn := tls.Server(conn, tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+		case "MAIL", "RSET", "NOOP":
+			reply(250, "OK")
+		case "RCPT":
+			r := s.rcptReply(args)
+			reply(r.code, r.message)
+		case "VRFY":
+			reply(252, "Cannot VRFY user")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+// rcptReply decides the response to a RCPT TO command.
+func (s *MockSMTPServer) rcptReply(args string) smtpReply {
+	email := parseRCPTAddress(args)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.greylisted[email] {
+		first, seen := s.firstSeen[email]
+		if !seen {
+			s.firstSeen[email] = time.Now()
+			return smtpReply{code: 450, message: "4.7.1 Greylisted, please try again later"}
+		}
+		if time.Since(first) < s.greylistAfter {
+			return smtpReply{code: 450, message: "4.7.1 Greylisted, please try again later"}
+		}
+	}
+
+	if r, ok := s.rcptResponses[email]; ok {
+		return r
+	}
+	if s.catchAll != nil {
+		return *s.catchAll
+	}
+
+	return smtpReply{code: 550, message: "5.1.1 No such user"}
+}
+
+// parseRCPTAddress extracts the address from "TO:<addr>" arguments.
+func parseRCPTAddress(args string) string {
+	args = strings.TrimSpace(args)
+	if len(args) >= 3 && strings.EqualFold(args[:3], "TO:") {
+		args = args[3:]
+	}
+	return strings.ToLower(strings.Trim(strings.TrimSpace(args), "<>"))
+}
+
+// record stores a received command for later inspection.
+func (s *MockSMTPServer) record(remoteAddr, verb, args string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, SMTPRequest{
+		RemoteAddr: remoteAddr,
+		Command:    verb,
+		Args:       args,
+		Time:       time.Now(),
+	})
+}
+
+// EmailGenerator produces email addresses for load tests and test corpora.
+// It is safe for concurrent use.
+type EmailGenerator struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewEmailGenerator creates a generator seeded from crypto/rand, so every
+// generator produces a different sequence.
+func NewEmailGenerator() *EmailGenerator {
+	var seed [32]byte
+	cryptorand.Read(seed[:])
+	return &EmailGenerator{rng: rand.New(rand.NewChaCha8(seed))}
+}
+
+// NewEmailGeneratorWithSeed creates a generator producing a reproducible sequence.
+func NewEmailGeneratorWithSeed(seed uint64) *EmailGenerator {
+	return &EmailGenerator{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// generatorFirstNames and generatorLastNames build realistic local parts
+var (
+	generatorFirstNames = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy", "mallory", "oscar", "peggy", "trent", "victor", "wendy"}
+	generatorLastNames  = []string{"smith", "jones", "garcia", "miller", "davis", "lopez", "wilson", "moore", "taylor", "martin", "lee", "clark"}
+	generatorTLDs       = []string{"com", "net", "org", "io", "de", "co.uk"}
+)
+
+// generatorInvalid builds malformed addresses from a valid local part and domain.
+var generatorInvalid = []func(local, domain string) string{
+	func(local, domain string) string { return local + domain },                         // missing @
+	func(local, domain string) string { return local + "@@" + domain },                  // double @
+	func(local, domain string) string { return "@" + domain },                           // empty local part
+	func(local, domain string) string { return local + "@" },                            // empty domain
+	func(local, domain string) string { return "." + local + "@" + domain },             // leading dot
+	func(local, domain string) string { return local + ".@" + domain },                  // trailing dot
+	func(local, domain string) string { return local + "..x@" + domain },                // consecutive dots
+	func(local, domain string) string { return local + " x@" + domain },                 // space
+	func(local, domain string) string { return local + "@" + domain + ".." },            // domain ends with dots
+	func(local, domain string) string { return local + "@-" + domain },                  // label starts with hyphen
+	func(local, domain string) string { return strings.Repeat("a", 65) + "@" + domain }, // local part too long
+}
+
+// GenerateValid returns count syntactically valid addresses on domain.
+func (g *EmailGenerator) GenerateValid(domain string, count int) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	emails := make([]string, count)
+	for i := range emails {
+		emails[i] = g.personalLocalPart() + "@" + domain
+	}
+	return emails
+}
+
+// GenerateInvalid returns count addresses that each fail CheckSyntax.
+func (g *EmailGenerator) GenerateInvalid(count int) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	emails := make([]string, count)
+	for i := range emails {
+		build := generatorInvalid[g.rng.IntN(len(generatorInvalid))]
+		emails[i] = build(g.personalLocalPart(), g.randomDomain())
+	}
+	return emails
+}
+
+// GenerateDisposable returns count addresses on domains from the built-in disposable list.
+func (g *EmailGenerator) GenerateDisposable(count int) []string {
+	domains := make([]string, 0, len(builtinDisposableDomains()))
+	for domain := range builtinDisposableDomains() {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains) // map order would defeat seeded generators
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	emails := make([]string, count)
+	for i := range emails {
+		emails[i] = g.personalLocalPart() + "@" + domains[g.rng.IntN(len(domains))]
+	}
+	return emails
+}
+
+// GenerateRoleBased returns count role account addresses (admin@, support@, ...)
+// on domain, drawn from DefaultRoleBasedAccounts. Roles repeat once count
+// exceeds the number of roles.
+func (g *EmailGenerator) GenerateRoleBased(domain string, count int) []string {
+	roles := make([]string, 0, len(DefaultRoleBasedAccounts))
+	for role := range DefaultRoleBasedAccounts {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	emails := make([]string, count)
+	for i := range emails {
+		emails[i] = roles[g.rng.IntN(len(roles))] + "@" + domain
+	}
+	return emails
+}
+
+// GenerateRandom returns count valid addresses with random-looking local parts on random domains.
+func (g *EmailGenerator) GenerateRandom(count int) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	emails := make([]string, count)
+	for i := range emails {
+		emails[i] = g.randomString(12+g.rng.IntN(9)) + "@" + g.randomDomain()
+	}
+	return emails
+}
+
+// personalLocalPart returns a name-like local part such as "alice.smith42".
+func (g *EmailGenerator) personalLocalPart() string {
+	first := generatorFirstNames[g.rng.IntN(len(generatorFirstNames))]
+	last := generatorLastNames[g.rng.IntN(len(generatorLastNames))]
+	switch g.rng.IntN(4) {
+	case 0:
+		return first + "." + last
+	case 1:
+		return first + last + fmt.Sprint(g.rng.IntN(100))
+	case 2:
+		return first[:1] + last
+	default:
+		return first + "_" + last
+	}
+}
+
+// randomDomain returns a random-looking domain such as "kqzv.net".
+func (g *EmailGenerator) randomDomain() string {
+	return g.randomString(4+g.rng.IntN(8)) + "." + generatorTLDs[g.rng.IntN(len(generatorTLDs))]
+}
+
+// randomString returns n random lower-case letters and digits, starting with a letter.
+func (g *EmailGenerator) randomString(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	const alphanumeric = letters + "0123456789"
+
+	b := make([]byte, n)
+	b[0] = letters[g.rng.IntN(len(letters))]
+	for i := 1; i < n; i++ {
+		b[i] = alphanumeric[g.rng.IntN(len(alphanumeric))]
+	}
+	return string(b)
+}
@@ -0,0 +1,102 @@
+// File: shared/tls_pinning.go
+package shared
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// WithTLSPinning rejects AbuseIPDB connections unless a certificate in the
+// server's chain matches one of pins. Each pin is a base64-encoded SHA-256
+// hash of a DER-encoded SubjectPublicKeyInfo; see ExtractSPKIPin. Pinning is
+// applied on top of normal certificate verification, not instead of it: only
+// certificates in the verified chains count, so a pinned certificate the
+// server merely appends to an unrelated chain is rejected.
+func WithTLSPinning(pins []string) AbuseIPDBOption {
+	return func(c *AbuseIPDBClient) {
+		pinSet := make(map[string]bool, len(pins))
+		for _, pin := range pins {
+			pinSet[pin] = true
+		}
+
+		transport, err := c.transport("WithTLSPinning")
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.VerifyPeerCertificate = func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+			return verifySPKIPins(verifiedChains, pinSet)
+		}
+	}
+}
+
+// ExtractSPKIPin returns the pin for cert in the format expected by WithTLSPinning.
+func ExtractSPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// verifySPKIPins checks the verified certificate chains against the pin set.
+// There are no verified chains when InsecureSkipVerify is set, so every
+// connection is then rejected.
+func verifySPKIPins(verifiedChains [][]*x509.Certificate, pins map[string]bool) error {
+	for _, chain := range verifiedChains {
+		for _, cert := range chain {
+			if pins[ExtractSPKIPin(cert)] {
+				return nil
+			}
+		}
+	}
+	return ErrTLSPinMismatch
+}
+
+// transport returns the client's *http.Transport for option to modify. A
+// transport the client doesn't own, including http.DefaultTransport and one
+// shared with other clients, is cloned first so changes stay local.
+func (c *AbuseIPDBClient) transport(option string) (*http.Transport, error) {
+	client, err := c.client(option)
+	if err != nil {
+		return nil, err
+	}
+	if c.ownsTransport {
+		return client.Transport.(*http.Transport), nil
+	}
+
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("%s requires an *http.Transport, got %T", option, client.Transport)
+	}
+	client.Transport = transport
+	c.ownsTransport = true
+
+	return transport, nil
+}
+
+// client returns the client's *http.Client for option to modify. A client
+// passed in with WithHTTPDoer is copied first so the caller's is left alone.
+// Other HTTPDoer implementations can't be configured and are an error.
+func (c *AbuseIPDBClient) client(option string) (*http.Client, error) {
+	client, ok := c.httpClient.(*http.Client)
+	if !ok {
+		return nil, fmt.Errorf("%s requires an *http.Client, got %T from WithHTTPDoer", option, c.httpClient)
+	}
+	if !c.ownsClient {
+		copied := *client
+		client = &copied
+		c.httpClient = client
+		c.ownsClient = true
+	}
+	return client, nil
+}
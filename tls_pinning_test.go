@@ -0,0 +1,118 @@
+package shared
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testCert is a certificate and its key, for building TLS test chains.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert creates a certificate for name signed by parent, or a
+// self-signed CA if parent is nil.
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		tmpl.DNSNames = []string{name}
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// pinnedTLSServer starts a TLS server for "localhost" presenting leaf
+// followed by extra, and returns a client trusting root with pins applied.
+func pinnedTLSServer(t *testing.T, root, leaf *testCert, extra []*testCert, pins []string) *AbuseIPDBClient {
+	t.Helper()
+	chain := [][]byte{leaf.cert.Raw}
+	for _, c := range extra {
+		chain = append(chain, c.cert.Raw)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"ipAddress":"8.8.8.8"}}`))
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: chain, PrivateKey: leaf.key}}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: "localhost"}}
+
+	c, err := NewAbuseIPDBClientE("key", WithHTTPTransport(transport), WithTLSPinning(pins))
+	if err != nil {
+		t.Fatalf("NewAbuseIPDBClientE: %v", err)
+	}
+	c.baseURL = srv.URL + "/api/v2"
+	return c
+}
+
+func TestTLSPinningAcceptsPinnedChain(t *testing.T) {
+	root := newTestCert(t, "root", nil)
+	leaf := newTestCert(t, "localhost", root)
+
+	c := pinnedTLSServer(t, root, leaf, nil, []string{ExtractSPKIPin(root.cert)})
+	if _, err := c.CheckIP("8.8.8.8"); err != nil {
+		t.Fatalf("CheckIP with a pinned root: %v", err)
+	}
+}
+
+func TestTLSPinningRejectsAppendedPinnedCert(t *testing.T) {
+	attackerRoot := newTestCert(t, "attacker root", nil)
+	attackerLeaf := newTestCert(t, "localhost", attackerRoot)
+	pinned := newTestCert(t, "pinned root", nil)
+
+	// The attacker's chain verifies against a trusted root, and the pinned
+	// certificate is presented alongside it without being part of it.
+	c := pinnedTLSServer(t, attackerRoot, attackerLeaf, []*testCert{pinned}, []string{ExtractSPKIPin(pinned.cert)})
+	if _, err := c.CheckIP("8.8.8.8"); !errors.Is(err, ErrTLSPinMismatch) {
+		t.Fatalf("CheckIP error = %v, want ErrTLSPinMismatch", err)
+	}
+}
+
+func TestTLSPinningLeavesCallerTransport(t *testing.T) {
+	transport := &http.Transport{TLSClientConfig: &tls.Config{}}
+	if _, err := NewAbuseIPDBClientE("key", WithHTTPTransport(transport), WithTLSPinning([]string{"pin"})); err != nil {
+		t.Fatalf("NewAbuseIPDBClientE: %v", err)
+	}
+	if transport.TLSClientConfig.VerifyPeerCertificate != nil {
+		t.Error("pins were installed on the caller's transport")
+	}
+}
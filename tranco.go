@@ -0,0 +1,137 @@
+// File: shared/tranco.go
+package shared
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rank cut-offs and score bonuses for Tranco top-list membership
+const (
+	trancoTopRank      = 10_000
+	trancoTopBonus     = 20
+	trancoPopularRank  = 100_000
+	trancoPopularBonus = 5
+)
+
+// TrancoListChecker looks up domains in the Tranco top sites list
+// (https://tranco-list.eu). Domains ranked highly are rarely abuse-related.
+type TrancoListChecker struct {
+	ranks map[string]int
+}
+
+// NewTrancoListChecker parses a Tranco CSV ("rank,domain" per line) from r.
+func NewTrancoListChecker(r io.Reader) (*TrancoListChecker, error) {
+	ranks := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		rankField, domain, ok := strings.Cut(line, ",")
+		rank, err := strconv.Atoi(strings.TrimSpace(rankField))
+		if !ok || err != nil {
+			if lineNum == 1 {
+				continue // header row
+			}
+			return nil, fmt.Errorf("invalid Tranco list line %d: %q", lineNum, line)
+		}
+
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if _, exists := ranks[domain]; !exists {
+			ranks[domain] = rank
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Tranco list: %w", err)
+	}
+
+	return &TrancoListChecker{ranks: ranks}, nil
+}
+
+// LoadTrancoList reads a Tranco CSV file from path.
+func LoadTrancoList(path string) (*TrancoListChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Tranco list: %w", err)
+	}
+	defer file.Close()
+
+	return NewTrancoListChecker(file)
+}
+
+// GetRank returns the Tranco rank of domain, or of its closest listed parent
+// domain (so "mail.example.com" matches "example.com"). Zero means unlisted.
+func (t *TrancoListChecker) GetRank(domain string) int {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	for domain != "" {
+		if rank, ok := t.ranks[domain]; ok {
+			return rank
+		}
+		_, parent, found := strings.Cut(domain, ".")
+		if !found || !strings.Contains(parent, ".") {
+			break
+		}
+		domain = parent
+	}
+	return 0
+}
+
+// IsTopDomain reports whether domain appears in the list.
+func (t *TrancoListChecker) IsTopDomain(domain string) bool {
+	return t.GetRank(domain) > 0
+}
+
+// trancoScoreBonus returns the score bonus for a Tranco rank.
+func trancoScoreBonus(rank int) int {
+	switch {
+	case rank <= 0:
+		return 0
+	case rank <= trancoTopRank:
+		return trancoTopBonus
+	case rank <= trancoPopularRank:
+		return trancoPopularBonus
+	default:
+		return 0
+	}
+}
+
+// WithTrancoList loads the Tranco top sites CSV at path at construction time
+// and rewards listed domains with a score bonus: large for the top 10,000,
+// small for the top 100,000. The rank is stored in Metadata["tranco_rank"].
+// The check is off unless this option is given; a list that fails to load is
+// logged and skipped.
+func WithTrancoList(path string) EnhancedValidatorOption {
+	return func(v *EnhancedValidator) {
+		tranco, err := LoadTrancoList(path)
+		if err != nil {
+			log.Printf("Failed to load Tranco list: %v", err)
+			return
+		}
+		v.tranco = tranco
+	}
+}
+
+// applyTrancoRank records the domain's Tranco rank on result and adds its score bonus.
+func (v *EnhancedValidator) applyTrancoRank(domain string, result *Result) {
+	if v.tranco == nil {
+		return
+	}
+
+	rank := v.tranco.GetRank(domain)
+	if rank == 0 {
+		return
+	}
+	result.Metadata["tranco_rank"] = rank
+	result.Score = min(result.Score+trancoScoreBonus(rank), 100)
+}
@@ -2,6 +2,7 @@
 package shared
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -13,6 +14,15 @@ const (
 	StatusInvalid Status = "invalid"
 	StatusRisky   Status = "risky"
 	StatusUnknown Status = "unknown"
+	StatusError   Status = "error"
+
+	// StatusCatchAll means the server accepted the address but accepts every
+	// address, so the mailbox may not exist.
+	StatusCatchAll Status = "catch_all"
+
+	// StatusSuspicious means the address exists but its domain or mail
+	// servers look abusive, e.g. mail server IPs with a poor reputation.
+	StatusSuspicious Status = "suspicious"
 )
 
 // String returns the string representation of the status
@@ -25,20 +35,142 @@ type ValidationJob struct {
 	JobID     string    `json:"job_id"`
 	Email     string    `json:"email"`
 	Timestamp time.Time `json:"timestamp"`
+	NotBefore time.Time `json:"not_before,omitempty"` // earliest time the job may be processed
+	Attempts  int       `json:"attempts,omitempty"`   // number of retries already made
 }
 
 // Result represents the result of an email validation.
 type Result struct {
-	JobID    string                 `json:"job_id"`
-	Email    string                 `json:"email"`
-	Status   string                 `json:"status"` // "valid", "invalid", "risky", "unknown"
-	Reason   string                 `json:"reason"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	JobID    string                 `json:"job_id,omitempty" yaml:"job_id,omitempty"`
+	Email    string                 `json:"email" yaml:"email"`
+	Status   string                 `json:"status" yaml:"status"` // "valid", "invalid", "risky", "unknown"
+	Reason   string                 `json:"reason" yaml:"reason"`
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	SubStatus string        `json:"sub_status,omitempty" yaml:"sub_status,omitempty"` // finer-grained reason code, e.g. "FREE_TIER_PROVIDER"
+	Tags      []string      `json:"tags,omitempty" yaml:"tags,omitempty"`             // labels for downstream filtering, e.g. "free-provider"
+	Score     int           `json:"score,omitempty" yaml:"score,omitempty"`           // 0-100 deliverability score
+	Duration  time.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`     // time taken to validate
+	Timestamp time.Time     `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`   // when validation finished
+
+	WasGreylisted      bool `json:"was_greylisted,omitempty" yaml:"was_greylisted,omitempty"`
+	GraylistRetryCount int  `json:"graylist_retry_count,omitempty" yaml:"graylist_retry_count,omitempty"`
+}
+
+// MarshalJSON encodes the result in its compact form, leaving out a zero
+// Timestamp, Score or Duration, an empty JobID and empty Metadata or Tags.
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result // without these methods, to avoid recursion
+	out := struct {
+		plain
+		Timestamp *time.Time `json:"timestamp,omitempty"` // omitempty can't drop a zero time.Time
+	}{plain: plain(r)}
+	if !r.Timestamp.IsZero() {
+		out.Timestamp = &r.Timestamp
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts both the compact form written by MarshalJSON and the
+// full form with every field present. Omitted fields keep their zero values,
+// and empty Metadata or Tags decode as nil either way.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	type plain Result // without these methods, to avoid recursion
+	var in plain
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = Result(in)
+	if len(r.Metadata) == 0 {
+		r.Metadata = nil
+	}
+	if len(r.Tags) == 0 {
+		r.Tags = nil
+	}
+	return nil
+}
+
+// AddTag adds a tag to the result if it is not already present.
+func (r *Result) AddTag(tag string) {
+	for _, t := range r.Tags {
+		if t == tag {
+			return
+		}
+	}
+	r.Tags = append(r.Tags, tag)
+}
+
+// resultAddress returns the address a result was validated for: the bare
+// address parsed from display-name input (Metadata["parsed_address"]), or else Email.
+func resultAddress(r *Result) string {
+	if addr, ok := r.Metadata["parsed_address"].(string); ok {
+		return addr
+	}
+	return r.Email
+}
+
+// EmailRequest is one address of a batch request, with options overriding
+// the batch's DefaultOptions.
+type EmailRequest struct {
+	Email   string           `json:"email"`
+	Options *ValidateOptions `json:"options,omitempty"`
 }
 
 // BatchRequest represents a batch validation request.
 type BatchRequest struct {
-	Emails []string `json:"emails"`
+	Requests []EmailRequest `json:"requests"`
+
+	// DefaultOptions apply to every request without its own Options. Nil
+	// validates each address as-is, like ValidateEmail.
+	DefaultOptions *ValidateOptions `json:"default_options,omitempty"`
+}
+
+// BatchRequestFromStrings creates a batch request validating emails without options.
+func BatchRequestFromStrings(emails []string) *BatchRequest {
+	req := &BatchRequest{Requests: make([]EmailRequest, len(emails))}
+	for i, email := range emails {
+		req.Requests[i] = EmailRequest{Email: email}
+	}
+	return req
+}
+
+// UnmarshalJSON also accepts the older {"emails": [...]} form, which is
+// converted as by BatchRequestFromStrings.
+func (r *BatchRequest) UnmarshalJSON(data []byte) error {
+	type plain BatchRequest // without this method, to avoid recursion
+	var req struct {
+		plain
+		Emails []string `json:"emails"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	*r = BatchRequest(req.plain)
+	for _, email := range req.Emails {
+		r.Requests = append(r.Requests, EmailRequest{Email: email})
+	}
+	return nil
+}
+
+// Emails returns the addresses of all requests, in order.
+func (r *BatchRequest) Emails() []string {
+	emails := make([]string, len(r.Requests))
+	for i, req := range r.Requests {
+		emails[i] = req.Email
+	}
+	return emails
+}
+
+// optionsFor returns the options that apply to req.
+func (r *BatchRequest) optionsFor(req EmailRequest) ValidateOptions {
+	switch {
+	case req.Options != nil:
+		return *req.Options
+	case r.DefaultOptions != nil:
+		return *r.DefaultOptions
+	default:
+		return ValidateOptions{}
+	}
 }
 
 // BatchResponse represents a batch validation response.
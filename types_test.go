@@ -0,0 +1,92 @@
+package shared
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultJSONRoundTrip(t *testing.T) {
+	// The fields MarshalJSON leaves out when empty, with a value to populate
+	// each and its zero value as written in the full form.
+	optional := []struct {
+		key  string
+		set  func(*Result)
+		zero json.RawMessage
+	}{
+		{"job_id", func(r *Result) { r.JobID = "job-1" }, json.RawMessage(`""`)},
+		{"metadata", func(r *Result) { r.Metadata = map[string]interface{}{"request_id": "req-1"} }, json.RawMessage(`{}`)},
+		{"tags", func(r *Result) { r.Tags = []string{TagFreeProvider, "b2c"} }, json.RawMessage(`[]`)},
+		{"score", func(r *Result) { r.Score = 87 }, json.RawMessage(`0`)},
+		{"duration", func(r *Result) { r.Duration = 1500 * time.Millisecond }, json.RawMessage(`0`)},
+		{"timestamp", func(r *Result) { r.Timestamp = time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC) }, json.RawMessage(`"0001-01-01T00:00:00Z"`)},
+	}
+
+	// Every combination of populated and empty optional fields
+	for mask := 0; mask < 1<<len(optional); mask++ {
+		want := &Result{Email: "john@acme.io", Status: StatusValid.String(), Reason: "email appears valid"}
+		var populated, empty []string
+		for i, field := range optional {
+			if mask&(1<<i) != 0 {
+				field.set(want)
+				populated = append(populated, field.key)
+			} else {
+				empty = append(empty, field.key)
+			}
+		}
+
+		name := "empty"
+		if len(populated) > 0 {
+			name = "populated " + strings.Join(populated, "+")
+		}
+		t.Run(name, func(t *testing.T) {
+			compact, err := json.Marshal(want)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(compact, &fields); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range populated {
+				if _, ok := fields[key]; !ok {
+					t.Errorf("compact form %s is missing %q", compact, key)
+				}
+			}
+			for _, key := range empty {
+				if _, ok := fields[key]; ok {
+					t.Errorf("compact form %s has empty %q", compact, key)
+				}
+			}
+
+			var got Result
+			if err := json.Unmarshal(compact, &got); err != nil {
+				t.Fatalf("Unmarshal compact: %v", err)
+			}
+			if !reflect.DeepEqual(&got, want) {
+				t.Errorf("compact round trip = %+v, want %+v", got, want)
+			}
+
+			// The full form spells out the empty fields as zero values
+			for i, field := range optional {
+				if mask&(1<<i) == 0 {
+					fields[field.key] = field.zero
+				}
+			}
+			full, err := json.Marshal(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = Result{}
+			if err := json.Unmarshal(full, &got); err != nil {
+				t.Fatalf("Unmarshal full: %v", err)
+			}
+			if !reflect.DeepEqual(&got, want) {
+				t.Errorf("full form %s decoded to %+v, want %+v", full, got, want)
+			}
+		})
+	}
+}
@@ -2,51 +2,191 @@
 package shared
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Validator handles email validation logic.
 type Validator struct {
 	emailRegex *regexp.Regexp
+	config     ValidatorConfig
+
+	resolver   DNSResolver
+	smtpDialer SMTPDialer
+	httpClient HTTPDoer
+	disposable DisposableProvider
+	now        func() time.Time
+
+	domainInfo *domainInfoCache
+	smtpCache  *SMTPCache
+	platforms  *PlatformDetector
+	limiter    *concurrencyLimiter
+
+	suspiciousLocal []SuspiciousPattern
+}
+
+// ValidatorOption configures a Validator.
+type ValidatorOption func(*Validator)
+
+// WithDisposableProvider checks domains with p instead of the configured
+// disposable domain list. If p returns an error, the check is logged and the
+// domain is treated as not disposable.
+func WithDisposableProvider(p DisposableProvider) ValidatorOption {
+	return func(v *Validator) {
+		v.disposable = p
+	}
+}
+
+// WithDNSResolver sends every DNS lookup through r, e.g. one created by
+// NewDNSResolverFromAddr. A nil r uses net.DefaultResolver.
+func WithDNSResolver(r *net.Resolver) ValidatorOption {
+	return func(v *Validator) {
+		if r == nil {
+			r = net.DefaultResolver
+		}
+		v.resolver = r
+	}
 }
 
-// NewValidator creates a new validator instance.
-func NewValidator() *Validator {
-	// RFC 5322 compliant email regex (simplified version)
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+// NewValidator creates a validator using cfg, e.g. DefaultValidatorConfig()
+// with some fields changed. It returns an error wrapping ErrInvalidConfig if
+// cfg fails Validate.
+func NewValidator(cfg ValidatorConfig, opts ...ValidatorOption) (*Validator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return newValidator(cfg, opts...), nil
+}
 
-	return &Validator{
-		emailRegex: emailRegex,
+// NewValidatorNoBuiltinLists creates a validator without the built-in
+// disposable and free provider domain lists, for callers that supply their own
+// through DisposableDomains and FreeProviderDomains in the configuration.
+func NewValidatorNoBuiltinLists() *Validator {
+	cfg := DefaultValidatorConfig()
+	cfg.DisposableDomains = map[string]bool{}
+	cfg.FreeProviderDomains = map[string]bool{}
+	return newValidator(cfg)
+}
+
+// newValidator creates a validator from a configuration known to be valid.
+func newValidator(cfg ValidatorConfig, opts ...ValidatorOption) *Validator {
+	v := DefaultValidatorFactory().Build(cfg)
+	for _, opt := range opts {
+		opt(v)
 	}
+	return v
 }
 
-// ValidateEmail validates an email address and returns the result.
+// ValidateEmail validates an email address and returns the result, bounded
+// by ValidatorConfig.ValidationTimeout when set.
+// The result's "request_id" metadata correlates its log messages and errors.
+// When several advisory checks match a valid address, SubStatus is the first
+// of SubStatusMailingList, SubStatusSuspiciousLocalPart and
+// SubStatusFreeTierProvider, and Tags records every match.
 func (v *Validator) ValidateEmail(email string) *Result {
+	if v.config.ValidationTimeout > 0 {
+		return v.ValidateEmailWithTimeout(context.Background(), email, v.config.ValidationTimeout)
+	}
+	return v.validateEmail(context.Background(), email, true)
+}
+
+// ValidateEmailContext validates an email address, cancelling every DNS and
+// SMTP operation as soon as ctx is done. When ctx has a deadline, the DNS
+// checks get ValidatorConfig.DNSTimeoutFraction of the remaining time, and
+// each SMTP conversation is capped at SMTPTimeout and the deadline. A deadline
+// of about 2×SMTPTimeout plus one second for DNS is recommended, leaving time
+// for a fallback MX server.
+func (v *Validator) ValidateEmailContext(ctx context.Context, email string) *Result {
+	return v.validateEmail(ctx, email, true)
+}
+
+// ValidateEmailWithTimeout validates an email address, bounding the whole
+// validation (DNS and SMTP) by timeout. If the deadline is exceeded the result
+// has status "error" and reason "validation timeout".
+//
+// A timeout shorter than the SMTP handshake time produces "error" results;
+// these mean the check didn't finish, not that the address is invalid.
+func (v *Validator) ValidateEmailWithTimeout(ctx context.Context, email string, timeout time.Duration) *Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return v.validateEmail(ctx, email, true)
+}
+
+// advisorySubStatuses are the sub-statuses of checks that don't change the
+// status, highest priority first. When several match, SubStatus keeps the
+// highest and Tags records all of them.
+var advisorySubStatuses = []string{SubStatusMailingList, SubStatusSuspiciousLocalPart, SubStatusFreeTierProvider}
+
+// setAdvisorySubStatus sets result.SubStatus to subStatus unless an advisory
+// sub-status of higher priority is already set.
+func setAdvisorySubStatus(result *Result, subStatus string) {
+	current := slices.Index(advisorySubStatuses, result.SubStatus)
+	if current < 0 || slices.Index(advisorySubStatuses, subStatus) < current {
+		result.SubStatus = subStatus
+	}
+}
+
+// validateEmail runs the validation steps, stopping early if ctx's deadline is exceeded.
+// SMTP probing is skipped unless withSMTP is set and SMTP is enabled in the config.
+func (v *Validator) validateEmail(ctx context.Context, email string, withSMTP bool) *Result {
+	start := v.now()
+	ctx, requestID := ensureRequestID(ctx)
 	result := &Result{
 		Email:    email,
-		Metadata: make(map[string]interface{}),
+		Metadata: map[string]interface{}{"request_id": requestID},
+	}
+	defer func() {
+		result.Timestamp = v.now()
+		result.Duration = result.Timestamp.Sub(start)
+	}()
+
+	// Wait for capacity under MaxConcurrentValidations
+	if err := v.limiter.acquire(ctx); err != nil {
+		if !timedOut(ctx, result) {
+			result.Status = StatusError.String()
+			result.Reason = "validation cancelled"
+		}
+		return result
 	}
+	defer v.limiter.release()
 
 	// Step 1: Basic format validation
-	if !v.emailRegex.MatchString(email) {
+	if HasHeaderInjection(email) {
+		result.Status = "invalid"
+		result.Reason = "email contains header injection characters"
+		return result
+	}
+	addr, ok := v.checkFormat(email)
+	if !ok {
 		result.Status = "invalid"
 		result.Reason = "invalid email format"
 		return result
 	}
+	if addr != email {
+		result.Metadata["parsed_address"] = addr
+		email = addr
+	}
 
-	// Step 2: Extract domain
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
+	// Step 2: Extract domain, splitting on the last @ since a quoted local part may contain one
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
 		result.Status = "invalid"
 		result.Reason = "invalid email format"
 		return result
 	}
 
-	domain := parts[1]
-	localPart := parts[0]
+	domain := email[at+1:]
+	localPart := email[:at]
 
 	// Step 3: Basic local part validation
 	if len(localPart) == 0 || len(localPart) > 64 {
@@ -62,18 +202,126 @@ func (v *Validator) ValidateEmail(email string) *Result {
 		return result
 	}
 
+	// Offensive local parts, if the caller opted in with a wordlist
+	if v.config.EnableProfanityCheck && ContainsProfanity(localPart, v.config.ProfanityWordlist) {
+		result.Status = "invalid"
+		result.Reason = "local part contains offensive content"
+		result.SubStatus = SubStatusOffensiveLocalPart
+		return result
+	}
+
+	// Soft syntax issues (advisory only, doesn't change status)
+	if warnings := syntaxWarnings(email); len(warnings) > 0 {
+		result.Metadata["syntax_warnings"] = syntaxWarningCodes(warnings)
+	}
+
+	// Step 4b: IDNA validation
+	normalized, err := NormalizeDomain(domain, v.config.IDNA)
+	if err != nil {
+		result.Status = "invalid"
+		result.Reason = err.Error()
+		return result
+	}
+	domain = normalized
+
 	// Step 5: DNS validation
-	validationDetails := v.validateDomain(domain)
-	for k, v := range validationDetails.metadata {
-		result.Metadata[k] = v
+	dnsCtx, cancel := v.dnsContext(ctx)
+	validationDetails := v.validateDomain(dnsCtx, domain)
+	cancel()
+	if timedOut(ctx, result) {
+		return result
+	}
+	if validationDetails.info == nil && validationDetails.subStatus == "" {
+		result.Status = StatusError.String()
+		result.Reason = validationDetails.reason
+		return result
+	}
+	result.MergeMetadata(validationDetails.metadata)
+	for _, tag := range validationDetails.tags {
+		result.AddTag(tag)
 	}
 
 	if !validationDetails.valid {
 		result.Status = "invalid"
 		result.Reason = validationDetails.reason
+		result.SubStatus = validationDetails.subStatus
 		return result
 	}
 
+	// Free-tier provider detection (advisory only, doesn't change status)
+	freeProviders := v.config.FreeProviderDomains
+	if freeProviders == nil {
+		freeProviders = DefaultFreeProviderDomains
+	}
+	if isFreeTierProvider(domain, freeProviders) {
+		result.Metadata["is_free_provider"] = true
+		setAdvisorySubStatus(result, SubStatusFreeTierProvider)
+		result.AddTag(TagFreeProvider)
+	}
+
+	// Role account detection (advisory only, doesn't change status)
+	if IsRoleBased(localPart, DefaultRoleBasedAccounts) {
+		result.Metadata["is_role_based"] = true
+	}
+
+	// Suspicious local part detection (advisory only, doesn't change status)
+	if suspicious, pattern := DetectSuspiciousLocalPart(localPart, v.suspiciousLocal); suspicious {
+		result.Metadata["suspicious_local_part"] = pattern
+		setAdvisorySubStatus(result, SubStatusSuspiciousLocalPart)
+		result.AddTag(TagSuspiciousLocalPart)
+	}
+
+	// Local part signals for scoring (advisory only, doesn't change status)
+	result.Metadata["local_part_entropy"] = LocalPartEntropy(email)
+	result.Metadata["numeric_only_local"] = IsNumericOnlyLocalPart(email)
+	if v.config.EnableEntropyCheck {
+		result.Metadata["bot_score"] = v.botScore(localPart)
+	}
+
+	// Generated address pattern (advisory only, doesn't change status)
+	result.Metadata["email_pattern"] = DetectEmailPattern(email)
+
+	// Mailing list detection (advisory only, doesn't change status)
+	if IsMailingListAddress(email, validationDetails.info.MXRecords) {
+		result.Metadata["is_mailing_list"] = true
+		setAdvisorySubStatus(result, SubStatusMailingList)
+		result.AddTag(TagMailingList)
+	}
+
+	// Step 6: SMTP mailbox verification (only when enabled)
+	if withSMTP && v.config.SMTPTimeout > 0 {
+		mxRecords := validationDetails.info.MXRecords
+		smtpCfg := v.config.SMTP
+		probe := true
+
+		// Known providers short-circuit or adjust the probe
+		if name, behavior, ok := v.smtpBehaviorDB().Match(mxRecords); ok {
+			var decided bool
+			probe, decided = applySMTPBehavior(result, name, behavior, &smtpCfg)
+			if decided {
+				return result
+			}
+		}
+
+		// Mailboxes on a known catch-all domain all look valid, so don't probe again
+		if probe && v.applyCachedCatchAll(result, domain) {
+			return result
+		}
+
+		if probe {
+			smtpResult := checkSMTP(ctx, v.smtpDialer, email, mxRecords, v.config.SMTPTimeout, smtpCfg)
+			if timedOut(ctx, result) {
+				return result
+			}
+			if applySMTPResult(result, smtpResult, v.platforms.Detect(mxRecords)) {
+				if Status(result.Status) == StatusCatchAll {
+					v.markCatchAll(domain)
+				}
+				return result
+			}
+		}
+	}
+
 	// If all checks pass
 	result.Status = "valid"
 	result.Reason = "email appears valid"
@@ -81,43 +329,129 @@ func (v *Validator) ValidateEmail(email string) *Result {
 	return result
 }
 
+// dnsContext derives the context for the DNS checks, limited to
+// DNSTimeoutFraction of the time remaining before ctx's deadline.
+func (v *Validator) dnsContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	fraction := v.config.DNSTimeoutFraction
+	if !ok || fraction <= 0 || fraction >= 1 {
+		return context.WithCancel(ctx)
+	}
+
+	remaining := time.Until(deadline)
+	return context.WithTimeout(ctx, time.Duration(float64(remaining)*fraction))
+}
+
+// applySMTPResult records an SMTP check on result, accounting for quirks of
+// the hosting platform. It returns true if the SMTP result decided the final status.
+func applySMTPResult(result *Result, smtpResult SMTPResult, platform Platform) bool {
+	result.Metadata["smtp_code"] = smtpResult.Code
+	result.Metadata["smtp_confidence"] = smtpResult.Confidence
+	if platform != PlatformUnknown {
+		result.Metadata["platform"] = string(platform)
+	}
+
+	if smtpResult.Status != StatusValid {
+		result.Status = smtpResult.Status.String()
+		result.Reason = smtpResult.Reason
+		result.WasGreylisted = smtpResult.Greylisted
+		return true
+	}
+
+	// Microsoft 365 accepts every recipient in a tenant's domains at RCPT time
+	if platform == PlatformMicrosoft365 {
+		result.Status = StatusCatchAll.String()
+		result.Reason = "Microsoft 365 accepts all recipients during SMTP"
+		result.Metadata["is_catch_all"] = true
+		result.Metadata["smtp_confidence"] = adjustConfidence(smtpResult.Confidence, confidenceCatchAll)
+		result.Metadata["recommendation"] = microsoft365Recommendation
+		return true
+	}
+
+	return false
+}
+
+// timedOut marks result as a timeout error if ctx's deadline has been exceeded.
+func timedOut(ctx context.Context, result *Result) bool {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	result.Status = StatusError.String()
+	result.Reason = "validation timeout"
+	return true
+}
+
 // domainValidationResult holds domain validation results
 type domainValidationResult struct {
-	valid    bool
-	reason   string
-	metadata map[string]interface{}
+	valid     bool
+	reason    string
+	subStatus string
+	metadata  map[string]interface{}
+	tags      []string
+	info      *DomainInfo // nil if the lookup was interrupted or skipped
 }
 
 // validateDomain performs DNS-based domain validation
-func (v *Validator) validateDomain(domain string) domainValidationResult {
+func (v *Validator) validateDomain(ctx context.Context, domain string) domainValidationResult {
 	metadata := make(map[string]interface{})
 
-	// Check if domain resolves
-	_, err := net.LookupHost(domain)
+	// Educational/government classification (no network required)
+	tags := classifyEduGovDomain(domain, v.config.EduGovTLDs)
+
+	// Reserved labels can't be registered, so there is nothing to look up
+	if HasInvalidLabelHyphens(domain) {
+		return domainValidationResult{
+			valid:     false,
+			reason:    "domain has a reserved label",
+			subStatus: SubStatusInvalidDomainLabel,
+			metadata:  metadata,
+			tags:      tags,
+		}
+	}
+
+	info, err := v.LookupDomainInfo(ctx, domain)
 	if err != nil {
+		return domainValidationResult{
+			valid:    false,
+			reason:   "domain lookup interrupted",
+			metadata: metadata,
+			tags:     tags,
+		}
+	}
+
+	// Check if domain resolves
+	if !info.Resolves() {
 		return domainValidationResult{
 			valid:    false,
 			reason:   "domain does not resolve",
 			metadata: metadata,
+			tags:     tags,
+			info:     info,
 		}
 	}
 	metadata["domain_resolves"] = true
 
-	// Check for MX records
-	mxRecords, err := net.LookupMX(domain)
-	if err != nil {
+	// A null MX is an explicit refusal to accept email (RFC 7505)
+	if info.HasNullMX {
 		return domainValidationResult{
 			valid:    false,
-			reason:   "no MX records found",
+			reason:   "domain does not accept email (null MX)",
 			metadata: metadata,
+			tags:     tags,
+			info:     info,
 		}
 	}
 
+	// Check for MX records
+	mxRecords := info.MXRecords
 	if len(mxRecords) == 0 {
 		return domainValidationResult{
 			valid:    false,
 			reason:   "no MX records found",
 			metadata: metadata,
+			tags:     tags,
+			info:     info,
 		}
 	}
 
@@ -130,53 +464,110 @@ func (v *Validator) validateDomain(domain string) domainValidationResult {
 	metadata["mx_count"] = len(mxRecords)
 
 	// Additional checks for suspicious patterns
-	suspiciousPatterns := []string{
-		"temp", "temporary", "disposable", "throwaway", "fake",
-		"test", "example", "invalid", "localhost",
-	}
-
-	for _, pattern := range suspiciousPatterns {
-		if strings.Contains(strings.ToLower(domain), pattern) {
-			return domainValidationResult{
-				valid:    false,
-				reason:   fmt.Sprintf("domain contains suspicious pattern: %s", pattern),
-				metadata: metadata,
-			}
+	if pattern := suspiciousDomainPattern(domain); pattern != "" {
+		return domainValidationResult{
+			valid:    false,
+			reason:   fmt.Sprintf("domain contains suspicious pattern: %s", pattern),
+			metadata: metadata,
+			tags:     tags,
+			info:     info,
 		}
 	}
 
 	// Check for common disposable email domains
-	disposableDomains := []string{
-		"10minutemail.com", "guerrillamail.com", "mailinator.com",
-		"tempmail.org", "throwaway.email", "yopmail.com",
-		"temp-mail.org", "getairmail.com", "sharklasers.com",
-	}
-
-	for _, disposable := range disposableDomains {
-		if strings.ToLower(domain) == disposable {
-			return domainValidationResult{
-				valid:    false,
-				reason:   "disposable email domain detected",
-				metadata: metadata,
-			}
+	isDisposable, err := v.disposable.IsDisposable(ctx, strings.ToLower(domain))
+	if err != nil {
+		// Provider failures shouldn't fail the validation
+		log.Printf("%sDisposable domain check failed for %s: %v", requestIDPrefix(ctx), domain, err)
+	}
+	if isDisposable {
+		metadata["is_disposable"] = true
+		return domainValidationResult{
+			valid:    false,
+			reason:   "disposable email domain detected",
+			metadata: metadata,
+			tags:     tags,
+			info:     info,
 		}
 	}
 
+	// Mobile carrier SMS gateways are deliverable but not personal mailboxes
+	carriers := v.config.CarrierDomains
+	if carriers == nil {
+		carriers = DefaultCarrierDomains
+	}
+	if carrier, ok := carrierForDomain(domain, carriers); ok {
+		metadata["carrier"] = carrier
+		tags = append(tags, TagSMSGateway)
+	}
+
 	// All checks passed
 	return domainValidationResult{
 		valid:    true,
 		reason:   "domain validation passed",
 		metadata: metadata,
+		tags:     tags,
+		info:     info,
+	}
+}
+
+// suspiciousPatterns are substrings that mark a domain as likely fake or temporary
+var suspiciousPatterns = []string{
+	"temp", "temporary", "disposable", "throwaway", "fake",
+	"test", "example", "invalid", "localhost",
+}
+
+// suspiciousDomainPattern returns the first suspicious pattern found in domain, or ""
+func suspiciousDomainPattern(domain string) string {
+	domain = strings.ToLower(domain)
+	for _, pattern := range suspiciousPatterns {
+		if strings.Contains(domain, pattern) {
+			return pattern
+		}
 	}
+	return ""
 }
 
-// ValidateBatch validates multiple emails and returns results
+// ValidateBatch validates multiple emails concurrently and returns results in
+// the same order, bounded by MaxConcurrentValidations. When
+// CoalesceSMTPPerDomain is set, SMTP checks are shared between addresses on the same domain.
 func (v *Validator) ValidateBatch(emails []string) []*Result {
+	if v.config.CoalesceSMTPPerDomain && v.config.SMTPTimeout > 0 {
+		return NewDomainCoalescer(v).ValidateBatch(context.Background(), emails)
+	}
+
 	results := make([]*Result, len(emails))
 
+	// Validations share the validator-wide limit, so at most
+	// MaxConcurrentValidations workers are useful per batch.
+	var g errgroup.Group
+	g.SetLimit(max(1, min(len(emails), v.limiter.max)))
 	for i, email := range emails {
-		results[i] = v.ValidateEmail(email)
+		g.Go(func() error {
+			results[i] = v.ValidateEmail(email)
+			return nil
+		})
+	}
+	g.Wait()
+
+	return results
+}
+
+// ValidateBatchRequest validates the addresses of req concurrently like
+// ValidateBatch, applying each request's options or else req.DefaultOptions.
+// Results are in request order. SMTP checks are not coalesced per domain.
+func (v *Validator) ValidateBatchRequest(ctx context.Context, req *BatchRequest) []*Result {
+	results := make([]*Result, len(req.Requests))
+
+	var g errgroup.Group
+	g.SetLimit(max(1, min(len(req.Requests), v.limiter.max)))
+	for i, emailReq := range req.Requests {
+		g.Go(func() error {
+			results[i] = v.ValidateEmailWithOptions(ctx, emailReq.Email, req.optionsFor(emailReq))
+			return nil
+		})
 	}
+	g.Wait()
 
 	return results
 }
@@ -190,7 +581,8 @@ func (v *Validator) GetValidatorStats() map[string]interface{} {
 			"domain_resolution",
 			"mx_record_check",
 			"disposable_domain_detection",
+			"free_provider_detection",
 		},
-		"version": "1.0.0",
+		"version": Version,
 	}
 }
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAdvisorySubStatusPrecedence(t *testing.T) {
+	tests := []struct {
+		email         string
+		wantSubStatus string
+		wantTags      []string
+	}{
+		{"alice@acme.io", SubStatusFreeTierProvider, []string{TagFreeProvider}},
+		{"1234567890@acme.io", SubStatusSuspiciousLocalPart, []string{TagFreeProvider, TagSuspiciousLocalPart}},
+		{"mailman@acme.io", SubStatusMailingList, []string{TagFreeProvider, TagMailingList}},
+	}
+
+	cfg := DefaultValidatorConfig()
+	cfg.FreeProviderDomains = map[string]bool{"acme.io": true}
+	v := (&ValidatorFactory{DNSResolver: acmeOnlyResolver{}}).Build(cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.ValidateEmail(tt.email)
+			if Status(result.Status) != StatusValid {
+				t.Fatalf("status = %s (%s), want valid", result.Status, result.Reason)
+			}
+			if result.SubStatus != tt.wantSubStatus {
+				t.Errorf("SubStatus = %q, want %q", result.SubStatus, tt.wantSubStatus)
+			}
+			for _, tag := range tt.wantTags {
+				if !slices.Contains(result.Tags, tag) {
+					t.Errorf("Tags = %v, missing %q", result.Tags, tag)
+				}
+			}
+		})
+	}
+}
+
+func TestSetAdvisorySubStatusIgnoresOrder(t *testing.T) {
+	for _, order := range [][]string{
+		{SubStatusFreeTierProvider, SubStatusSuspiciousLocalPart, SubStatusMailingList},
+		{SubStatusMailingList, SubStatusSuspiciousLocalPart, SubStatusFreeTierProvider},
+		{SubStatusSuspiciousLocalPart, SubStatusMailingList, SubStatusFreeTierProvider},
+	} {
+		result := &Result{}
+		for _, subStatus := range order {
+			setAdvisorySubStatus(result, subStatus)
+		}
+		if result.SubStatus != SubStatusMailingList {
+			t.Errorf("after %v SubStatus = %q, want %q", order, result.SubStatus, SubStatusMailingList)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// File: shared/version.go
+package shared
+
+// Version is the release of this package, reported in validator stats and
+// the default AbuseIPDB User-Agent.
+const Version = "1.0.0"